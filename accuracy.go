@@ -0,0 +1,48 @@
+package sunevent
+
+import (
+	"math"
+	"time"
+)
+
+// maxAccuracy bounds the estimate of Event.Accuracy where the Sun grazes
+// the altitude of the event, which may then not happen at all.
+const maxAccuracy = time.Hour
+
+// accuracy estimates the error of the event e computed at t with o. The
+// largest error of the algorithm inside its envelope, see AlgorithmInfo,
+// holds where the Sun crosses the altitude of the event at least half as
+// fast as it can; a position error takes proportionally longer to cross
+// where the Sun moves more slowly, as near the polar circles. It is 0,
+// unknown, for algorithms from outside the package.
+//
+// rate = cos(latitude) * cos(dec) * sin(H) / cos(altitude)
+func (o Options) accuracy(e EventType, t time.Time, latitude, longitude float64) time.Duration {
+	info, ok := algorithmInfo(o.algorithm())
+	if !ok {
+		return 0
+	}
+	base := info.MaxError
+	if o.Precision == High && o.algorithm() == Almanac {
+		// without the error of the fixed guess, see High
+		base = 5 * time.Second
+	}
+	if e == SolarNoon {
+		return base
+	}
+
+	altitude, _ := e.Altitude()
+	if e == Sunrise || e == Sunset {
+		altitude = o.sunriseAltitude()
+	}
+	H := o.hourAngle(t, o.longitude(longitude))
+	rate := math.Abs(degreeCos(latitude) * degreeCos(SolarDeclination(t)) * degreeSin(H) / degreeCos(altitude))
+
+	if rate >= 0.5 {
+		return base
+	}
+	if a := float64(base) * 0.5 / rate; a < float64(maxAccuracy) {
+		return time.Duration(a).Round(time.Second)
+	}
+	return maxAccuracy
+}
@@ -0,0 +1,51 @@
+package sunevent
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEventAccuracy(t *testing.T) {
+	date := time.Date(2026, time.June, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		e        EventType
+		latitude float64
+		opts     []Option
+		min, max time.Duration
+	}{
+		// the Sun rises steeply in the tropics
+		{"tropics", Sunrise, 10, nil, 30 * time.Second, 30 * time.Second},
+		{"solar noon", SolarNoon, 66, nil, 30 * time.Second, 30 * time.Second},
+		{"NOAA", Sunrise, 10, []Option{WithAlgorithm(NOAA)}, time.Minute, time.Minute},
+		{"high precision", Sunrise, 10, []Option{WithPrecision(High)}, 5 * time.Second, 5 * time.Second},
+		// and slowly near the polar circle
+		{"polar circle", Sunrise, 65, nil, 45 * time.Second, maxAccuracy},
+		{"astronomical dawn", AstronomicalDawn, 48, nil, 45 * time.Second, maxAccuracy},
+	}
+	for _, tt := range tests {
+		ev, err := EventOn(tt.e, date, tt.latitude, 0, tt.opts...)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if ev.Accuracy < tt.min || ev.Accuracy > tt.max {
+			t.Errorf("%s: Accuracy = %s, want %s to %s", tt.name, ev.Accuracy, tt.min, tt.max)
+		}
+	}
+
+	// a substitute chosen by the polar fallback is not an estimate
+	ev, err := EventOn(Sunset, date, 78, 15, WithPolarFallback())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ev.Approximate || ev.Accuracy != 0 {
+		t.Errorf("polar fallback: Approximate %v, Accuracy %s, want true, 0", ev.Approximate, ev.Accuracy)
+	}
+
+	for _, ev := range EventsBetween(40, 0, date, date.Add(24*time.Hour)) {
+		if ev.Accuracy <= 0 {
+			t.Errorf("EventsBetween: %s at %s without Accuracy", ev.Type, ev.Time)
+		}
+	}
+}
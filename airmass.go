@@ -0,0 +1,27 @@
+package sunevent
+
+import (
+	"math"
+	"time"
+)
+
+// AirMass returns the relative optical air mass along the line of sight
+// to the Sun at the given instant: 1 with the Sun at the zenith, about 38
+// at the horizon. It uses the Kasten-Young formula on the apparent zenith
+// angle, so refraction is always applied. It returns +Inf when the Sun is
+// below the horizon.
+//
+// AM = 1 / (cos(z) + 0.50572 * (96.07995 - z)^-1.6364)
+func AirMass(t time.Time, latitude, longitude float64, opts ...Option) float64 {
+	o := newOptions(opts)
+	longitude = o.longitude(longitude)
+
+	_, elevation := o.sunAt(t, latitude, longitude)
+	elevation += o.refraction(elevation)
+	if elevation < 0 {
+		return math.Inf(1)
+	}
+
+	z := 90.0 - elevation
+	return 1.0 / (degreeCos(z) + 0.50572*math.Pow(96.07995-z, -1.6364))
+}
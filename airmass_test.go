@@ -0,0 +1,42 @@
+package sunevent
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestAirMass(t *testing.T) {
+	date := time.Date(2026, time.March, 20, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		latitude, longitude float64
+		want                float64
+	}{
+		// the Sun near the zenith at the equator at the equinox
+		{0, 7, 1.0},
+		// and at an elevation of about 45°, where AM is about √2
+		{45, 7, math.Sqrt2},
+		{60, 7, 2.0},
+	}
+	for _, tt := range tests {
+		noon, err := SolarNoonOn(date, tt.latitude, tt.longitude)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := AirMass(noon, tt.latitude, tt.longitude); !near(got, tt.want, 0.02) {
+			t.Errorf("AirMass at noon at latitude %v = %v, want %v", tt.latitude, got, tt.want)
+		}
+	}
+
+	// the air mass grows quickly towards sunset and is infinite at night
+	set, err := SunSetOn(date, 45, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := AirMass(set.Add(-10*time.Minute), 45, 7); got < 15 || got > 38 {
+		t.Errorf("AirMass before sunset = %v", got)
+	}
+	if got := AirMass(set.Add(time.Hour), 45, 7); !math.IsInf(got, 1) {
+		t.Errorf("AirMass at night = %v, want +Inf", got)
+	}
+}
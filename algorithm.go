@@ -0,0 +1,183 @@
+package sunevent
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Algorithm computes the times at which the Sun crosses a zenith angle.
+// The package ships Almanac and NOAA; other implementations, such as a
+// high-precision ephemeris, can be selected with WithAlgorithm.
+type Algorithm interface {
+	// SunTimes returns the times on the civil date of date, in the time
+	// zone of date, when the centre of the Sun rises above and sets below
+	// zenith degrees at the location, with longitude in degrees east.
+	// A crossing that does not happen that day is the zero time, and err
+	// is then ErrSunNeverRises if the Sun stays below the zenith angle or
+	// ErrSunNeverSets if it stays above.
+	SunTimes(date time.Time, latitude, longitude, zenith float64) (rise, set time.Time, err error)
+}
+
+var (
+	// Almanac is the single-pass method of the 1990 Almanac for Computers
+	// with solar coordinates in Julian centuries. It is fast; evaluating
+	// the Sun at a fixed guess of the time costs up to half a minute
+	// within 60° of the equator and minutes beyond. This is the default.
+	Almanac Algorithm = almanacAlgorithm{}
+	// NOAA uses the equations of the NOAA solar calculator, iterated at
+	// the time of the event, for errors within a minute. They are
+	// evaluated in terrestrial time; see DeltaT.
+	NOAA Algorithm = noaaAlgorithm{}
+)
+
+type almanacAlgorithm struct{}
+
+// SunTimes implements Algorithm with sunRiseSet.
+func (almanacAlgorithm) SunTimes(date time.Time, latitude, longitude, zenith float64) (rise, set time.Time, err error) {
+	return sunTimes(func(rising bool) (time.Time, error) {
+		return sunRiseSet(date, rising, latitude, longitude, zenith, false)
+	})
+}
+
+func (almanacAlgorithm) String() string {
+	return almanac.Name
+}
+
+type noaaAlgorithm struct{}
+
+// SunTimes implements Algorithm with noaaRiseSet.
+func (noaaAlgorithm) SunTimes(date time.Time, latitude, longitude, zenith float64) (rise, set time.Time, err error) {
+	return sunTimes(func(rising bool) (time.Time, error) {
+		return Options{}.noaaRiseSet(date, rising, latitude, longitude, zenith)
+	})
+}
+
+func (noaaAlgorithm) String() string {
+	return noaa.Name
+}
+
+// sunTimes combines the rising and setting crossings computed by event
+// into the results of SunTimes.
+func sunTimes(event func(rising bool) (time.Time, error)) (rise, set time.Time, err error) {
+	rise, errRise := event(true)
+	set, errSet := event(false)
+	if errRise != nil {
+		return rise, set, errRise
+	}
+	return rise, set, errSet
+}
+
+// algorithm returns the algorithm of o, Almanac if none was selected.
+func (o Options) algorithm() Algorithm {
+	if o.Algorithm == nil {
+		return Almanac
+	}
+	return o.Algorithm
+}
+
+// algorithmInfo returns the metadata of a, and false for an algorithm not
+// provided by the package.
+func algorithmInfo(a Algorithm) (AlgorithmInfo, bool) {
+	switch a {
+	case Almanac:
+		return almanac, true
+	case NOAA:
+		return noaa, true
+	}
+	return AlgorithmInfo{}, false
+}
+
+// AlgorithmInfo describes the range over which an algorithm has been
+// validated and its expected accuracy there.
+type AlgorithmInfo struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	MinYear     int     `json:"min_year"`
+	MaxYear     int     `json:"max_year"`
+	MaxLatitude float64 `json:"max_latitude"`
+
+	// MaxError is the largest error of event times inside the envelope.
+	MaxError time.Duration `json:"max_error_ns"`
+}
+
+// almanac is the single-pass algorithm of the 1990 Almanac for Computers
+// implemented by sunRiseSet.
+var almanac = AlgorithmInfo{
+	Name:        "almanac",
+	Description: "Almanac for Computers (1990) single-pass approximation",
+	MinYear:     1800,
+	MaxYear:     2100,
+	MaxLatitude: 60.0,
+	MaxError:    30 * time.Second,
+}
+
+// noaa is the NOAA solar calculator implemented by noaaRiseSet. NOAA
+// states the equations are accurate to a minute for years 1800 to 2100 and
+// latitudes within 72°.
+var noaa = AlgorithmInfo{
+	Name:        "noaa",
+	Description: "NOAA solar calculator, iterated at the event time",
+	MinYear:     1800,
+	MaxYear:     2100,
+	MaxLatitude: 72.0,
+	MaxError:    time.Minute,
+}
+
+// builtinAlgorithms are the algorithms provided by the package.
+var builtinAlgorithms = []Algorithm{Almanac, NOAA}
+
+// Algorithms returns the metadata of the algorithms provided by the
+// package: Almanac and NOAA.
+func Algorithms() []AlgorithmInfo {
+	var infos []AlgorithmInfo
+	for _, a := range builtinAlgorithms {
+		info, _ := algorithmInfo(a)
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// algorithmByName returns the algorithm whose AlgorithmInfo.Name is name.
+func algorithmByName(name string) (Algorithm, bool) {
+	for _, a := range builtinAlgorithms {
+		if info, _ := algorithmInfo(a); info.Name == name {
+			return a, true
+		}
+	}
+	return nil, false
+}
+
+// EnvelopeWarning reports a query outside the validated envelope of an
+// algorithm. The result is still computed but may be less accurate than
+// AlgorithmInfo.MaxError.
+type EnvelopeWarning struct {
+	Algorithm string
+	Reason    string
+}
+
+func (w *EnvelopeWarning) Error() string {
+	return fmt.Sprintf("sunevent: %s algorithm outside validated range: %s", w.Algorithm, w.Reason)
+}
+
+// CheckEnvelope returns an *EnvelopeWarning if date or latitude lies
+// outside the validated envelope of the algorithm used for the
+// calculation, and nil otherwise. Algorithms from outside the package
+// have no known envelope and are never flagged.
+func CheckEnvelope(date time.Time, latitude float64, opts ...Option) error {
+	info, ok := algorithmInfo(newOptions(opts).algorithm())
+	if !ok {
+		return nil
+	}
+	return info.check(date, latitude)
+}
+
+func (a AlgorithmInfo) check(date time.Time, latitude float64) error {
+	if y := date.Year(); y < a.MinYear || y > a.MaxYear {
+		return &EnvelopeWarning{Algorithm: a.Name, Reason: fmt.Sprintf("year %d not in %d-%d", y, a.MinYear, a.MaxYear)}
+	}
+	if math.Abs(latitude) > a.MaxLatitude {
+		return &EnvelopeWarning{Algorithm: a.Name, Reason: fmt.Sprintf("latitude %.2f beyond ±%.0f", latitude, a.MaxLatitude)}
+	}
+	return nil
+}
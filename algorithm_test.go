@@ -0,0 +1,102 @@
+package sunevent
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCheckEnvelope(t *testing.T) {
+	date := time.Date(2026, time.June, 21, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		date     time.Time
+		latitude float64
+		opts     []Option
+		reason   string
+	}{
+		{date, 45, nil, ""},
+		{date, -60, nil, ""},
+		{date, 65, nil, "latitude 65.00 beyond ±60"},
+		{date, 65, []Option{WithAlgorithm(NOAA)}, ""},
+		{date, -75, []Option{WithAlgorithm(NOAA)}, "latitude -75.00 beyond ±72"},
+		{time.Date(1750, time.June, 21, 12, 0, 0, 0, time.UTC), 45, nil, "year 1750 not in 1800-2100"},
+		{time.Date(2101, time.June, 21, 12, 0, 0, 0, time.UTC), 45, []Option{WithAlgorithm(NOAA)}, "year 2101 not in 1800-2100"},
+		// algorithms from outside the package are never flagged
+		{date, 89, []Option{WithAlgorithm(civilDateAlgorithm{})}, ""},
+	}
+	for _, tt := range tests {
+		err := CheckEnvelope(tt.date, tt.latitude, tt.opts...)
+		if tt.reason == "" {
+			if err != nil {
+				t.Errorf("%d, %v: %v, want nil", tt.date.Year(), tt.latitude, err)
+			}
+			continue
+		}
+		w, ok := err.(*EnvelopeWarning)
+		if !ok || w.Reason != tt.reason {
+			t.Errorf("%d, %v: %v, want %q", tt.date.Year(), tt.latitude, err, tt.reason)
+		}
+	}
+
+	// the warning travels with the events
+	ev, err := EventOn(Sunrise, date, 65, 25)
+	if err != nil || ev.Warning == nil {
+		t.Errorf("EventOn at 65°: %+v, %v, want a warning", ev, err)
+	}
+}
+
+// fixedAlgorithm rises at 06:00 and sets at 18:00 in the zone of the date,
+// and remembers the zenith angle it was asked for.
+type fixedAlgorithm struct {
+	zenith *float64
+	err    error
+}
+
+func (a fixedAlgorithm) SunTimes(date time.Time, latitude, longitude, zenith float64) (rise, set time.Time, err error) {
+	*a.zenith = zenith
+	if a.err != nil {
+		return time.Time{}, time.Time{}, a.err
+	}
+	y, m, d := date.Date()
+	return time.Date(y, m, d, 6, 0, 0, 0, date.Location()), time.Date(y, m, d, 18, 0, 0, 0, date.Location()), nil
+}
+
+func TestWithAlgorithm(t *testing.T) {
+	date := time.Date(2026, time.March, 4, 12, 0, 0, 0, time.UTC)
+
+	// the built-in algorithms give the times of SunRiseOn and SunSetOn
+	for _, a := range []Algorithm{Almanac, NOAA} {
+		rise, set, err := a.SunTimes(date, 40, -74, 90)
+		if err != nil {
+			t.Fatal(err)
+		}
+		wantRise, _ := SunRiseOn(date, 40, -74, WithAlgorithm(a))
+		wantSet, _ := SunSetOn(date, 40, -74, WithAlgorithm(a))
+		if !rise.Equal(wantRise) || !set.Equal(wantSet) {
+			t.Errorf("%v: SunTimes = %s, %s, want %s, %s", a, rise, set, wantRise, wantSet)
+		}
+	}
+
+	var zenith float64
+	custom := fixedAlgorithm{zenith: &zenith}
+	rise, err := SunRiseOn(date, 40, -74, WithAlgorithm(custom))
+	if err != nil || rise.Hour() != 6 {
+		t.Errorf("SunRiseOn = %s, %v, want 06:00", rise, err)
+	}
+	if _, err := TimeAtAltitude(date, 40, -74, -6, false, WithAlgorithm(custom)); err != nil || zenith != 96 {
+		t.Errorf("TimeAtAltitude asked for zenith %v, %v, want 96", zenith, err)
+	}
+	if ev, err := EventOn(Sunset, date, 40, -74, WithAlgorithm(custom)); err != nil || ev.Time.Hour() != 18 || ev.Accuracy != 0 {
+		t.Errorf("EventOn = %+v, %v, want 18:00 of unknown accuracy", ev, err)
+	}
+	if d := SunDayOn(date, 40, -74, WithAlgorithm(custom)); d.Type != NormalDay || d.DayLength != 12*time.Hour {
+		t.Errorf("SunDayOn: %v, %s, want a normal day of 12h", d.Type, d.DayLength)
+	}
+
+	custom.err = ErrSunNeverSets
+	if _, err := SunSetOn(date, 40, -74, WithAlgorithm(custom)); err != ErrSunNeverSets {
+		t.Errorf("SunSetOn error %v, want %v", err, ErrSunNeverSets)
+	}
+	if !IsPolarDay(date, 40, -74, WithAlgorithm(custom)) {
+		t.Error("IsPolarDay is false for an algorithm that never sets")
+	}
+}
@@ -0,0 +1,105 @@
+package sunevent
+
+import (
+	"time"
+)
+
+// DayBoundary selects where reports start a new day.
+type DayBoundary int
+
+const (
+	// CivilMidnight starts the day at 00:00 in the time zone of the
+	// report. This is the default.
+	CivilMidnight DayBoundary = iota
+	// SolarMidnight starts the day when the Sun crosses the lower
+	// meridian, 12 hours from solar noon.
+	SolarMidnight
+)
+
+// ReportCalendar defines how reports and digests group time into days and
+// weeks. The zero value uses civil midnight and weeks starting on Sunday.
+//
+// Offset moves the day boundary, so a day running from 04:00 to 04:00 is
+//
+//	ReportCalendar{Boundary: CivilMidnight, Offset: 4 * time.Hour}
+type ReportCalendar struct {
+	Boundary  DayBoundary
+	Offset    time.Duration
+	WeekStart time.Weekday
+}
+
+// Day returns the report day containing t at the given longitude, as a
+// half-open interval [start, end) in the time zone of t.
+func (c ReportCalendar) Day(t time.Time, longitude float64, opts ...Option) (start, end time.Time) {
+	date := time.Date(t.Year(), t.Month(), t.Day(), 12, 0, 0, 0, t.Location())
+
+	start = c.dayStart(date, longitude, opts)
+	if t.Before(start) {
+		date = date.AddDate(0, 0, -1)
+		return c.dayStart(date, longitude, opts), start
+	}
+	end = c.dayStart(date.AddDate(0, 0, 1), longitude, opts)
+	if !t.Before(end) {
+		return end, c.dayStart(date.AddDate(0, 0, 2), longitude, opts)
+	}
+	return start, end
+}
+
+// Week returns the report week containing t: seven report days, the first
+// of which begins on WeekStart.
+func (c ReportCalendar) Week(t time.Time, longitude float64, opts ...Option) (start, end time.Time) {
+	dayStart, _ := c.Day(t, longitude, opts...)
+
+	// the civil date the report day is anchored to
+	anchor := dayStart.Add(-c.Offset)
+	if c.Boundary == SolarMidnight {
+		anchor = dayStart.Add(12 * time.Hour)
+	}
+	back := (int(anchor.Weekday()) - int(c.WeekStart) + 7) % 7
+
+	first := time.Date(anchor.Year(), anchor.Month(), anchor.Day()-back, 12, 0, 0, 0, t.Location())
+	return c.dayStart(first, longitude, opts), c.dayStart(first.AddDate(0, 0, 7), longitude, opts)
+}
+
+// dayStart returns the start of the report day anchored to the civil date
+// of date.
+func (c ReportCalendar) dayStart(date time.Time, longitude float64, opts []Option) time.Time {
+	if c.Boundary == SolarMidnight {
+		noon, _ := SolarNoonOn(date, 0, longitude, opts...)
+		return noon.Add(-12 * time.Hour).Add(c.Offset)
+	}
+	// the offset is a wall-clock time, so a 04:00 boundary stays at 04:00
+	// across daylight saving changes
+	o := newOptions(opts)
+	if c.Offset == 0 {
+		return o.midnight(date)
+	}
+	return o.wallTime(date.Year(), date.Month(), date.Day(), c.Offset, date.Location())
+}
+
+// DaylightBetween returns how long the Sun is above the horizon during
+// [from, to). Together with ReportCalendar it answers questions such as
+// "how much daylight did this week gain over the last".
+func DaylightBetween(latitude, longitude float64, from, to time.Time) time.Duration {
+	if !from.Before(to) {
+		return 0
+	}
+
+	up := Elevation(from, latitude, longitude) > 0
+	since := from
+
+	var total time.Duration
+	for _, e := range EventsBetween(latitude, longitude, from, to, Sunrise, Sunset) {
+		switch {
+		case e.Type == Sunrise && !up:
+			up, since = true, e.Time
+		case e.Type == Sunset && up:
+			total += e.Time.Sub(since)
+			up = false
+		}
+	}
+	if up {
+		total += to.Sub(since)
+	}
+	return total
+}
@@ -0,0 +1,63 @@
+package sunevent
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReportCalendarDay(t *testing.T) {
+	taipei := time.FixedZone("CST", 8*3600)
+	at := func(day, hour int) time.Time {
+		return time.Date(2026, time.March, day, hour, 0, 0, 0, taipei)
+	}
+
+	c := ReportCalendar{Offset: 4 * time.Hour}
+	tests := []struct {
+		t          time.Time
+		start, end time.Time
+	}{
+		{at(5, 12), at(5, 4), at(6, 4)},
+		{at(5, 3), at(4, 4), at(5, 4)},
+		{at(5, 4), at(5, 4), at(6, 4)},
+		{at(5, 23), at(5, 4), at(6, 4)},
+	}
+	for _, tt := range tests {
+		start, end := c.Day(tt.t, 120.30)
+		if !start.Equal(tt.start) || !end.Equal(tt.end) {
+			t.Errorf("Day(%s) = %s–%s, want %s–%s", tt.t, start, end, tt.start, tt.end)
+		}
+	}
+
+	// a solar day runs from one solar midnight to the next
+	c = ReportCalendar{Boundary: SolarMidnight}
+	start, end := c.Day(at(5, 12), 120.30)
+	noon, _ := SolarNoonOn(at(5, 12), 0, 120.30)
+	if !start.Equal(noon.Add(-12*time.Hour)) || !near(end.Sub(start).Hours(), 24, 0.01) {
+		t.Errorf("solar day = %s–%s, want centred on %s", start, end, noon)
+	}
+}
+
+func TestReportCalendarWeek(t *testing.T) {
+	taipei := time.FixedZone("CST", 8*3600)
+	at := func(month time.Month, day, hour int) time.Time {
+		return time.Date(2026, month, day, hour, 0, 0, 0, taipei)
+	}
+	tests := []struct {
+		c          ReportCalendar
+		t          time.Time
+		start, end time.Time
+	}{
+		// 4 March 2026 is a Wednesday
+		{ReportCalendar{}, at(time.March, 4, 12), at(time.March, 1, 0), at(time.March, 8, 0)},
+		{ReportCalendar{WeekStart: time.Monday}, at(time.March, 4, 12), at(time.March, 2, 0), at(time.March, 9, 0)},
+		// Monday 03:00 still belongs to the Sunday of a 04:00 calendar
+		{ReportCalendar{Offset: 4 * time.Hour, WeekStart: time.Monday}, at(time.March, 2, 3), at(time.February, 23, 4), at(time.March, 2, 4)},
+		{ReportCalendar{Offset: 4 * time.Hour, WeekStart: time.Monday}, at(time.March, 2, 5), at(time.March, 2, 4), at(time.March, 9, 4)},
+	}
+	for _, tt := range tests {
+		start, end := tt.c.Week(tt.t, 120.30)
+		if !start.Equal(tt.start) || !end.Equal(tt.end) {
+			t.Errorf("%+v: Week(%s) = %s–%s, want %s–%s", tt.c, tt.t, start, end, tt.start, tt.end)
+		}
+	}
+}
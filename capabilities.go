@@ -0,0 +1,48 @@
+package sunevent
+
+// CalcVersion identifies the calculation engine. It is incremented
+// whenever a change alters computed results, so stored results can be
+// invalidated.
+const CalcVersion = 6
+
+// AnglePreset is a named solar zenith angle used for event definitions.
+type AnglePreset struct {
+	Name   string  `json:"name"`
+	Zenith float64 `json:"zenith"`
+}
+
+// CapabilitySet describes what the package can compute.
+type CapabilitySet struct {
+	CalcVersion int             `json:"calc_version"`
+	Events      []string        `json:"events"`
+	Algorithms  []AlgorithmInfo `json:"algorithms"`
+	Angles      []AnglePreset   `json:"angles"`
+}
+
+// AnglePresets lists the standard zenith angles. The "official" zenith of
+// 90°50′ puts the upper limb of the Sun on the horizon with standard
+// refraction, the sunrise and sunset of WithRefractionModel with
+// StandardRefraction. It is not the default of SunRiseOn and SunSetOn,
+// which without a refraction model take the geometric crossing of the
+// horizon by the centre of the Sun, at a zenith of 90°.
+var AnglePresets = []AnglePreset{
+	{Name: "official", Zenith: 90.0 + 50.0/60.0},
+	{Name: "civil", Zenith: 96.0},
+	{Name: "nautical", Zenith: 102.0},
+	{Name: "astronomical", Zenith: 108.0},
+}
+
+// Capabilities returns the events, algorithms and angle presets supported
+// by this version of the package, so user interfaces can build their
+// option lists instead of hard-coding them.
+func Capabilities() CapabilitySet {
+	c := CapabilitySet{
+		CalcVersion: CalcVersion,
+		Algorithms:  Algorithms(),
+		Angles:      append([]AnglePreset(nil), AnglePresets...),
+	}
+	for _, e := range EventTypes {
+		c.Events = append(c.Events, e.String())
+	}
+	return c
+}
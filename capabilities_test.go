@@ -0,0 +1,38 @@
+package sunevent
+
+import "testing"
+
+func TestCapabilities(t *testing.T) {
+	c := Capabilities()
+	if c.CalcVersion != CalcVersion {
+		t.Errorf("CalcVersion = %d, want %d", c.CalcVersion, CalcVersion)
+	}
+	if len(c.Events) != len(EventTypes) {
+		t.Fatalf("%d events, want %d", len(c.Events), len(EventTypes))
+	}
+	for i, e := range EventTypes {
+		if c.Events[i] != e.String() {
+			t.Errorf("event %d = %q, want %q", i, c.Events[i], e)
+		}
+	}
+	if len(c.Algorithms) != 2 || c.Algorithms[0].Name != "almanac" || c.Algorithms[1].Name != "noaa" {
+		t.Errorf("algorithms = %+v", c.Algorithms)
+	}
+
+	zenith := map[string]float64{}
+	for _, a := range c.Angles {
+		zenith[a.Name] = a.Zenith
+	}
+	want := map[string]float64{"official": 90 + 50.0/60, "civil": 96, "nautical": 102, "astronomical": 108}
+	for name, z := range want {
+		if zenith[name] != z {
+			t.Errorf("angle %s = %v, want %v", name, zenith[name], z)
+		}
+	}
+
+	// the angles are a copy
+	c.Angles[0].Zenith = 0
+	if AnglePresets[0].Zenith == 0 {
+		t.Error("Capabilities shares AnglePresets")
+	}
+}
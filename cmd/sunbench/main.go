@@ -0,0 +1,170 @@
+// Command sunbench compares the speed and accuracy of the package's
+// algorithms.
+//
+// Every algorithm, at standard and high precision, computes sunrise and
+// sunset over a grid of latitudes, longitudes and dates; the results are
+// compared with a reference and summarized in a table:
+//
+//	sunbench -year 2026 -maxlat 60
+//
+// The reference is by default the NOAA solar calculator equations, iterated
+// at the event time, which are accurate to about a minute. As the noaa
+// algorithm is one of those compared and scores no error against itself,
+// -reference vsop87 compares every algorithm instead with the times at
+// which the centre of the Sun crosses the horizon in the VSOP87 ephemeris,
+// which is independent of the algorithms and accurate to about an
+// arcsecond.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cfw011566/sunevent"
+)
+
+// variant is an algorithm of the package at a precision.
+type variant struct {
+	name string
+	opts []sunevent.Option
+}
+
+// variants returns every algorithm of the package at both precisions.
+func variants() []variant {
+	var vs []variant
+	for _, info := range sunevent.Algorithms() {
+		opts, err := sunevent.Profile{Name: info.Name, Algorithm: info.Name}.Options()
+		if err != nil {
+			continue
+		}
+		vs = append(vs,
+			variant{info.Name, opts},
+			variant{info.Name + "/high", append(opts, sunevent.WithPrecision(sunevent.High))})
+	}
+	return vs
+}
+
+type query struct {
+	date                time.Time
+	rising              bool
+	latitude, longitude float64
+
+	// references are the reference events of the UTC dates around date
+	references []time.Time
+}
+
+func main() {
+	year := flag.Int("year", time.Now().Year(), "year of the date grid")
+	maxLat := flag.Float64("maxlat", 60, "largest absolute latitude of the grid")
+	step := flag.Float64("step", 10, "grid spacing in degrees")
+	ref := flag.String("reference", "noaa", "reference of the errors: noaa, the NOAA equations, or vsop87, the VSOP87 ephemeris")
+	flag.Parse()
+
+	reference, ok := references[*ref]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "sunbench: unknown reference %q\n", *ref)
+		os.Exit(2)
+	}
+	queries := grid(*year, *maxLat, *step, reference)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "algorithm\tns/event\tmean error\tp95 error\tmax error\tmissed\t")
+	for _, v := range variants() {
+		results := make([]time.Time, len(queries))
+		start := time.Now()
+		for i, q := range queries {
+			results[i], _ = sunevent.TimeAtAltitude(q.date, q.latitude, q.longitude, 0.0, q.rising, v.opts...)
+		}
+		elapsed := time.Since(start)
+
+		errs := make([]float64, 0, len(queries))
+		missed := 0
+		for i, q := range queries {
+			if results[i].IsZero() {
+				missed++
+				continue
+			}
+			errs = append(errs, referenceError(q, results[i]))
+		}
+
+		sort.Float64s(errs)
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%d\t\n",
+			v.name,
+			elapsed.Nanoseconds()/int64(len(queries)),
+			seconds(mean(errs)),
+			seconds(percentile(errs, 0.95)),
+			seconds(percentile(errs, 1.0)),
+			missed)
+	}
+	w.Flush()
+	fmt.Printf("%d events, reference: %s\n", len(queries), *ref)
+}
+
+// referenceError returns the difference in seconds between t and the
+// reference event closest to it, so algorithms are not penalized for
+// choosing the event of a neighbouring UTC date near the antimeridian.
+func referenceError(q query, t time.Time) float64 {
+	best := math.Inf(1)
+	for _, ref := range q.references {
+		best = math.Min(best, math.Abs(t.Sub(ref).Seconds()))
+	}
+	return best
+}
+
+// grid builds the queries, keeping only events that happen on the dates
+// around the query.
+func grid(year int, maxLat, step float64, reference reference) []query {
+	var queries []query
+	for month := time.January; month <= time.December; month++ {
+		for _, day := range []int{1, 15} {
+			date := time.Date(year, month, day, 12, 0, 0, 0, time.UTC)
+			for lat := -maxLat; lat <= maxLat; lat += step {
+				for lon := -180.0; lon < 180.0; lon += step * 3 {
+					for _, rising := range []bool{true, false} {
+						q := query{date: date, rising: rising, latitude: lat, longitude: lon}
+						for _, days := range []int{-1, 0, 1} {
+							if ref, ok := reference(date.AddDate(0, 0, days), rising, lat, lon); ok {
+								q.references = append(q.references, ref)
+							}
+						}
+						if len(q.references) == 3 {
+							queries = append(queries, q)
+						}
+					}
+				}
+			}
+		}
+	}
+	return queries
+}
+
+func mean(v []float64) float64 {
+	if len(v) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, x := range v {
+		sum += x
+	}
+	return sum / float64(len(v))
+}
+
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	i := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if i < 0 {
+		i = 0
+	}
+	return sorted[i]
+}
+
+func seconds(s float64) string {
+	return (time.Duration(s * float64(time.Second))).Round(100 * time.Millisecond).String()
+}
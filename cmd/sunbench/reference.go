@@ -0,0 +1,77 @@
+package main
+
+import (
+	"math"
+	"time"
+
+	"github.com/cfw011566/sunevent"
+	"github.com/cfw011566/sunevent/vsop87"
+)
+
+// reference returns the time near the UTC date of date when the centre of
+// the Sun crosses the horizon, rising or setting; ok is false when it does
+// not.
+type reference func(date time.Time, rising bool, latitude, longitude float64) (event time.Time, ok bool)
+
+// references are the references selected by -reference.
+var references = map[string]reference{
+	"noaa":   noaaEvent,
+	"vsop87": ephemerisEvent,
+}
+
+// noaaEvent is the reference of the NOAA solar calculator, iterated at the
+// event time.
+func noaaEvent(date time.Time, rising bool, latitude, longitude float64) (time.Time, bool) {
+	t, err := sunevent.TimeAtAltitude(date, latitude, longitude, 0.0, rising,
+		sunevent.WithAlgorithm(sunevent.NOAA), sunevent.WithPrecision(sunevent.High))
+	return t, err == nil
+}
+
+// ephemeris is the source of the reference positions.
+var ephemeris vsop87.Ephemeris
+
+// ephemerisEvent is the reference of the VSOP87 ephemeris: the crossing
+// of the horizon rising in the half day before the solar noon nearest to
+// local noon, or setting in the half day after.
+func ephemerisEvent(date time.Time, rising bool, latitude, longitude float64) (event time.Time, ok bool) {
+	noon := time.Date(date.Year(), date.Month(), date.Day(), 12, 0, 0, 0, time.UTC)
+	noon = noon.Add(-time.Duration(longitude / 15.0 * float64(time.Hour)))
+
+	// the hour angle changes by about 360° a day
+	for i := 0; i < 3; i++ {
+		ha, _ := ephemeris.SunGreenwich(noon)
+		ha = math.Mod(ha+longitude+540.0, 360.0) - 180.0
+		noon = noon.Add(-time.Duration(ha / 360.0 * 24 * float64(time.Hour)))
+	}
+
+	lo, hi := noon.Add(-12*time.Hour), noon
+	if !rising {
+		lo, hi = noon, noon.Add(12*time.Hour)
+	}
+	// the altitude is monotonic between solar midnight and noon
+	hLo, hHi := altitude(lo, latitude, longitude), altitude(hi, latitude, longitude)
+	if (hLo < 0) == (hHi < 0) {
+		return time.Time{}, false
+	}
+	for hi.Sub(lo) > 10*time.Millisecond {
+		mid := lo.Add(hi.Sub(lo) / 2)
+		if h := altitude(mid, latitude, longitude); (h < 0) == (hLo < 0) {
+			lo, hLo = mid, h
+		} else {
+			hi = mid
+		}
+	}
+	return lo.Add(hi.Sub(lo) / 2), true
+}
+
+// altitude returns the geometric altitude in degrees of the centre of the
+// Sun at t.
+func altitude(t time.Time, latitude, longitude float64) float64 {
+	ha, dec := ephemeris.SunGreenwich(t)
+	phi, delta, h := rad(latitude), rad(dec), rad(ha+longitude)
+	return math.Asin(math.Sin(phi)*math.Sin(delta)+math.Cos(phi)*math.Cos(delta)*math.Cos(h)) * 180.0 / math.Pi
+}
+
+func rad(d float64) float64 {
+	return d * math.Pi / 180.0
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// config is the configuration file, by default
+// ~/.config/sunevent/config.toml:
+//
+//	[defaults]
+//	tz = "Asia/Taipei"
+//	format = "text"
+//	location = "home"      # used when a command names no location
+//
+//	[locations.home]
+//	lat = 22.63
+//	lon = 120.30
+//
+//	[locations.office]
+//	place = "Taipei"       # a place of the gazetteer
+//	tz = "Asia/Taipei"
+//
+// A location is named as the argument of a command, as in
+// "sunevent rise home". Flags take precedence over the file, and the time
+// zone of a location over that of [defaults].
+type config struct {
+	tz       string
+	format   string
+	location string
+
+	locations map[string]configLocation
+}
+
+type configLocation struct {
+	latitude, longitude float64
+	hasCoordinates      bool // set when both lat and lon are
+	place               string
+	tz                  string
+
+	// line is that of the first key, for errors
+	line                      int
+	hasLatitude, hasLongitude bool
+}
+
+// configEnv names the environment variable that overrides the path of the
+// configuration file.
+const configEnv = "SUNEVENT_CONFIG"
+
+// configPath returns the path of the configuration file.
+func configPath() string {
+	if path := os.Getenv(configEnv); path != "" {
+		return path
+	}
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		dir = filepath.Join(os.Getenv("HOME"), ".config")
+	}
+	return filepath.Join(dir, "sunevent", "config.toml")
+}
+
+// loadConfig reads the configuration file. A missing file is an empty
+// configuration.
+func loadConfig() (*config, error) {
+	path := configPath()
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return &config{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	c, err := parseConfig(bufio.NewScanner(f))
+	if err != nil {
+		return nil, fmt.Errorf("%s:%v", path, err)
+	}
+	return c, nil
+}
+
+// parseConfig parses the subset of TOML the configuration needs: tables,
+// and keys with string, number or boolean values. Errors start with the
+// line number.
+func parseConfig(sc *bufio.Scanner) (*config, error) {
+	c := &config{locations: make(map[string]configLocation)}
+	table := ""
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(stripComment(sc.Text()))
+		if text == "" {
+			continue
+		}
+
+		if strings.HasPrefix(text, "[") {
+			if !strings.HasSuffix(text, "]") {
+				return nil, fmt.Errorf("%d: invalid table %s", line, text)
+			}
+			table = strings.TrimSpace(text[1 : len(text)-1])
+			if table != "defaults" && !strings.HasPrefix(table, "locations.") {
+				return nil, fmt.Errorf("%d: unknown table [%s]", line, table)
+			}
+			continue
+		}
+
+		i := strings.Index(text, "=")
+		if i < 0 {
+			return nil, fmt.Errorf("%d: expected key = value", line)
+		}
+		key := strings.TrimSpace(text[:i])
+		value, err := parseValue(strings.TrimSpace(text[i+1:]))
+		if err != nil {
+			return nil, fmt.Errorf("%d: %s: %v", line, key, err)
+		}
+		if err := c.set(table, key, value, line); err != nil {
+			return nil, fmt.Errorf("%d: %v", line, err)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	// a lone lat or lon would silently put the location on the equator
+	// or the prime meridian
+	bad, badLine := "", 0
+	for name, l := range c.locations {
+		if l.hasLatitude != l.hasLongitude && (badLine == 0 || l.line < badLine) {
+			bad, badLine = name, l.line
+		}
+	}
+	if badLine != 0 {
+		return nil, fmt.Errorf("%d: location %q needs both lat and lon", badLine, bad)
+	}
+	return c, nil
+}
+
+// set sets the key of table to value, read at line.
+func (c *config) set(table, key, value string, line int) error {
+	if table == "defaults" {
+		switch key {
+		case "tz":
+			c.tz = value
+		case "format":
+			c.format = value
+		case "location":
+			c.location = value
+		default:
+			return fmt.Errorf("unknown key %s in [defaults]", key)
+		}
+		return nil
+	}
+	if table == "" {
+		return fmt.Errorf("key %s outside a table", key)
+	}
+
+	name := unquoteKey(strings.TrimPrefix(table, "locations."))
+	l := c.locations[name]
+	if l.line == 0 {
+		l.line = line
+	}
+	var err error
+	switch key {
+	case "lat":
+		l.latitude, err = strconv.ParseFloat(value, 64)
+		l.hasLatitude = true
+	case "lon":
+		l.longitude, err = strconv.ParseFloat(value, 64)
+		l.hasLongitude = true
+	case "place":
+		l.place = value
+	case "tz":
+		l.tz = value
+	default:
+		return fmt.Errorf("unknown key %s in [%s]", key, table)
+	}
+	if err != nil {
+		return fmt.Errorf("%s is not a number", key)
+	}
+	l.hasCoordinates = l.hasLatitude && l.hasLongitude
+	c.locations[name] = l
+	return nil
+}
+
+// stripComment removes a comment from a line, leaving # inside strings.
+func stripComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		switch ch := line[i]; {
+		case quote != 0:
+			if ch == '\\' && quote == '"' {
+				i++
+			} else if ch == quote {
+				quote = 0
+			}
+		case ch == '"' || ch == '\'':
+			quote = ch
+		case ch == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// parseValue returns a TOML value as a string: a basic or literal string
+// without its quotes, or a number or boolean as written.
+func parseValue(v string) (string, error) {
+	switch {
+	case strings.HasPrefix(v, `"`):
+		return strconv.Unquote(v)
+	case strings.HasPrefix(v, "'"):
+		if len(v) < 2 || !strings.HasSuffix(v, "'") {
+			return "", fmt.Errorf("unterminated string")
+		}
+		return v[1 : len(v)-1], nil
+	case v == "true" || v == "false":
+		return v, nil
+	}
+	if _, err := strconv.ParseFloat(strings.Replace(v, "_", "", -1), 64); err != nil {
+		return "", fmt.Errorf("invalid value %s", v)
+	}
+	return strings.Replace(v, "_", "", -1), nil
+}
+
+// unquoteKey removes the quotes of a quoted TOML key, as in
+// [locations."summer house"].
+func unquoteKey(k string) string {
+	if s, err := strconv.Unquote(k); err == nil {
+		return s
+	}
+	return strings.Trim(k, "'")
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bufio"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cfw011566/sunevent"
+)
+
+func TestParseConfig(t *testing.T) {
+	c, err := parseConfig(bufio.NewScanner(strings.NewReader(`
+# sunevent
+[defaults]
+tz = "Asia/Taipei"
+format = 'json'
+location = "home"   # the default
+
+[locations.home]
+lat = 22.63
+lon = 120.30
+
+[locations."summer #2"]
+place = "Tromso"
+tz = "Europe/Oslo"
+`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.tz != "Asia/Taipei" || c.format != "json" || c.location != "home" {
+		t.Errorf("defaults = %q, %q, %q", c.tz, c.format, c.location)
+	}
+	if home := c.locations["home"]; !home.hasCoordinates || home.latitude != 22.63 || home.longitude != 120.30 {
+		t.Errorf("home = %+v", home)
+	}
+	if summer := c.locations["summer #2"]; summer.place != "Tromso" || summer.tz != "Europe/Oslo" || summer.hasCoordinates {
+		t.Errorf("summer #2 = %+v", summer)
+	}
+}
+
+func TestParseConfigErrors(t *testing.T) {
+	tests := []struct {
+		config, err string
+	}{
+		{"tz = \"UTC\"", "1: key tz outside a table"},
+		{"[defaults\n", "1: invalid table"},
+		{"[places.home]", "1: unknown table"},
+		{"[defaults]\ncolour = \"red\"", "2: unknown key colour"},
+		{"[locations.home]\nlat", "2: expected key = value"},
+		{"[locations.home]\nlat = north", "2: lat: invalid value"},
+		{"[locations.home]\nlat = \"north\"", "2: lat is not a number"},
+		{"[locations.home]\nplace = 'Taipei", "2: place: unterminated string"},
+		{"[locations.a]\nlat = 1\nlon = 2\n[locations.b]\nlat = 3", "5: location \"b\" needs both lat and lon"},
+	}
+	for _, tt := range tests {
+		_, err := parseConfig(bufio.NewScanner(strings.NewReader(tt.config)))
+		if err == nil || !strings.HasPrefix(err.Error(), tt.err) {
+			t.Errorf("%q: %v, want %s", tt.config, err, tt.err)
+		}
+	}
+}
+
+func TestConfigLocation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sunevent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config.toml")
+	config := "[defaults]\nlocation = \"home\"\n\n[locations.home]\nlat = 78.22\nlon = 15.65\ntz = \"UTC\"\n"
+	if err := ioutil.WriteFile(path, []byte(config), 0666); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Setenv(configEnv, os.Getenv(configEnv))
+	os.Setenv(configEnv, path)
+
+	// the default location is home, in polar night on 21 December, unless
+	// the flags say otherwise
+	for _, args := range [][]string{
+		{"--date", "2026-12-21"},
+		{"home", "--date", "2026-12-21"},
+	} {
+		_, err := capture(t, func() error { return runRise(args) })
+		if err != sunevent.ErrSunNeverRises {
+			t.Errorf("rise %s: %v, want no sunrise in the polar night at home", strings.Join(args, " "), err)
+		}
+	}
+	out, err := capture(t, func() error { return runRise([]string{"Kaohsiung", "--date", "2026-12-21", "--tz", "UTC"}) })
+	if err != nil || !strings.HasPrefix(out, "2026-12-2") {
+		t.Errorf("rise in Kaohsiung printed %q, %v", out, err)
+	}
+	if _, err := capture(t, func() error { return runRise([]string{"atlantis"}) }); err == nil {
+		t.Error("rise accepted an unknown location")
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cfw011566/sunevent"
+	"github.com/cfw011566/sunevent/ical"
+)
+
+// runIcs writes the events of a year as an iCalendar file. It always
+// writes iCalendar, whatever the format.
+func runIcs(args []string) error {
+	var q query
+	fs := newFlagSet("ics", &q)
+	year := fs.Int("year", time.Now().Year(), "year of the calendar")
+	events := fs.String("events", "civil_dawn,sunrise,sunset,civil_dusk", "comma-separated events to include")
+	name := fs.String("name", "", "name of the calendar (default Sun at the location)")
+	if err := q.parse(fs, args); err != nil {
+		return err
+	}
+
+	var types []sunevent.EventType
+	for _, s := range strings.Split(*events, ",") {
+		e, err := sunevent.ParseEventType(strings.TrimSpace(s))
+		if err != nil {
+			return err
+		}
+		types = append(types, e)
+	}
+
+	c := ical.Calendar{
+		Name:      *name,
+		Latitude:  q.latitude,
+		Longitude: q.longitude,
+	}
+	if c.Name == "" {
+		c.Name = fmt.Sprintf("Sun at %.2f, %.2f", q.latitude, q.longitude)
+		if q.place != "" {
+			c.Name = "Sun in " + q.place
+		}
+	}
+	from := time.Date(*year, time.January, 1, 0, 0, 0, 0, q.loc)
+	c.Events = sunevent.EventsBetween(q.latitude, q.longitude, from, from.AddDate(1, 0, 0), types...)
+	return c.Write(os.Stdout)
+}
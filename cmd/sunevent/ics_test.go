@@ -0,0 +1,23 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIcs(t *testing.T) {
+	out, err := run(t, "ics", "--lat", "22.63", "--lon", "120.30", "--tz", "UTC", "--year", "2026", "--events", "sunrise, sunset")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(out, "BEGIN:VEVENT"); n != 2*365 {
+		t.Errorf("%d events, want a sunrise and a sunset every day", n)
+	}
+	if !strings.Contains(out, "X-WR-CALNAME:Sun at 22.63\\, 120.30\r\n") {
+		t.Error("calendar lacks the default name")
+	}
+
+	if _, err := run(t, "ics", "--lat", "22.63", "--lon", "120.30", "--events", "sunrise,teatime"); err == nil {
+		t.Error("ics accepted an unknown event")
+	}
+}
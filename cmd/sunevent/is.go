@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/cfw011566/sunevent"
+)
+
+// lightTests maps the tests of is to the phases of daylight they accept.
+var lightTests = map[string][]sunevent.PhaseKind{
+	"day":   {sunevent.PhaseDay},
+	"night": {sunevent.PhaseNight},
+	"twilight": {
+		sunevent.PhaseCivilTwilight,
+		sunevent.PhaseNauticalTwilight,
+		sunevent.PhaseAstronomicalTwilight,
+	},
+	"dark": {
+		sunevent.PhaseNight,
+		sunevent.PhaseAstronomicalTwilight,
+		sunevent.PhaseNauticalTwilight,
+	},
+	"civil_twilight":        {sunevent.PhaseCivilTwilight},
+	"nautical_twilight":     {sunevent.PhaseNauticalTwilight},
+	"astronomical_twilight": {sunevent.PhaseAstronomicalTwilight},
+}
+
+// runIs tests the phase of daylight: day has the Sun above the horizon,
+// night more than 18° below it and twilight in between. dark is night or
+// any twilight but civil, when outdoor lights are needed, and the names
+// of the phases test a single twilight.
+func runIs(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: sunevent is day|night|twilight|dark [location] [flags]")
+	}
+	test, ok := lightTests[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown test %q; want day, night, twilight, dark or a twilight phase", args[0])
+	}
+
+	var q query
+	fs := newFlagSet("is "+args[0], &q)
+	q.dateFlag(fs, "time as 2006-01-02T15:04 or RFC 3339 (default now)")
+	if err := q.parse(fs, args[1:]); err != nil {
+		return err
+	}
+	t, err := q.instant()
+	if err != nil {
+		return err
+	}
+
+	phase := sunevent.PhaseAt(t, q.latitude, q.longitude)
+	for _, p := range test {
+		if p == phase {
+			return nil
+		}
+	}
+	return errFalse
+}
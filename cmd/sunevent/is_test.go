@@ -0,0 +1,37 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestIs(t *testing.T) {
+	// Greenwich, 21 June 2026, in UTC
+	tests := []struct {
+		test, at string
+		want     error
+	}{
+		{"day", "2026-06-21T12:00", nil},
+		{"night", "2026-06-21T12:00", errFalse},
+		{"dark", "2026-06-21T12:00", errFalse},
+		{"twilight", "2026-06-21T03:30", nil},
+		{"civil_twilight", "2026-06-21T03:30", nil},
+		{"dark", "2026-06-21T03:30", errFalse},
+		// there is no night in London at midsummer, only astronomical twilight
+		{"night", "2026-06-21T00:00", errFalse},
+		{"astronomical_twilight", "2026-06-21T00:00", nil},
+		{"dark", "2026-06-21T00:00", nil},
+		{"night", "2026-12-21T00:00", nil},
+	}
+	for _, tt := range tests {
+		out, err := run(t, "is", tt.test, "--lat", "51.48", "--lon", "0", "--tz", "UTC", "--date", tt.at)
+		if err != tt.want || out != "" {
+			t.Errorf("is %s at %s: %v, printed %q, want %v", tt.test, tt.at, err, out, tt.want)
+		}
+	}
+	if err := runIs([]string{"sunny"}); err == nil || exitStatus(err) != 2 {
+		t.Errorf("is sunny: %v, want a usage error", err)
+	}
+	if exitStatus(errFalse) != 1 {
+		t.Error("a test that does not hold does not exit with status 1")
+	}
+}
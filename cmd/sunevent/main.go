@@ -0,0 +1,379 @@
+// Command sunevent prints the solar events of a location, so that shell
+// scripts can use the package without Go:
+//
+//	sunevent rise --lat 22.63 --lon 120.30 --tz Asia/Taipei
+//	sunevent set --lat 22.63 --lon 120.30 --date 2026-06-21
+//	sunevent twilight --lat 59.33 --lon 18.07 --kind nautical
+//	sunevent day --lat 22.63 --lon 120.30
+//	sunevent position --lat 22.63 --lon 120.30 --date 2026-06-21T12:00
+//	sunevent table --lat 22.63 --lon 120.30 --month 2025-07
+//	sunevent watch --lat 22.63 --lon 120.30
+//	sunevent ics --lat 22.63 --lon 120.30 --year 2026 > sun.ics
+//	sunevent serve --addr :8080
+//	sunevent mqtt home --broker tcp://broker:1883 --discovery homeassistant
+//	sunevent webhook home --url https://example.com/hooks/sun --events sunset
+//
+// Times are printed as "2006-01-02 15:04:05 -0700" in the time zone of
+// --tz, by default the local one. --date is a date such as 2026-06-21,
+// by default today; position also accepts a time of day and defaults to
+// now. An event that does not happen, as sunrise in polar night, is
+// reported on standard error with exit status 1; other errors exit with
+// status 2.
+//
+// For shell scripts and cron jobs, is tests the light at --date, by
+// default now, printing nothing and exiting with status 0 if the test
+// holds and 1 if not:
+//
+//	sunevent is night --place home && lights on
+//
+// With --format json every command prints a JSON document instead, for
+// jq and other tools. Times are RFC 3339 strings, events that do not
+// happen are null and the day_type field tells whether the day is a
+// polar_day or polar_night; a missing event is then not an error.
+//
+// Instead of coordinates, --place names a city of the built-in gazetteer,
+// as in --place Kaohsiung or --place "Portland, US", and its time zone
+// becomes the default of --tz. More places can be listed in a CSV file
+// named by $SUNEVENT_GAZETTEER, one name,country,lat,lon,timezone per
+// line.
+//
+// Named locations and defaults for the time zone and format can be kept
+// in ~/.config/sunevent/config.toml, or the file named by
+// $SUNEVENT_CONFIG, and chosen by name, as in "sunevent rise home":
+//
+//	[defaults]
+//	tz = "Asia/Taipei"
+//	location = "home"
+//
+//	[locations.home]
+//	lat = 22.63
+//	lon = 120.30
+//
+// A name that is not in the file is looked up in the gazetteer. Flags
+// take precedence over the file.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cfw011566/sunevent"
+)
+
+// command is a subcommand of sunevent; run receives the arguments after
+// its name.
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) error
+}
+
+var commands []command
+
+func init() {
+	commands = []command{
+		{"rise", "print the sunrise", runRise},
+		{"set", "print the sunset", runSet},
+		{"twilight", "print the dawn and dusk of a twilight", runTwilight},
+		{"day", "print every event of the day", runDay},
+		{"position", "print the azimuth and altitude of the Sun", runPosition},
+		{"table", "print a table of the days of a month or year", runTable},
+		{"watch", "show the Sun and countdowns to the next events live", runWatch},
+		{"is", "test whether it is day, night or twilight", runIs},
+		{"ics", "write a year of events as an iCalendar file", runIcs},
+		{"serve", "serve the HTTP JSON API", runServe},
+		{"mqtt", "publish the events to an MQTT broker", runMQTT},
+		{"webhook", "post the events to URLs", runWebhook},
+	}
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("sunevent: ")
+
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	for _, c := range commands {
+		if c.name == os.Args[1] {
+			err := c.run(os.Args[2:])
+			if err == nil {
+				return
+			}
+			status := exitStatus(err)
+			if err != errFalse {
+				// errors of the package carry the prefix already
+				log.Print(strings.TrimPrefix(err.Error(), "sunevent: "))
+			}
+			os.Exit(status)
+		}
+	}
+	if os.Args[1] != "help" && os.Args[1] != "-h" && os.Args[1] != "--help" {
+		log.Printf("unknown command %q", os.Args[1])
+	}
+	usage()
+	os.Exit(2)
+}
+
+// errFalse is returned by a test that does not hold.
+var errFalse = errors.New("false")
+
+// exitStatus returns the exit status for the error of a command: 1 when a
+// test does not hold or an event does not happen, as grep exits with 1
+// when nothing matches, and 2 for every other error.
+func exitStatus(err error) int {
+	switch err {
+	case errFalse, sunevent.ErrSunNeverRises, sunevent.ErrSunNeverSets:
+		return 1
+	}
+	return 2
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: sunevent <command> [location] [--lat <degrees> --lon <degrees> | --place <name>] [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "commands:")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Run sunevent <command> -h for the flags of a command.")
+}
+
+// query holds the flags common to every command.
+type query struct {
+	latitude, longitude float64
+	place               string
+	date                string
+	tz                  string
+	format              string
+
+	// set by parse
+	loc  *time.Location
+	name string // of the location of the configuration or the gazetteer
+}
+
+// newFlagSet returns the flag set of the command name with the common
+// flags registered in q.
+func newFlagSet(name string, q *query) *flag.FlagSet {
+	fs := flag.NewFlagSet("sunevent "+name, flag.ExitOnError)
+	fs.Float64Var(&q.latitude, "lat", 0, "latitude in degrees, north positive")
+	fs.Float64Var(&q.longitude, "lon", 0, "longitude in degrees, east positive")
+	fs.StringVar(&q.place, "place", "", "name of a place of the gazetteer instead of --lat and --lon")
+	fs.StringVar(&q.tz, "tz", "", "IANA time zone of the output (default local)")
+	fs.StringVar(&q.format, "format", "text", "output format: text or json")
+	return fs
+}
+
+// dateUsage is the usage of --date for the commands that take a date.
+const dateUsage = "date as 2006-01-02 (default today)"
+
+// dateFlag registers --date in fs, for the commands that take a date or
+// an instant.
+func (q *query) dateFlag(fs *flag.FlagSet, usage string) {
+	fs.StringVar(&q.date, "date", "", usage)
+}
+
+// parse parses args into fs and resolves the location, time zone and
+// format from the flags, the configuration file and the gazetteer. The
+// only argument allowed is the name of a location.
+func (q *query) parse(fs *flag.FlagSet, args []string) error {
+	// flags may follow the location name, where the flag package would
+	// stop looking for them
+	var names []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		names = append(names, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	if len(names) > 1 {
+		return fmt.Errorf("unexpected argument %q", names[1])
+	}
+	seen := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { seen[f.Name] = true })
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	if !seen["format"] && cfg.format != "" {
+		q.format = cfg.format
+	}
+
+	zone := ""
+	switch {
+	case seen["lat"] || seen["lon"]:
+		if !seen["lat"] || !seen["lon"] {
+			return fmt.Errorf("--lat and --lon must be given together")
+		}
+		if q.place != "" || len(names) > 0 {
+			return fmt.Errorf("--lat and --lon cannot be combined with --place or a location name")
+		}
+	case q.place != "":
+		if len(names) > 0 {
+			return fmt.Errorf("--place cannot be combined with a location name")
+		}
+		p, err := lookupPlace(q.place)
+		if err != nil {
+			return err
+		}
+		q.latitude, q.longitude, zone = p.Latitude, p.Longitude, p.TimeZone
+		q.name = q.place
+	default:
+		name := cfg.location
+		if len(names) == 1 {
+			name = names[0]
+		}
+		if name == "" {
+			return fmt.Errorf("--lat and --lon, --place or a location name is required")
+		}
+		if zone, err = q.useLocation(cfg, name); err != nil {
+			return err
+		}
+		q.name = name
+	}
+
+	if q.latitude < -90 || q.latitude > 90 {
+		return fmt.Errorf("latitude %v is outside -90 to 90", q.latitude)
+	}
+	if q.longitude < -180 || q.longitude > 180 {
+		return fmt.Errorf("longitude %v is outside -180 to 180", q.longitude)
+	}
+	if q.format != "text" && q.format != "json" {
+		return fmt.Errorf("unknown format %q", q.format)
+	}
+
+	switch {
+	case q.tz != "":
+	case zone != "":
+		q.tz = zone
+	default:
+		q.tz = cfg.tz
+	}
+	q.loc = time.Local
+	if q.tz != "" {
+		loc, err := time.LoadLocation(q.tz)
+		if err != nil {
+			return err
+		}
+		q.loc = loc
+	}
+	return nil
+}
+
+// useLocation sets the coordinates to those of the named location of the
+// configuration, or else of the place of the gazetteer, and returns its
+// time zone, if known.
+func (q *query) useLocation(cfg *config, name string) (string, error) {
+	l, ok := cfg.locations[name]
+	if !ok {
+		p, err := lookupPlace(name)
+		if err != nil {
+			return "", fmt.Errorf("unknown location %q: it is neither in %s nor a place of the gazetteer", name, configPath())
+		}
+		q.latitude, q.longitude = p.Latitude, p.Longitude
+		return p.TimeZone, nil
+	}
+
+	switch {
+	case l.hasCoordinates:
+		q.latitude, q.longitude = l.latitude, l.longitude
+		return l.tz, nil
+	case l.place != "":
+		p, err := lookupPlace(l.place)
+		if err != nil {
+			return "", fmt.Errorf("location %q: %v", name, err)
+		}
+		q.latitude, q.longitude = p.Latitude, p.Longitude
+		if l.tz != "" {
+			return l.tz, nil
+		}
+		return p.TimeZone, nil
+	}
+	return "", fmt.Errorf("location %q has neither lat and lon nor a place", name)
+}
+
+// day returns the date of --date at noon, which keeps it away from
+// daylight saving transitions.
+func (q *query) day() (time.Time, error) {
+	d := time.Now().In(q.loc)
+	if q.date != "" {
+		var err error
+		if d, err = time.ParseInLocation("2006-01-02", q.date, q.loc); err != nil {
+			return time.Time{}, fmt.Errorf("invalid date %q", q.date)
+		}
+	}
+	return time.Date(d.Year(), d.Month(), d.Day(), 12, 0, 0, 0, q.loc), nil
+}
+
+// instantLayouts are the forms of --date accepted by position.
+var instantLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+}
+
+// instant returns the time of --date, by default now. A date without a
+// time of day is taken at noon.
+func (q *query) instant() (time.Time, error) {
+	if q.date == "" {
+		return time.Now().In(q.loc), nil
+	}
+	for _, layout := range instantLayouts {
+		if t, err := time.ParseInLocation(layout, q.date, q.loc); err == nil {
+			return t.In(q.loc), nil
+		}
+	}
+	if _, err := time.Parse("2006-01-02", q.date); err == nil {
+		return q.day()
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q", q.date)
+}
+
+// formatTime formats an event time for the output, or a dash if the event
+// does not happen.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format("2006-01-02 15:04:05 -0700")
+}
+
+// json reports whether the output is JSON.
+func (q *query) json() bool {
+	return q.format == "json"
+}
+
+// writeJSON prints v as indented JSON.
+func writeJSON(v interface{}) error {
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Printf("%s\n", out)
+	return err
+}
+
+// jsonTime formats t as RFC 3339 for the JSON output, or null if the event
+// does not happen.
+func jsonTime(t time.Time) *string {
+	if t.IsZero() {
+		return nil
+	}
+	s := t.Format(time.RFC3339)
+	return &s
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cfw011566/sunevent"
+)
+
+// run runs the command name with args, without a configuration file, and
+// returns what it printed.
+func run(t *testing.T, name string, args ...string) (string, error) {
+	t.Helper()
+	defer os.Setenv(configEnv, os.Getenv(configEnv))
+	os.Setenv(configEnv, filepath.Join(os.TempDir(), "sunevent-test-missing.toml"))
+
+	return capture(t, func() error {
+		for _, c := range commands {
+			if c.name == name {
+				return c.run(args)
+			}
+		}
+		t.Fatalf("no command %q", name)
+		return nil
+	})
+}
+
+// capture calls f and returns what it printed on standard output.
+func capture(t *testing.T, f func() error) (string, error) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	out := make(chan string)
+	go func() {
+		var b bytes.Buffer
+		io.Copy(&b, r)
+		out <- b.String()
+	}()
+	err = f()
+	w.Close()
+	return <-out, err
+}
+
+func TestRiseSet(t *testing.T) {
+	taipei, err := time.LoadLocation("Asia/Taipei")
+	if err != nil {
+		t.Skipf("time zone Asia/Taipei: %v", err)
+	}
+	date := time.Date(2026, time.June, 21, 12, 0, 0, 0, taipei)
+	for name, e := range map[string]sunevent.EventType{"rise": sunevent.Sunrise, "set": sunevent.Sunset} {
+		want, _ := e.On(date, 22.63, 120.30)
+		got, err := run(t, name, "--lat", "22.63", "--lon", "120.30", "--tz", "Asia/Taipei", "--date", "2026-06-21")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want.Format("2006-01-02 15:04:05 +0800")+"\n" {
+			t.Errorf("%s printed %q, want %s", name, got, want)
+		}
+	}
+
+	// polar night at Longyearbyen
+	_, err = run(t, "rise", "--lat", "78.22", "--lon", "15.65", "--date", "2026-12-21")
+	if err != sunevent.ErrSunNeverRises || exitStatus(err) != 1 {
+		t.Errorf("rise in polar night: %v, exit status %d", err, exitStatus(err))
+	}
+}
+
+func TestFlagErrors(t *testing.T) {
+	for _, args := range [][]string{
+		{"--lat", "22.63"},
+		{"--lat", "91", "--lon", "0"},
+		{"--lat", "0", "--lon", "181"},
+		{"--lat", "0", "--lon", "0", "--date", "21 June"},
+		{"--lat", "0", "--lon", "0", "--tz", "Nowhere/Special"},
+		{"--lat", "0", "--lon", "0", "extra"},
+	} {
+		if _, err := run(t, "rise", args...); err == nil || exitStatus(err) != 2 {
+			t.Errorf("rise %s: %v, want an error with exit status 2", strings.Join(args, " "), err)
+		}
+	}
+}
+
+func TestTwilightDayPosition(t *testing.T) {
+	args := []string{"--lat", "59.33", "--lon", "18.07", "--tz", "UTC", "--date", "2026-06-21"}
+
+	// Stockholm has no astronomical night at midsummer
+	out, err := run(t, "twilight", append(args, "--kind", "astronomical")...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "dawn  -\ndusk  -\n" {
+		t.Errorf("astronomical twilight printed %q, want dashes", out)
+	}
+	if _, err := run(t, "twilight", append(args, "--kind", "golden")...); err == nil {
+		t.Error("twilight accepted an unknown kind")
+	}
+
+	out, err = run(t, "day", args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	words := strings.Join(strings.Fields(out), " ")
+	for _, want := range []string{"date 2026-06-21 type normal astronomical_dawn - nautical_dawn -", "day_length 18h15m"} {
+		if !strings.Contains(words, want) {
+			t.Errorf("day printed\n%s\nwithout %q", out, want)
+		}
+	}
+
+	// the Sun is due south at noon on the meridian of Greenwich
+	out, err = run(t, "position", "--lat", "51.48", "--lon", "0", "--tz", "UTC", "--date", "2026-06-21T12:02")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if words := strings.Join(strings.Fields(out), " "); !strings.Contains(words, "azimuth 18") || !strings.Contains(words, "altitude 61.") {
+		t.Errorf("position printed\n%s", out)
+	}
+}
+
+func TestFormatJSON(t *testing.T) {
+	// in polar night the missing sunrise is null rather than an error
+	out, err := run(t, "rise", "--lat", "78.22", "--lon", "15.65", "--tz", "UTC", "--date", "2026-12-21", "--format", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var event eventJSON
+	if err := json.Unmarshal([]byte(out), &event); err != nil {
+		t.Fatal(err)
+	}
+	if event != (eventJSON{Event: "sunrise", Date: "2026-12-21", DayType: "polar_night"}) {
+		t.Errorf("rise printed %s", out)
+	}
+
+	out, err = run(t, "day", "--lat", "22.63", "--lon", "120.30", "--tz", "UTC", "--date", "2026-06-21", "--format", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var day dayJSON
+	if err := json.Unmarshal([]byte(out), &day); err != nil {
+		t.Fatal(err)
+	}
+	if day.Sunrise == nil || day.DayType != "normal" || day.DayLength < 13*3600 || day.DayLength > 14*3600 {
+		t.Errorf("day printed %s", out)
+	} else if _, err := time.Parse(time.RFC3339, *day.Sunrise); err != nil {
+		t.Errorf("sunrise %q: %v", *day.Sunrise, err)
+	}
+
+	out, err = run(t, "table", "--lat", "22.63", "--lon", "120.30", "--month", "2026-02", "--format", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var days []dayJSON
+	if err := json.Unmarshal([]byte(out), &days); err != nil {
+		t.Fatal(err)
+	}
+	if len(days) != 28 || days[0].Date != "2026-02-01" {
+		t.Errorf("table printed %d days, want February 2026", len(days))
+	}
+
+	if _, err := run(t, "rise", "--lat", "0", "--lon", "0", "--format", "yaml"); err == nil {
+		t.Error("rise accepted --format yaml")
+	}
+}
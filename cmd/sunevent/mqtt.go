@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/cfw011566/sunevent/mqtt"
+)
+
+// reconnectDelay is the wait before connecting again to the broker.
+const reconnectDelay = 10 * time.Second
+
+// unsafeTopic matches the characters left out of the default topic name.
+var unsafeTopic = regexp.MustCompile(`[^a-z0-9_-]+`)
+
+// runMQTT publishes the events of the location to an MQTT broker until
+// interrupted, connecting again whenever the connection is lost. The
+// password is read from $SUNEVENT_MQTT_PASSWORD rather than a flag, which
+// other users could see.
+func runMQTT(args []string) error {
+	var q query
+	fs := newFlagSet("mqtt", &q)
+	broker := fs.String("broker", "tcp://localhost:1883", "broker as tcp://host:port or tls://host:port")
+	name := fs.String("name", "", "name of the location in the topics (default the location name or home)")
+	prefix := fs.String("prefix", "", "topic prefix (default sunevent/<name>)")
+	user := fs.String("user", "", "user name at the broker")
+	discovery := fs.String("discovery", "", "Home Assistant discovery prefix, such as homeassistant (default no discovery)")
+	interval := fs.Duration("interval", time.Minute, "interval of the position updates")
+	if err := q.parse(fs, args); err != nil {
+		return err
+	}
+
+	if *name == "" {
+		*name = strings.Trim(unsafeTopic.ReplaceAllString(strings.ToLower(q.name), "_"), "_")
+		if *name == "" {
+			*name = "home"
+		}
+	}
+	opts := []mqtt.Option{mqtt.WithPositionInterval(*interval)}
+	if *user != "" {
+		opts = append(opts, mqtt.WithCredentials(*user, os.Getenv("SUNEVENT_MQTT_PASSWORD")))
+	}
+	if *prefix != "" {
+		opts = append(opts, mqtt.WithTopicPrefix(*prefix))
+	}
+	if *discovery != "" {
+		opts = append(opts, mqtt.WithDiscovery(*discovery))
+	}
+	p := mqtt.NewPublisher(*broker, *name, q.latitude, q.longitude, opts...)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		interrupt := make(chan os.Signal, 1)
+		signal.Notify(interrupt, os.Interrupt)
+		<-interrupt
+		cancel()
+	}()
+
+	for {
+		log.Printf("publishing %s to %s", *name, *broker)
+		err := p.Run(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		log.Printf("%v; connecting again in %v", err, reconnectDelay)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
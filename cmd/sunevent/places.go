@@ -0,0 +1,273 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// place is an entry of the gazetteer.
+type place struct {
+	Name      string
+	Country   string // ISO 3166 code
+	Latitude  float64
+	Longitude float64
+	TimeZone  string
+}
+
+// gazetteer holds major cities, and those at high latitudes where the Sun
+// is most interesting, to two decimals of a degree.
+var gazetteer = []place{
+	// Taiwan and East Asia
+	{"Taipei", "TW", 25.03, 121.57, "Asia/Taipei"},
+	{"Kaohsiung", "TW", 22.63, 120.30, "Asia/Taipei"},
+	{"Taichung", "TW", 24.15, 120.67, "Asia/Taipei"},
+	{"Tainan", "TW", 22.99, 120.21, "Asia/Taipei"},
+	{"Hsinchu", "TW", 24.80, 120.97, "Asia/Taipei"},
+	{"Tokyo", "JP", 35.68, 139.69, "Asia/Tokyo"},
+	{"Osaka", "JP", 34.69, 135.50, "Asia/Tokyo"},
+	{"Sapporo", "JP", 43.06, 141.35, "Asia/Tokyo"},
+	{"Seoul", "KR", 37.57, 126.98, "Asia/Seoul"},
+	{"Busan", "KR", 35.18, 129.08, "Asia/Seoul"},
+	{"Beijing", "CN", 39.90, 116.41, "Asia/Shanghai"},
+	{"Shanghai", "CN", 31.23, 121.47, "Asia/Shanghai"},
+	{"Guangzhou", "CN", 23.13, 113.26, "Asia/Shanghai"},
+	{"Shenzhen", "CN", 22.54, 114.06, "Asia/Shanghai"},
+	{"Chengdu", "CN", 30.57, 104.07, "Asia/Shanghai"},
+	{"Hong Kong", "HK", 22.32, 114.17, "Asia/Hong_Kong"},
+	{"Macau", "MO", 22.20, 113.54, "Asia/Macau"},
+	{"Ulaanbaatar", "MN", 47.89, 106.91, "Asia/Ulaanbaatar"},
+
+	// South and Southeast Asia
+	{"Singapore", "SG", 1.35, 103.82, "Asia/Singapore"},
+	{"Kuala Lumpur", "MY", 3.14, 101.69, "Asia/Kuala_Lumpur"},
+	{"Bangkok", "TH", 13.76, 100.50, "Asia/Bangkok"},
+	{"Hanoi", "VN", 21.03, 105.85, "Asia/Ho_Chi_Minh"},
+	{"Ho Chi Minh City", "VN", 10.82, 106.63, "Asia/Ho_Chi_Minh"},
+	{"Manila", "PH", 14.60, 120.98, "Asia/Manila"},
+	{"Jakarta", "ID", -6.21, 106.85, "Asia/Jakarta"},
+	{"Delhi", "IN", 28.61, 77.21, "Asia/Kolkata"},
+	{"Mumbai", "IN", 19.08, 72.88, "Asia/Kolkata"},
+	{"Bangalore", "IN", 12.97, 77.59, "Asia/Kolkata"},
+	{"Kolkata", "IN", 22.57, 88.36, "Asia/Kolkata"},
+	{"Karachi", "PK", 24.86, 67.01, "Asia/Karachi"},
+	{"Dhaka", "BD", 23.81, 90.41, "Asia/Dhaka"},
+	{"Kathmandu", "NP", 27.72, 85.32, "Asia/Kathmandu"},
+
+	// Central and West Asia
+	{"Tashkent", "UZ", 41.30, 69.24, "Asia/Tashkent"},
+	{"Almaty", "KZ", 43.24, 76.89, "Asia/Almaty"},
+	{"Dubai", "AE", 25.20, 55.27, "Asia/Dubai"},
+	{"Riyadh", "SA", 24.71, 46.68, "Asia/Riyadh"},
+	{"Tehran", "IR", 35.69, 51.39, "Asia/Tehran"},
+	{"Istanbul", "TR", 41.01, 28.98, "Europe/Istanbul"},
+	{"Jerusalem", "IL", 31.77, 35.21, "Asia/Jerusalem"},
+	{"Tel Aviv", "IL", 32.09, 34.78, "Asia/Jerusalem"},
+
+	// Europe
+	{"London", "GB", 51.51, -0.13, "Europe/London"},
+	{"Edinburgh", "GB", 55.95, -3.19, "Europe/London"},
+	{"Dublin", "IE", 53.35, -6.26, "Europe/Dublin"},
+	{"Paris", "FR", 48.86, 2.35, "Europe/Paris"},
+	{"Berlin", "DE", 52.52, 13.40, "Europe/Berlin"},
+	{"Munich", "DE", 48.14, 11.58, "Europe/Berlin"},
+	{"Hamburg", "DE", 53.55, 9.99, "Europe/Berlin"},
+	{"Madrid", "ES", 40.42, -3.70, "Europe/Madrid"},
+	{"Barcelona", "ES", 41.39, 2.17, "Europe/Madrid"},
+	{"Lisbon", "PT", 38.72, -9.14, "Europe/Lisbon"},
+	{"Rome", "IT", 41.90, 12.50, "Europe/Rome"},
+	{"Milan", "IT", 45.46, 9.19, "Europe/Rome"},
+	{"Amsterdam", "NL", 52.37, 4.90, "Europe/Amsterdam"},
+	{"Brussels", "BE", 50.85, 4.35, "Europe/Brussels"},
+	{"Zurich", "CH", 47.38, 8.54, "Europe/Zurich"},
+	{"Geneva", "CH", 46.20, 6.14, "Europe/Zurich"},
+	{"Vienna", "AT", 48.21, 16.37, "Europe/Vienna"},
+	{"Prague", "CZ", 50.08, 14.44, "Europe/Prague"},
+	{"Warsaw", "PL", 52.23, 21.01, "Europe/Warsaw"},
+	{"Budapest", "HU", 47.50, 19.04, "Europe/Budapest"},
+	{"Bucharest", "RO", 44.43, 26.10, "Europe/Bucharest"},
+	{"Athens", "GR", 37.98, 23.73, "Europe/Athens"},
+	{"Kyiv", "UA", 50.45, 30.52, "Europe/Kiev"},
+	{"Moscow", "RU", 55.76, 37.62, "Europe/Moscow"},
+	{"Saint Petersburg", "RU", 59.93, 30.36, "Europe/Moscow"},
+	{"Murmansk", "RU", 68.97, 33.08, "Europe/Moscow"},
+	{"Copenhagen", "DK", 55.68, 12.57, "Europe/Copenhagen"},
+	{"Oslo", "NO", 59.91, 10.75, "Europe/Oslo"},
+	{"Tromso", "NO", 69.65, 18.96, "Europe/Oslo"},
+	{"Longyearbyen", "SJ", 78.22, 15.65, "Arctic/Longyearbyen"},
+	{"Stockholm", "SE", 59.33, 18.07, "Europe/Stockholm"},
+	{"Kiruna", "SE", 67.86, 20.23, "Europe/Stockholm"},
+	{"Helsinki", "FI", 60.17, 24.94, "Europe/Helsinki"},
+	{"Rovaniemi", "FI", 66.50, 25.73, "Europe/Helsinki"},
+	{"Reykjavik", "IS", 64.15, -21.94, "Atlantic/Reykjavik"},
+
+	// Africa
+	{"Cairo", "EG", 30.04, 31.24, "Africa/Cairo"},
+	{"Casablanca", "MA", 33.57, -7.59, "Africa/Casablanca"},
+	{"Dakar", "SN", 14.72, -17.47, "Africa/Dakar"},
+	{"Accra", "GH", 5.60, -0.19, "Africa/Accra"},
+	{"Lagos", "NG", 6.52, 3.38, "Africa/Lagos"},
+	{"Addis Ababa", "ET", 9.03, 38.74, "Africa/Addis_Ababa"},
+	{"Nairobi", "KE", -1.29, 36.82, "Africa/Nairobi"},
+	{"Kinshasa", "CD", -4.44, 15.27, "Africa/Kinshasa"},
+	{"Johannesburg", "ZA", -26.20, 28.05, "Africa/Johannesburg"},
+	{"Cape Town", "ZA", -33.92, 18.42, "Africa/Johannesburg"},
+
+	// North America
+	{"New York", "US", 40.71, -74.01, "America/New_York"},
+	{"Boston", "US", 42.36, -71.06, "America/New_York"},
+	{"Philadelphia", "US", 39.95, -75.17, "America/New_York"},
+	{"Washington", "US", 38.91, -77.04, "America/New_York"},
+	{"Atlanta", "US", 33.75, -84.39, "America/New_York"},
+	{"Miami", "US", 25.76, -80.19, "America/New_York"},
+	{"Chicago", "US", 41.88, -87.63, "America/Chicago"},
+	{"Houston", "US", 29.76, -95.37, "America/Chicago"},
+	{"Dallas", "US", 32.78, -96.80, "America/Chicago"},
+	{"New Orleans", "US", 29.95, -90.07, "America/Chicago"},
+	{"Denver", "US", 39.74, -104.99, "America/Denver"},
+	{"Salt Lake City", "US", 40.76, -111.89, "America/Denver"},
+	{"Phoenix", "US", 33.45, -112.07, "America/Phoenix"},
+	{"Las Vegas", "US", 36.17, -115.14, "America/Los_Angeles"},
+	{"Los Angeles", "US", 34.05, -118.24, "America/Los_Angeles"},
+	{"San Francisco", "US", 37.77, -122.42, "America/Los_Angeles"},
+	{"Portland", "US", 45.52, -122.68, "America/Los_Angeles"},
+	{"Seattle", "US", 47.61, -122.33, "America/Los_Angeles"},
+	{"Anchorage", "US", 61.22, -149.90, "America/Anchorage"},
+	{"Fairbanks", "US", 64.84, -147.72, "America/Anchorage"},
+	{"Utqiagvik", "US", 71.29, -156.79, "America/Anchorage"},
+	{"Honolulu", "US", 21.31, -157.86, "Pacific/Honolulu"},
+	{"Toronto", "CA", 43.65, -79.38, "America/Toronto"},
+	{"Montreal", "CA", 45.50, -73.57, "America/Toronto"},
+	{"Calgary", "CA", 51.05, -114.07, "America/Edmonton"},
+	{"Vancouver", "CA", 49.28, -123.12, "America/Vancouver"},
+	{"Mexico City", "MX", 19.43, -99.13, "America/Mexico_City"},
+	{"Havana", "CU", 23.11, -82.37, "America/Havana"},
+
+	// South America
+	{"Bogota", "CO", 4.71, -74.07, "America/Bogota"},
+	{"Caracas", "VE", 10.48, -66.90, "America/Caracas"},
+	{"Lima", "PE", -12.05, -77.04, "America/Lima"},
+	{"Santiago", "CL", -33.45, -70.67, "America/Santiago"},
+	{"Buenos Aires", "AR", -34.60, -58.38, "America/Argentina/Buenos_Aires"},
+	{"Ushuaia", "AR", -54.80, -68.30, "America/Argentina/Ushuaia"},
+	{"Sao Paulo", "BR", -23.55, -46.63, "America/Sao_Paulo"},
+	{"Rio de Janeiro", "BR", -22.91, -43.17, "America/Sao_Paulo"},
+
+	// Oceania and Antarctica
+	{"Sydney", "AU", -33.87, 151.21, "Australia/Sydney"},
+	{"Melbourne", "AU", -37.81, 144.96, "Australia/Melbourne"},
+	{"Brisbane", "AU", -27.47, 153.03, "Australia/Brisbane"},
+	{"Adelaide", "AU", -34.93, 138.60, "Australia/Adelaide"},
+	{"Perth", "AU", -31.95, 115.86, "Australia/Perth"},
+	{"Darwin", "AU", -12.46, 130.84, "Australia/Darwin"},
+	{"Hobart", "AU", -42.88, 147.33, "Australia/Hobart"},
+	{"Auckland", "NZ", -36.85, 174.76, "Pacific/Auckland"},
+	{"Wellington", "NZ", -41.29, 174.78, "Pacific/Auckland"},
+	{"McMurdo Station", "AQ", -77.85, 166.67, "Antarctica/McMurdo"},
+}
+
+// gazetteerEnv names the environment variable holding the path of a CSV
+// file of further places, with lines of name,country,lat,lon,timezone.
+// Its entries are searched before the built-in ones, so it can add
+// villages or correct a city.
+const gazetteerEnv = "SUNEVENT_GAZETTEER"
+
+// lookupPlace finds a place by name, optionally followed by a comma and
+// the country code to choose between places of the same name, as in
+// "Portland" or "Kaohsiung, TW". Case and the accents of common letters
+// are ignored.
+func lookupPlace(query string) (place, error) {
+	places := gazetteer
+	if path := os.Getenv(gazetteerEnv); path != "" {
+		extra, err := readGazetteer(path)
+		if err != nil {
+			return place{}, err
+		}
+		places = append(extra, places...)
+	}
+
+	name, country := query, ""
+	if i := strings.LastIndex(query, ","); i >= 0 {
+		name, country = query[:i], strings.TrimSpace(query[i+1:])
+	}
+	name = foldName(name)
+
+	var found []place
+	seen := make(map[string]bool)
+	for _, p := range places {
+		if foldName(p.Name) != name || (country != "" && !strings.EqualFold(p.Country, country)) {
+			continue
+		}
+		// a place of the file hides the built-in one of the same name
+		if key := strings.ToUpper(p.Country); !seen[key] {
+			seen[key] = true
+			found = append(found, p)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return place{}, fmt.Errorf("unknown place %q; give --lat and --lon or add it to $%s", query, gazetteerEnv)
+	case 1:
+		return found[0], nil
+	}
+	names := make([]string, len(found))
+	for i, p := range found {
+		names[i] = p.Name + ", " + p.Country
+	}
+	return place{}, fmt.Errorf("place %q is ambiguous: %s", query, strings.Join(names, "; "))
+}
+
+// nameFolder removes the accents of the letters common in place names.
+var nameFolder = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ä", "a", "ã", "a", "å", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "ö", "o", "õ", "o", "ø", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+)
+
+// foldName returns the form of a place name used for matching.
+func foldName(name string) string {
+	return nameFolder.Replace(strings.Join(strings.Fields(strings.ToLower(name)), " "))
+}
+
+// readGazetteer reads a CSV file of places. A first line starting with
+// "name" is taken as a header.
+func readGazetteer(path string) ([]place, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = 5
+	r.TrimLeadingSpace = true
+	var places []place
+	for line := 1; ; line++ {
+		rec, err := r.Read()
+		if err == io.EOF {
+			return places, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		if line == 1 && strings.EqualFold(rec[0], "name") {
+			continue
+		}
+		lat, err := strconv.ParseFloat(rec[2], 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid latitude %q", path, line, rec[2])
+		}
+		lon, err := strconv.ParseFloat(rec[3], 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid longitude %q", path, line, rec[3])
+		}
+		places = append(places, place{Name: rec[0], Country: rec[1], Latitude: lat, Longitude: lon, TimeZone: rec[4]})
+	}
+}
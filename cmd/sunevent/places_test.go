@@ -0,0 +1,80 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLookupPlace(t *testing.T) {
+	for _, query := range []string{"Kaohsiung", "kaohsiung, tw", "  KAOHSIUNG "} {
+		p, err := lookupPlace(query)
+		if err != nil || p.Latitude != 22.63 || p.TimeZone != "Asia/Taipei" {
+			t.Errorf("lookupPlace(%q) = %+v, %v", query, p, err)
+		}
+	}
+	if p, err := lookupPlace("Tromsø"); err != nil || p.Country != "NO" {
+		t.Errorf("lookupPlace(Tromsø) = %+v, %v", p, err)
+	}
+	for _, query := range []string{"Atlantis", "Kaohsiung, JP"} {
+		if _, err := lookupPlace(query); err == nil {
+			t.Errorf("lookupPlace(%q) found a place", query)
+		}
+	}
+}
+
+func TestGazetteerFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sunevent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "places.csv")
+	data := "name,country,lat,lon,timezone\n" +
+		"Portland,US-ME,43.66,-70.26,America/New_York\n" +
+		"Kaohsiung,TW,22.6,120.3,Asia/Taipei\n"
+	if err := ioutil.WriteFile(path, []byte(data), 0666); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Setenv(gazetteerEnv, os.Getenv(gazetteerEnv))
+	os.Setenv(gazetteerEnv, path)
+
+	// the file corrects a built-in place
+	if p, err := lookupPlace("Kaohsiung"); err != nil || p.Latitude != 22.6 {
+		t.Errorf("lookupPlace(Kaohsiung) = %+v, %v, want the place of the file", p, err)
+	}
+	// and adds one of the same name elsewhere
+	if _, err := lookupPlace("Portland"); err == nil || !strings.Contains(err.Error(), "ambiguous") {
+		t.Errorf("lookupPlace(Portland): %v, want ambiguous", err)
+	}
+	if p, err := lookupPlace("Portland, US-ME"); err != nil || p.TimeZone != "America/New_York" {
+		t.Errorf("lookupPlace(Portland, US-ME) = %+v, %v", p, err)
+	}
+
+	if err := ioutil.WriteFile(path, []byte("Nowhere,XX,north,0,UTC\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := lookupPlace("Kaohsiung"); err == nil {
+		t.Error("an invalid file was accepted")
+	}
+}
+
+func TestPlaceFlag(t *testing.T) {
+	if _, err := time.LoadLocation("Asia/Taipei"); err != nil {
+		t.Skipf("time zone Asia/Taipei: %v", err)
+	}
+	// the time zone of the place is the default of --tz
+	out, err := run(t, "rise", "--place", "Kaohsiung", "--date", "2026-06-21")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(out, " +0800\n") {
+		t.Errorf("rise printed %q, want Taipei time", out)
+	}
+	if _, err := run(t, "rise", "--place", "Kaohsiung", "--lat", "0", "--lon", "0"); err == nil {
+		t.Error("--place was accepted with --lat and --lon")
+	}
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cfw011566/sunevent"
+)
+
+func runRise(args []string) error {
+	return runEvent("rise", sunevent.Sunrise, args)
+}
+
+func runSet(args []string) error {
+	return runEvent("set", sunevent.Sunset, args)
+}
+
+// runEvent prints the time of a single event.
+func runEvent(name string, e sunevent.EventType, args []string) error {
+	var q query
+	fs := newFlagSet(name, &q)
+	q.dateFlag(fs, dateUsage)
+	if err := q.parse(fs, args); err != nil {
+		return err
+	}
+	date, err := q.day()
+	if err != nil {
+		return err
+	}
+	t, err := e.On(date, q.latitude, q.longitude)
+	if q.json() {
+		return writeJSON(eventJSON{
+			Event:   e.String(),
+			Date:    date.Format("2006-01-02"),
+			DayType: dayType(date, q.latitude, q.longitude).String(),
+			Time:    jsonTime(t),
+		})
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Println(formatTime(t))
+	return nil
+}
+
+type eventJSON struct {
+	Event   string  `json:"event"`
+	Date    string  `json:"date"`
+	DayType string  `json:"day_type"`
+	Time    *string `json:"time"`
+}
+
+// dayType returns whether the Sun rises and sets on the date of date.
+func dayType(date time.Time, latitude, longitude float64) sunevent.DayType {
+	switch {
+	case sunevent.IsPolarDay(date, latitude, longitude):
+		return sunevent.PolarDay
+	case sunevent.IsPolarNight(date, latitude, longitude):
+		return sunevent.PolarNight
+	}
+	return sunevent.NormalDay
+}
+
+// twilightKinds maps the values of --kind to the dawn and dusk events.
+var twilightKinds = map[string][2]sunevent.EventType{
+	"civil":        {sunevent.CivilDawn, sunevent.CivilDusk},
+	"nautical":     {sunevent.NauticalDawn, sunevent.NauticalDusk},
+	"astronomical": {sunevent.AstronomicalDawn, sunevent.AstronomicalDusk},
+}
+
+func runTwilight(args []string) error {
+	var q query
+	fs := newFlagSet("twilight", &q)
+	q.dateFlag(fs, dateUsage)
+	kind := fs.String("kind", "civil", "twilight: civil, nautical or astronomical")
+	if err := q.parse(fs, args); err != nil {
+		return err
+	}
+	events, ok := twilightKinds[*kind]
+	if !ok {
+		return fmt.Errorf("unknown twilight %q", *kind)
+	}
+	date, err := q.day()
+	if err != nil {
+		return err
+	}
+
+	dawn, _ := events[0].On(date, q.latitude, q.longitude)
+	dusk, _ := events[1].On(date, q.latitude, q.longitude)
+	if q.json() {
+		return writeJSON(twilightJSON{
+			Kind:    *kind,
+			Date:    date.Format("2006-01-02"),
+			DayType: dayType(date, q.latitude, q.longitude).String(),
+			Dawn:    jsonTime(dawn),
+			Dusk:    jsonTime(dusk),
+		})
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "dawn\t%s\n", formatTime(dawn))
+	fmt.Fprintf(w, "dusk\t%s\n", formatTime(dusk))
+	return w.Flush()
+}
+
+type twilightJSON struct {
+	Kind    string  `json:"kind"`
+	Date    string  `json:"date"`
+	DayType string  `json:"day_type"`
+	Dawn    *string `json:"dawn"`
+	Dusk    *string `json:"dusk"`
+}
+
+// dayEvents lists the events of a day in the order they happen.
+var dayEvents = []sunevent.EventType{
+	sunevent.AstronomicalDawn,
+	sunevent.NauticalDawn,
+	sunevent.CivilDawn,
+	sunevent.Sunrise,
+	sunevent.SolarNoon,
+	sunevent.Sunset,
+	sunevent.CivilDusk,
+	sunevent.NauticalDusk,
+	sunevent.AstronomicalDusk,
+}
+
+// dayEventTime returns the field of d holding the event e.
+func dayEventTime(d sunevent.SunDay, e sunevent.EventType) time.Time {
+	switch e {
+	case sunevent.AstronomicalDawn:
+		return d.AstronomicalDawn
+	case sunevent.NauticalDawn:
+		return d.NauticalDawn
+	case sunevent.CivilDawn:
+		return d.CivilDawn
+	case sunevent.Sunrise:
+		return d.Sunrise
+	case sunevent.SolarNoon:
+		return d.SolarNoon
+	case sunevent.Sunset:
+		return d.Sunset
+	case sunevent.CivilDusk:
+		return d.CivilDusk
+	case sunevent.NauticalDusk:
+		return d.NauticalDusk
+	case sunevent.AstronomicalDusk:
+		return d.AstronomicalDusk
+	}
+	return time.Time{}
+}
+
+func runDay(args []string) error {
+	var q query
+	fs := newFlagSet("day", &q)
+	q.dateFlag(fs, dateUsage)
+	if err := q.parse(fs, args); err != nil {
+		return err
+	}
+	date, err := q.day()
+	if err != nil {
+		return err
+	}
+	d := sunevent.SunDayOn(date, q.latitude, q.longitude)
+	if q.json() {
+		return writeJSON(newDayJSON(d))
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "date\t%s\n", d.Date.Format("2006-01-02"))
+	fmt.Fprintf(w, "type\t%s\n", d.Type)
+	for _, e := range dayEvents {
+		fmt.Fprintf(w, "%s\t%s\n", e, formatTime(dayEventTime(d, e)))
+	}
+	fmt.Fprintf(w, "day_length\t%s\n", d.DayLength.Round(time.Second))
+	return w.Flush()
+}
+
+// dayJSON is a SunDay in the JSON output.
+type dayJSON struct {
+	Date             string  `json:"date"`
+	DayType          string  `json:"day_type"`
+	AstronomicalDawn *string `json:"astronomical_dawn"`
+	NauticalDawn     *string `json:"nautical_dawn"`
+	CivilDawn        *string `json:"civil_dawn"`
+	Sunrise          *string `json:"sunrise"`
+	SolarNoon        *string `json:"solar_noon"`
+	Sunset           *string `json:"sunset"`
+	CivilDusk        *string `json:"civil_dusk"`
+	NauticalDusk     *string `json:"nautical_dusk"`
+	AstronomicalDusk *string `json:"astronomical_dusk"`
+
+	// DayLength is in seconds, 86400 on a polar day.
+	DayLength int64 `json:"day_length"`
+}
+
+func newDayJSON(d sunevent.SunDay) dayJSON {
+	return dayJSON{
+		Date:             d.Date.Format("2006-01-02"),
+		DayType:          d.Type.String(),
+		AstronomicalDawn: jsonTime(d.AstronomicalDawn),
+		NauticalDawn:     jsonTime(d.NauticalDawn),
+		CivilDawn:        jsonTime(d.CivilDawn),
+		Sunrise:          jsonTime(d.Sunrise),
+		SolarNoon:        jsonTime(d.SolarNoon),
+		Sunset:           jsonTime(d.Sunset),
+		CivilDusk:        jsonTime(d.CivilDusk),
+		NauticalDusk:     jsonTime(d.NauticalDusk),
+		AstronomicalDusk: jsonTime(d.AstronomicalDusk),
+		DayLength:        int64(d.DayLength.Round(time.Second) / time.Second),
+	}
+}
+
+func runPosition(args []string) error {
+	var q query
+	fs := newFlagSet("position", &q)
+	q.dateFlag(fs, "time as 2006-01-02T15:04 or RFC 3339 (default now)")
+	if err := q.parse(fs, args); err != nil {
+		return err
+	}
+	t, err := q.instant()
+	if err != nil {
+		return err
+	}
+	p := sunevent.SunPosition(t, q.latitude, q.longitude)
+	if q.json() {
+		return writeJSON(positionJSON{
+			Time:     t.Format(time.RFC3339),
+			Azimuth:  p.Azimuth,
+			Altitude: p.Altitude,
+		})
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "time\t%s\n", formatTime(t))
+	fmt.Fprintf(w, "azimuth\t%.2f\n", p.Azimuth)
+	fmt.Fprintf(w, "altitude\t%.2f\n", p.Altitude)
+	return w.Flush()
+}
+
+type positionJSON struct {
+	Time     string  `json:"time"`
+	Azimuth  float64 `json:"azimuth"`
+	Altitude float64 `json:"altitude"`
+}
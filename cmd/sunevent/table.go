@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cfw011566/sunevent"
+	"github.com/cfw011566/sunevent/internal/format"
+)
+
+// runTable prints a month, or with --year a whole year, as an almanac
+// table with a row per day.
+func runTable(args []string) error {
+	var q query
+	fs := newFlagSet("table", &q)
+	q.dateFlag(fs, dateUsage)
+	month := fs.String("month", "", "month as 2006-01 (default the month of --date)")
+	year := fs.Int("year", 0, "print every month of the year")
+	if err := q.parse(fs, args); err != nil {
+		return err
+	}
+
+	var first time.Time
+	months := 1
+	switch {
+	case *year != 0:
+		first = time.Date(*year, time.January, 1, 12, 0, 0, 0, q.loc)
+		months = 12
+	case *month != "":
+		m, err := time.ParseInLocation("2006-01", *month, q.loc)
+		if err != nil {
+			return fmt.Errorf("invalid month %q", *month)
+		}
+		first = time.Date(m.Year(), m.Month(), 1, 12, 0, 0, 0, q.loc)
+	default:
+		d, err := q.day()
+		if err != nil {
+			return err
+		}
+		first = time.Date(d.Year(), d.Month(), 1, 12, 0, 0, 0, q.loc)
+	}
+
+	if q.json() {
+		days := []dayJSON{}
+		for date := first; date.Before(first.AddDate(0, months, 0)); date = date.AddDate(0, 0, 1) {
+			days = append(days, newDayJSON(sunevent.SunDayOn(date, q.latitude, q.longitude)))
+		}
+		return writeJSON(days)
+	}
+
+	for i := 0; i < months; i++ {
+		if i > 0 {
+			fmt.Println()
+		}
+		if err := writeMonth(os.Stdout, first.AddDate(0, i, 0), q.latitude, q.longitude); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeMonth writes the table of the month of first, which is noon on its
+// first day.
+func writeMonth(out io.Writer, first time.Time, latitude, longitude float64) error {
+	fmt.Fprintf(out, "%s %d\n", first.Month(), first.Year())
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "date\tdawn\tsunrise\tnoon\tsunset\tdusk\tday length\t")
+	for date := first; date.Month() == first.Month(); date = date.AddDate(0, 0, 1) {
+		d := sunevent.SunDayOn(date, latitude, longitude)
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t\n",
+			date.Format("Mon 02"),
+			format.Clock(d.CivilDawn, "-"),
+			format.Clock(d.Sunrise, "-"),
+			format.Clock(d.SolarNoon, "-"),
+			format.Clock(d.Sunset, "-"),
+			format.Clock(d.CivilDusk, "-"),
+			format.Length(d.DayLength))
+	}
+	return w.Flush()
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteMonth(t *testing.T) {
+	// the Sun returns to Longyearbyen in the middle of February
+	cet := time.FixedZone("CET", 3600)
+	var b bytes.Buffer
+	if err := writeMonth(&b, time.Date(2026, time.February, 1, 12, 0, 0, 0, cet), 78.22, 15.65); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+	if len(lines) != 2+28 || lines[0] != "February 2026" {
+		t.Fatalf("table of %d lines starting %q", len(lines), lines[0])
+	}
+	first, last := strings.Fields(lines[2]), strings.Fields(lines[len(lines)-1])
+	if strings.Join(first[:2], " ") != "Sun 01" || first[3] != "-" || first[7] != "0h" {
+		t.Errorf("1 February = %q, want no sunrise", lines[2])
+	}
+	if strings.Join(last[:2], " ") != "Sat 28" || last[3] == "-" || last[7] == "0h" {
+		t.Errorf("28 February = %q, want a sunrise", lines[len(lines)-1])
+	}
+}
+
+func TestTable(t *testing.T) {
+	out, err := run(t, "table", "--lat", "22.63", "--lon", "120.30", "--tz", "UTC", "--year", "2026")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(out, "date  "); n != 12 {
+		t.Errorf("--year printed %d months", n)
+	}
+	if !strings.HasPrefix(out, "January 2026\n") || !strings.Contains(out, "\nDecember 2026\n") {
+		t.Errorf("--year printed\n%s", out)
+	}
+
+	if _, err := run(t, "table", "--lat", "22.63", "--lon", "120.30", "--month", "July"); err == nil {
+		t.Error("table accepted --month July")
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cfw011566/sunevent"
+)
+
+// watchEvents are the events counted down to by watch.
+var watchEvents = []sunevent.EventType{
+	sunevent.AstronomicalDawn,
+	sunevent.CivilDawn,
+	sunevent.Sunrise,
+	sunevent.SolarNoon,
+	sunevent.Sunset,
+	sunevent.CivilDusk,
+	sunevent.AstronomicalDusk,
+}
+
+// watchHorizon is how far ahead watch looks for the next events.
+const watchHorizon = 48 * time.Hour
+
+// runWatch redraws the phase, the position of the Sun and the countdowns
+// to the next events every second until interrupted. With --format json
+// it prints a line of JSON every second instead.
+func runWatch(args []string) error {
+	var q query
+	fs := newFlagSet("watch", &q)
+	if err := q.parse(fs, args); err != nil {
+		return err
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		now := time.Now().In(q.loc).Truncate(time.Second)
+		var err error
+		if q.json() {
+			err = writeWatchJSON(&q, now)
+		} else {
+			err = drawWatch(&q, now)
+		}
+		if err != nil {
+			return err
+		}
+
+		select {
+		case <-interrupt:
+			if !q.json() {
+				fmt.Println()
+			}
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// nextEvents returns the first occurrence of each of watchEvents after
+// now, in chronological order.
+func nextEvents(q *query, now time.Time) []sunevent.Event {
+	var next []sunevent.Event
+	seen := make(map[sunevent.EventType]bool)
+	for _, e := range sunevent.EventsBetween(q.latitude, q.longitude, now, now.Add(watchHorizon), watchEvents...) {
+		if e.Time.After(now) && !seen[e.Type] {
+			seen[e.Type] = true
+			next = append(next, e)
+		}
+	}
+	return next
+}
+
+// drawWatch clears the terminal and draws the state at now. The screen is
+// built first and written at once, so it does not flicker.
+func drawWatch(q *query, now time.Time) error {
+	var b bytes.Buffer
+	b.WriteString("\x1b[H\x1b[2J")
+
+	p := sunevent.SunPosition(now, q.latitude, q.longitude)
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "time\t%s\n", formatTime(now))
+	fmt.Fprintf(w, "phase\t%s\n", sunevent.PhaseAt(now, q.latitude, q.longitude))
+	fmt.Fprintf(w, "azimuth\t%.2f°\n", p.Azimuth)
+	fmt.Fprintf(w, "altitude\t%.2f°\n", p.Altitude)
+	fmt.Fprintln(w)
+
+	next := nextEvents(q, now)
+	if len(next) == 0 {
+		fmt.Fprintf(w, "no events in the next %s\n", watchHorizon)
+	}
+	for _, e := range next {
+		fmt.Fprintf(w, "%s\t%s\tin %s\n", e.Type, e.Time.Format("15:04:05"), formatCountdown(e.Time.Sub(now)))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	_, err := os.Stdout.Write(b.Bytes())
+	return err
+}
+
+// formatCountdown formats d as h:mm:ss.
+func formatCountdown(d time.Duration) string {
+	d = d.Round(time.Second)
+	return fmt.Sprintf("%d:%02d:%02d", int(d/time.Hour), int(d%time.Hour/time.Minute), int(d%time.Minute/time.Second))
+}
+
+type watchJSON struct {
+	Time     string           `json:"time"`
+	Phase    string           `json:"phase"`
+	Azimuth  float64          `json:"azimuth"`
+	Altitude float64          `json:"altitude"`
+	Next     []watchEventJSON `json:"next"`
+}
+
+type watchEventJSON struct {
+	Event string `json:"event"`
+	Time  string `json:"time"`
+
+	// In is the number of seconds until the event.
+	In int64 `json:"in"`
+}
+
+// writeWatchJSON prints the state at now as one line of JSON.
+func writeWatchJSON(q *query, now time.Time) error {
+	p := sunevent.SunPosition(now, q.latitude, q.longitude)
+	v := watchJSON{
+		Time:     now.Format(time.RFC3339),
+		Phase:    sunevent.PhaseAt(now, q.latitude, q.longitude).String(),
+		Azimuth:  p.Azimuth,
+		Altitude: p.Altitude,
+		Next:     []watchEventJSON{},
+	}
+	for _, e := range nextEvents(q, now) {
+		v.Next = append(v.Next, watchEventJSON{
+			Event: e.Type.String(),
+			Time:  e.Time.Format(time.RFC3339),
+			In:    int64(e.Time.Sub(now) / time.Second),
+		})
+	}
+	return json.NewEncoder(os.Stdout).Encode(v)
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatCountdown(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		0:                       "0:00:00",
+		1500 * time.Millisecond: "0:00:02",
+		time.Hour + 2*time.Minute + 3*time.Second: "1:02:03",
+		47 * time.Hour: "47:00:00",
+	} {
+		if got := formatCountdown(d); got != want {
+			t.Errorf("formatCountdown(%s) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+func TestNextEvents(t *testing.T) {
+	q := &query{latitude: 22.63, longitude: 120.30, loc: time.UTC}
+	now := time.Date(2026, time.June, 21, 4, 0, 0, 0, time.UTC) // noon in Kaohsiung
+	next := nextEvents(q, now)
+	if len(next) != len(watchEvents) {
+		t.Fatalf("%d events, want one of each of %v", len(next), watchEvents)
+	}
+	seen := make(map[string]bool)
+	for i, e := range next {
+		if !e.Time.After(now) || e.Time.Sub(now) > watchHorizon || seen[e.Type.String()] {
+			t.Errorf("event %v at %s", e.Type, e.Time)
+		}
+		if i > 0 && e.Time.Before(next[i-1].Time) {
+			t.Errorf("%v out of order", e.Type)
+		}
+		seen[e.Type.String()] = true
+	}
+
+	// polar night at Longyearbyen: only the twilights are left
+	q = &query{latitude: 78.22, longitude: 15.65, loc: time.UTC}
+	for _, e := range nextEvents(q, time.Date(2026, time.December, 21, 0, 0, 0, 0, time.UTC)) {
+		if e.Type.String() == "sunrise" || e.Type.String() == "sunset" {
+			t.Errorf("%v at %s in polar night", e.Type, e.Time)
+		}
+	}
+}
+
+func TestWriteWatchJSON(t *testing.T) {
+	q := &query{latitude: 22.63, longitude: 120.30, loc: time.UTC}
+	now := time.Date(2026, time.June, 21, 4, 0, 0, 0, time.UTC)
+	out, err := capture(t, func() error { return writeWatchJSON(q, now) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(out, "\n") != 1 {
+		t.Errorf("not one line: %q", out)
+	}
+	var v watchJSON
+	if err := json.Unmarshal([]byte(out), &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.Phase != "day" || v.Altitude < 80 || len(v.Next) != len(watchEvents) || v.Next[0].In <= 0 {
+		t.Errorf("watch printed %s", out)
+	}
+}
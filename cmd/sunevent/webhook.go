@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/cfw011566/sunevent"
+	"github.com/cfw011566/sunevent/webhook"
+)
+
+// stringList is a flag that may be repeated.
+type stringList []string
+
+func (l *stringList) String() string     { return strings.Join(*l, ",") }
+func (l *stringList) Set(s string) error { *l = append(*l, s); return nil }
+
+// runWebhook posts the events of the location to the URLs until
+// interrupted. The secret signing the deliveries is read from
+// $SUNEVENT_WEBHOOK_SECRET rather than a flag, which other users could
+// see.
+func runWebhook(args []string) error {
+	var q query
+	fs := newFlagSet("webhook", &q)
+	var urls stringList
+	fs.Var(&urls, "url", "URL to post the events to; may be repeated")
+	events := fs.String("events", "", "comma-separated events to post (default all)")
+	test := fs.Bool("test", false, "post the next occurrence of each event now and exit")
+	if err := q.parse(fs, args); err != nil {
+		return err
+	}
+	if len(urls) == 0 {
+		return fmt.Errorf("--url is required")
+	}
+
+	var types []sunevent.EventType
+	if *events != "" {
+		for _, s := range strings.Split(*events, ",") {
+			e, err := sunevent.ParseEventType(strings.TrimSpace(s))
+			if err != nil {
+				return err
+			}
+			types = append(types, e)
+		}
+	}
+	var hooks []webhook.Hook
+	for _, u := range urls {
+		hooks = append(hooks, webhook.Hook{URL: u, Events: types, Secret: os.Getenv("SUNEVENT_WEBHOOK_SECRET")})
+	}
+	d := webhook.NewDispatcher(q.latitude, q.longitude, hooks, webhook.WithLocation(q.name))
+
+	if *test {
+		now := time.Now()
+		next := make(map[sunevent.EventType]bool)
+		for _, e := range sunevent.EventsBetween(q.latitude, q.longitude, now, now.Add(48*time.Hour), types...) {
+			if next[e.Type] {
+				continue
+			}
+			next[e.Type] = true
+			if err := d.Deliver(context.Background(), e); err != nil {
+				return err
+			}
+			log.Printf("posted %s at %s", e.Type, formatTime(e.Time.In(q.loc)))
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		interrupt := make(chan os.Signal, 1)
+		signal.Notify(interrupt, os.Interrupt)
+		<-interrupt
+		cancel()
+	}()
+	log.Printf("posting events to %s", urls.String())
+	return d.Run(ctx)
+}
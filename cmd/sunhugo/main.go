@@ -0,0 +1,193 @@
+// Command sunhugo writes a year of sun events per location as Hugo data
+// files.
+//
+// Each location is written to <dir>/<name>.json (or .yaml), so a Hugo
+// template can read it as .Site.Data.sun.<name>:
+//
+//	sunhugo -year 2026 -tz Asia/Taipei -loc kaohsiung,22.63,120.30 -dir data/sun
+//
+//	{{ range .Site.Data.sun.kaohsiung.days }}
+//	  {{ .date }} {{ (time .sunrise).Format "15:04" }}
+//	{{ end }}
+//
+// Times are RFC 3339 strings in the requested time zone; events that do
+// not happen on a day are null, and the day's type tells whether it is a
+// polar day or polar night.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cfw011566/sunevent"
+)
+
+type location struct {
+	Name      string
+	Latitude  float64
+	Longitude float64
+}
+
+type locationList []location
+
+func (l *locationList) String() string {
+	names := make([]string, len(*l))
+	for i, loc := range *l {
+		names[i] = loc.Name
+	}
+	return strings.Join(names, " ")
+}
+
+func (l *locationList) Set(v string) error {
+	fields := strings.Split(v, ",")
+	if len(fields) != 3 {
+		return fmt.Errorf("location %q is not name,lat,lon", v)
+	}
+	lat, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil {
+		return err
+	}
+	lon, err := strconv.ParseFloat(fields[2], 64)
+	if err != nil {
+		return err
+	}
+	*l = append(*l, location{Name: fields[0], Latitude: lat, Longitude: lon})
+	return nil
+}
+
+type day struct {
+	Date      string  `json:"date"`
+	Type      string  `json:"type"`
+	Dawn      *string `json:"dawn"`
+	Sunrise   *string `json:"sunrise"`
+	Sunset    *string `json:"sunset"`
+	Dusk      *string `json:"dusk"`
+	DayLength *string `json:"day_length"`
+}
+
+type dataFile struct {
+	Name      string  `json:"name"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	TimeZone  string  `json:"timezone"`
+	Year      int     `json:"year"`
+	Days      []day   `json:"days"`
+}
+
+func main() {
+	var locations locationList
+	flag.Var(&locations, "loc", "location as name,lat,lon (repeatable)")
+	year := flag.Int("year", time.Now().Year(), "year to generate")
+	tz := flag.String("tz", "UTC", "IANA time zone of the generated times")
+	format := flag.String("format", "json", "output format: json or yaml")
+	dir := flag.String("dir", "data/sun", "output directory")
+	flag.Parse()
+
+	if len(locations) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *format != "json" && *format != "yaml" {
+		log.Fatalf("unknown format %q", *format)
+	}
+	loc, err := time.LoadLocation(*tz)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.MkdirAll(*dir, 0755); err != nil {
+		log.Fatal(err)
+	}
+
+	for _, l := range locations {
+		data := generate(l, *year, loc)
+
+		var out []byte
+		if *format == "json" {
+			out, err = json.MarshalIndent(data, "", "  ")
+			out = append(out, '\n')
+		} else {
+			out, err = marshalYAML(data)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		name := filepath.Join(*dir, l.Name+"."+*format)
+		if err := ioutil.WriteFile(name, out, 0644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+func generate(l location, year int, loc *time.Location) dataFile {
+	data := dataFile{
+		Name:      l.Name,
+		Latitude:  l.Latitude,
+		Longitude: l.Longitude,
+		TimeZone:  loc.String(),
+		Year:      year,
+	}
+
+	// noon keeps the date away from daylight saving transitions
+	for date := time.Date(year, time.January, 1, 12, 0, 0, 0, loc); date.Year() == year; date = date.AddDate(0, 0, 1) {
+		sun := sunevent.SunDayOn(date, l.Latitude, l.Longitude)
+		d := day{Date: date.Format("2006-01-02"), Type: sun.Type.String()}
+		d.Dawn = formatTime(sunevent.DawnOn(date, l.Latitude, l.Longitude))
+		d.Sunrise = formatTime(sunevent.SunRiseOn(date, l.Latitude, l.Longitude))
+		d.Sunset = formatTime(sunevent.SunSetOn(date, l.Latitude, l.Longitude))
+		d.Dusk = formatTime(sunevent.DuskOn(date, l.Latitude, l.Longitude))
+		length := sun.DayLength.String()
+		d.DayLength = &length
+		data.Days = append(data.Days, d)
+	}
+
+	return data
+}
+
+func formatTime(t time.Time, err error) *string {
+	if err != nil {
+		return nil
+	}
+	s := t.Format(time.RFC3339)
+	return &s
+}
+
+// marshalYAML writes the data file as YAML. The structure is fixed, so a
+// hand-written emitter avoids pulling in a YAML library.
+func marshalYAML(data dataFile) ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "name: %s\n", yamlString(&data.Name))
+	fmt.Fprintf(&b, "latitude: %s\n", strconv.FormatFloat(data.Latitude, 'f', -1, 64))
+	fmt.Fprintf(&b, "longitude: %s\n", strconv.FormatFloat(data.Longitude, 'f', -1, 64))
+	fmt.Fprintf(&b, "timezone: %s\n", yamlString(&data.TimeZone))
+	fmt.Fprintf(&b, "year: %d\n", data.Year)
+	fmt.Fprintf(&b, "days:\n")
+	for _, d := range data.Days {
+		fmt.Fprintf(&b, "  - date: %s\n", yamlString(&d.Date))
+		fmt.Fprintf(&b, "    type: %s\n", yamlString(&d.Type))
+		fmt.Fprintf(&b, "    dawn: %s\n", yamlString(d.Dawn))
+		fmt.Fprintf(&b, "    sunrise: %s\n", yamlString(d.Sunrise))
+		fmt.Fprintf(&b, "    sunset: %s\n", yamlString(d.Sunset))
+		fmt.Fprintf(&b, "    dusk: %s\n", yamlString(d.Dusk))
+		fmt.Fprintf(&b, "    day_length: %s\n", yamlString(d.DayLength))
+	}
+	return b.Bytes(), nil
+}
+
+func yamlString(s *string) string {
+	if s == nil {
+		return "null"
+	}
+	// a JSON string is a valid double-quoted YAML scalar
+	q, _ := json.Marshal(*s)
+	return string(q)
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLocationListSet(t *testing.T) {
+	var l locationList
+	if err := l.Set("kaohsiung,22.63,120.30"); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Set("tromso,69.65,18.96"); err != nil {
+		t.Fatal(err)
+	}
+	if len(l) != 2 || l[0] != (location{"kaohsiung", 22.63, 120.30}) || l.String() != "kaohsiung tromso" {
+		t.Errorf("locations = %v", l)
+	}
+	for _, v := range []string{"kaohsiung", "kaohsiung,22.63", "kaohsiung,north,120.30", "kaohsiung,22.63,east"} {
+		if err := l.Set(v); err == nil {
+			t.Errorf("Set(%q) succeeded, want an error", v)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	oslo, err := time.LoadLocation("Europe/Oslo")
+	if err != nil {
+		t.Skipf("time zone Europe/Oslo: %v", err)
+	}
+	data := generate(location{"tromso", 69.65, 18.96}, 2026, oslo)
+	if len(data.Days) != 365 || data.TimeZone != "Europe/Oslo" {
+		t.Fatalf("generated %d days in %s", len(data.Days), data.TimeZone)
+	}
+
+	// midsummer is a polar day: no sunrise or sunset
+	june := data.Days[171]
+	if june.Date != "2026-06-21" || june.Type != "polar_day" || june.Sunrise != nil || june.Sunset != nil {
+		t.Errorf("21 June = %+v", june)
+	}
+	// 21 March has both, in the time zone
+	march := data.Days[79]
+	if march.Date != "2026-03-21" || march.Sunrise == nil || march.Sunset == nil {
+		t.Fatalf("21 March = %+v", march)
+	}
+	for _, s := range []string{*march.Sunrise, *march.Sunset} {
+		tm, err := time.Parse(time.RFC3339, s)
+		if err != nil || tm.Format("2006-01-02 -07:00") != "2026-03-21 +01:00" {
+			t.Errorf("21 March event %q is not on that date in Oslo time", s)
+		}
+	}
+}
+
+func TestMarshalYAML(t *testing.T) {
+	sunrise := "2026-06-21T05:18:00+08:00"
+	data := dataFile{
+		Name:     `a "quoted" name`,
+		Latitude: 22.63, Longitude: 120.3,
+		TimeZone: "Asia/Taipei",
+		Year:     2026,
+		Days:     []day{{Date: "2026-06-21", Type: "normal", Sunrise: &sunrise}},
+	}
+	out, err := marshalYAML(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `name: "a \"quoted\" name"
+latitude: 22.63
+longitude: 120.3
+timezone: "Asia/Taipei"
+year: 2026
+days:
+  - date: "2026-06-21"
+    type: "normal"
+    dawn: null
+    sunrise: "2026-06-21T05:18:00+08:00"
+    sunset: null
+    dusk: null
+    day_length: null
+`
+	if string(out) != want {
+		t.Errorf("marshalYAML =\n%s\nwant\n%s", out, want)
+	}
+}
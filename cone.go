@@ -0,0 +1,90 @@
+package sunevent
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// ErrConeNotEntered is returned by NextConeViolation when the Sun stays
+// outside the cone for the whole interval.
+var ErrConeNotEntered = errors.New("sunevent: the sun does not enter the cone in this interval")
+
+// sunRate is an upper bound on the angular speed of the Sun across the
+// sky, in degrees per minute, used to step searches without missing a
+// crossing.
+const sunRate = 0.26
+
+// SunWithinCone reports whether the Sun at the instant t is within coneDeg
+// degrees of the direction an instrument points at, given by pointingAz and
+// pointingAlt in the azimuth and vertical angle conventions and units of
+// opts. The cone half-angle coneDeg is always in degrees. The test is
+// geometric and ignores the horizon, so it errs on the safe side for
+// keep-out zones; with WithRefraction the apparent position of the Sun is
+// used.
+func SunWithinCone(t time.Time, latitude, longitude, pointingAz, pointingAlt, coneDeg float64, opts ...Option) bool {
+	o := newOptions(opts)
+	return o.coneSeparation(t, latitude, o.longitude(longitude), pointingAz, pointingAlt) <= coneDeg
+}
+
+// NextConeViolation returns the first instant in [from, until) when the
+// Sun is within coneDeg degrees of the pointing direction, as tested by
+// SunWithinCone, to within a second. It returns from if the Sun is already
+// inside the cone and ErrConeNotEntered if it does not enter it before
+// until.
+func NextConeViolation(from, until time.Time, latitude, longitude, pointingAz, pointingAlt, coneDeg float64, opts ...Option) (time.Time, error) {
+	o := newOptions(opts)
+	longitude = o.longitude(longitude)
+	margin := func(t time.Time) float64 {
+		return o.coneSeparation(t, latitude, longitude, pointingAz, pointingAlt) - coneDeg
+	}
+
+	a, da := from, margin(from)
+	if da <= 0 {
+		return from, nil
+	}
+	for a.Before(until) {
+		// the Sun cannot close the margin faster than sunRate, so a step
+		// of that length cannot skip over the cone
+		step := time.Duration(da / sunRate * float64(time.Minute))
+		if step < time.Second {
+			step = time.Second
+		}
+		b := a.Add(step)
+		if b.After(until) {
+			b = until
+		}
+		db := margin(b)
+		if db <= 0 {
+			for b.Sub(a) > time.Second {
+				mid := a.Add(b.Sub(a) / 2)
+				if margin(mid) <= 0 {
+					b = mid
+				} else {
+					a = mid
+				}
+			}
+			if b.Before(until) {
+				return b, nil
+			}
+			break
+		}
+		a, da = b, db
+	}
+	return time.Time{}, ErrConeNotEntered
+}
+
+// coneSeparation returns the angle in degrees between the Sun at t and the
+// pointing direction, given in the conventions of o. The longitude is in
+// degrees east.
+func (o Options) coneSeparation(t time.Time, latitude, longitude, pointingAz, pointingAlt float64) float64 {
+	azimuth, elevation := o.sunAt(t, latitude, longitude)
+	if o.Refraction {
+		elevation += o.refraction(elevation)
+	}
+	sun := enuDirection(Position{Azimuth: azimuth, Altitude: elevation})
+	pointing := enuDirection(Position{Azimuth: o.azimuth(pointingAz), Altitude: o.elevation(pointingAlt)})
+
+	cos := math.Max(-1, math.Min(1, vecDot(sun, pointing)))
+	return degreeAcos(cos)
+}
@@ -0,0 +1,52 @@
+package sunevent
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSunWithinCone(t *testing.T) {
+	const latitude, longitude = 45.0, 7.0
+	at := time.Date(2026, time.June, 21, 12, 0, 0, 0, time.UTC)
+	p := SunPosition(at, latitude, longitude)
+
+	if !SunWithinCone(at, latitude, longitude, p.Azimuth, p.Altitude, 1) {
+		t.Error("the Sun is not within a cone pointing at it")
+	}
+	if SunWithinCone(at.Add(2*time.Hour), latitude, longitude, p.Azimuth, p.Altitude, 5) {
+		t.Error("the Sun is within 5° two hours later")
+	}
+
+	// the pointing follows the azimuth options
+	if !SunWithinCone(at, latitude, longitude, p.Azimuth-180, p.Altitude, 1, WithAzimuth(SouthClockwise)) {
+		t.Error("the Sun is not within a cone pointing at it from the south")
+	}
+}
+
+func TestNextConeViolation(t *testing.T) {
+	const latitude, longitude = 45.0, 7.0
+	at := time.Date(2026, time.June, 21, 12, 0, 0, 0, time.UTC)
+	p := SunPosition(at, latitude, longitude)
+	from := at.Add(-3 * time.Hour)
+
+	got, err := NextConeViolation(from, at.Add(time.Hour), latitude, longitude, p.Azimuth, p.Altitude, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.After(from) || !got.Before(at) {
+		t.Fatalf("violation at %s, want between %s and %s", got, from, at)
+	}
+	if !SunWithinCone(got, latitude, longitude, p.Azimuth, p.Altitude, 5) ||
+		SunWithinCone(got.Add(-time.Second), latitude, longitude, p.Azimuth, p.Altitude, 5) {
+		t.Errorf("violation at %s is not the first second inside the cone", got)
+	}
+
+	if got, err := NextConeViolation(at, at.Add(time.Hour), latitude, longitude, p.Azimuth, p.Altitude, 5); err != nil || !got.Equal(at) {
+		t.Errorf("already inside: %s, %v, want %s", got, err, at)
+	}
+
+	// the Sun never comes near the nadir
+	if _, err := NextConeViolation(from, at, latitude, longitude, 0, -90, 10); err != ErrConeNotEntered {
+		t.Errorf("nadir: %v, want ErrConeNotEntered", err)
+	}
+}
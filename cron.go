@@ -0,0 +1,216 @@
+package sunevent
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CronSchedule is a parsed cron expression, extended with solar events so
+// that cron-based configurations can move to solar scheduling. Besides
+// the five standard fields, minute, hour, day of month, month and day of
+// week, and the macros such as @daily, it accepts:
+//
+//	@sunset-20m          20 minutes before every sunset
+//	30 @civil_dawn * * 1-5  30 minutes after civil dawn, Monday to Friday
+//	-15 @sunset * 6-8 *  15 minutes before sunset in summer
+//
+// A solar event in the hour field takes a trigger as for ParseTrigger,
+// written without spaces, and turns the minute field into an offset in
+// minutes from the event. The day fields then select the dates of the
+// event. As in cron, when both the day of month and the day of week are
+// restricted a date matching either is selected.
+type CronSchedule struct {
+	spec string
+
+	minute, hour, dom, month, dow uint64 // bit sets of the allowed values
+
+	// domAll and dowAll are set when the day fields are "*"
+	domAll, dowAll bool
+
+	solar   bool
+	trigger Trigger
+}
+
+// cronMacros maps the standard cron macros to their expressions.
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// cronHorizon is how many days CronSchedule.Next looks ahead; a schedule
+// for the 29th of February waits up to eight years over a skipped leap
+// day.
+const cronHorizon = 8*366 + 1
+
+// ParseCron parses an extended cron expression; see CronSchedule.
+func ParseCron(spec string) (*CronSchedule, error) {
+	expr := strings.TrimSpace(spec)
+	if m, ok := cronMacros[expr]; ok {
+		expr = m
+	}
+	fields := strings.Fields(expr)
+	if len(fields) == 1 && strings.HasPrefix(fields[0], "@") {
+		fields = []string{"0", fields[0], "*", "*", "*"}
+	}
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("sunevent: cron %q: expected 5 fields, got %d", spec, len(fields))
+	}
+
+	c := &CronSchedule{
+		spec:   spec,
+		domAll: fields[2] == "*",
+		dowAll: fields[4] == "*",
+	}
+	var err error
+	if strings.HasPrefix(fields[1], "@") {
+		t, err := ParseTrigger(fields[1][1:])
+		if err != nil {
+			return nil, err
+		}
+		minutes, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("sunevent: cron %q: minute offset %q is not a number", spec, fields[0])
+		}
+		c.solar = true
+		c.trigger = At(t.Event, t.Offset+time.Duration(minutes)*time.Minute)
+	} else {
+		if c.minute, err = parseCronField(fields[0], 0, 59); err != nil {
+			return nil, fmt.Errorf("sunevent: cron %q: minute: %v", spec, err)
+		}
+		if c.hour, err = parseCronField(fields[1], 0, 23); err != nil {
+			return nil, fmt.Errorf("sunevent: cron %q: hour: %v", spec, err)
+		}
+	}
+	if c.dom, err = parseCronField(fields[2], 1, 31); err != nil {
+		return nil, fmt.Errorf("sunevent: cron %q: day of month: %v", spec, err)
+	}
+	if c.month, err = parseCronField(fields[3], 1, 12); err != nil {
+		return nil, fmt.Errorf("sunevent: cron %q: month: %v", spec, err)
+	}
+	if c.dow, err = parseCronField(fields[4], 0, 7); err != nil {
+		return nil, fmt.Errorf("sunevent: cron %q: day of week: %v", spec, err)
+	}
+	// 7 is another name for Sunday
+	if c.dow&(1<<7) != 0 {
+		c.dow |= 1
+	}
+	return c, nil
+}
+
+// parseCronField parses a list of values, ranges a-b and steps */n or
+// a-b/n within [min, max] into a bit set.
+func parseCronField(field string, min, max int) (uint64, error) {
+	var set uint64
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+			step, part = n, part[:i]
+		}
+
+		lo, hi := min, max
+		if part != "*" {
+			bounds := strings.SplitN(part, "-", 2)
+			var err error
+			if lo, err = strconv.Atoi(bounds[0]); err != nil {
+				return 0, fmt.Errorf("invalid value %q", part)
+			}
+			hi = lo
+			if len(bounds) == 2 {
+				if hi, err = strconv.Atoi(bounds[1]); err != nil {
+					return 0, fmt.Errorf("invalid value %q", part)
+				}
+			} else if step > 1 {
+				hi = max
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("%q is outside %d-%d", part, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			set |= 1 << uint(v)
+		}
+	}
+	return set, nil
+}
+
+// String returns the expression the schedule was parsed from.
+func (c *CronSchedule) String() string {
+	return c.spec
+}
+
+// Next returns the first time after the instant after that the schedule
+// selects, in the time zone of after, which is also the zone of the date
+// and clock fields. Solar events are taken from sky. It returns the zero
+// time if nothing is selected within eight years.
+func (c *CronSchedule) Next(after time.Time, sky Sky) time.Time {
+	_, t := c.next(after, sky)
+	return t
+}
+
+// next returns the first time selected after the instant after and, for a
+// solar schedule, the event it is offset from.
+func (c *CronSchedule) next(after time.Time, sky Sky) (Event, time.Time) {
+	loc := after.Location()
+	start := after
+	if c.solar {
+		start = after.Add(-c.trigger.Offset)
+	}
+
+	// from the day before, for an event just before midnight
+	for i := -1; i < cronHorizon; i++ {
+		day := time.Date(start.Year(), start.Month(), start.Day()+i, 0, 0, 0, 0, loc)
+		if !c.matchesDay(day) {
+			continue
+		}
+
+		if c.solar {
+			end := time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, loc)
+			for _, e := range sky.EventsBetween(day, end, c.trigger.Event) {
+				if t := e.Time.Add(c.trigger.Offset); t.After(after) {
+					return e, t
+				}
+			}
+			continue
+		}
+
+		for h := 0; h < 24; h++ {
+			if c.hour&(1<<uint(h)) == 0 {
+				continue
+			}
+			for m := 0; m < 60; m++ {
+				if c.minute&(1<<uint(m)) == 0 {
+					continue
+				}
+				if t := time.Date(day.Year(), day.Month(), day.Day(), h, m, 0, 0, loc); t.After(after) {
+					return Event{}, t
+				}
+			}
+		}
+	}
+	return Event{}, time.Time{}
+}
+
+// matchesDay reports whether the date of day is selected by the day and
+// month fields.
+func (c *CronSchedule) matchesDay(day time.Time) bool {
+	if c.month&(1<<uint(day.Month())) == 0 {
+		return false
+	}
+	dom := c.dom&(1<<uint(day.Day())) != 0
+	dow := c.dow&(1<<uint(day.Weekday())) != 0
+	if c.domAll || c.dowAll {
+		return dom && dow
+	}
+	return dom || dow
+}
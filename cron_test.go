@@ -0,0 +1,123 @@
+package sunevent
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCronNext(t *testing.T) {
+	taipei, err := time.LoadLocation("Asia/Taipei")
+	if err != nil {
+		t.Skipf("time zone Asia/Taipei: %v", err)
+	}
+	sky := Observer{Latitude: 22.63, Longitude: 120.30}
+	date := func(year int, month time.Month, day, hour, min int) time.Time {
+		return time.Date(year, month, day, hour, min, 0, 0, taipei)
+	}
+	// Friday 19 June 2026, 17:50
+	friday := date(2026, time.June, 19, 17, 50)
+
+	tests := []struct {
+		spec  string
+		after time.Time
+		want  time.Time
+	}{
+		{"*/15 9-17 * * 1-5", friday, date(2026, time.June, 22, 9, 0)},
+		{"*/15 9-17 * * 1-5", date(2026, time.June, 22, 9, 0), date(2026, time.June, 22, 9, 15)},
+		{"@daily", friday, date(2026, time.June, 20, 0, 0)},
+		{"@hourly", friday, date(2026, time.June, 19, 18, 0)},
+		{"0 12 * * 7", friday, date(2026, time.June, 21, 12, 0)},
+		{"0 0 1,15 * *", friday, date(2026, time.July, 1, 0, 0)},
+		// either day field selects a date when both are restricted
+		{"0 0 13 * 1", friday, date(2026, time.June, 22, 0, 0)},
+		{"0 0 29 2 *", friday, date(2028, time.February, 29, 0, 0)},
+	}
+	for _, tt := range tests {
+		c, err := ParseCron(tt.spec)
+		if err != nil {
+			t.Errorf("ParseCron(%q): %v", tt.spec, err)
+			continue
+		}
+		if got := c.Next(tt.after, sky); !got.Equal(tt.want) {
+			t.Errorf("%q after %s = %s, want %s", tt.spec, tt.after, got, tt.want)
+		}
+	}
+}
+
+func TestCronNextSolar(t *testing.T) {
+	taipei, err := time.LoadLocation("Asia/Taipei")
+	if err != nil {
+		t.Skipf("time zone Asia/Taipei: %v", err)
+	}
+	sky := Observer{Latitude: 22.63, Longitude: 120.30}
+	event := func(e EventType, year int, month time.Month, day int) time.Time {
+		ev, err := sky.EventOn(e, time.Date(year, month, day, 12, 0, 0, 0, taipei))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ev.Time
+	}
+	// Saturday 20 June 2026, at noon
+	saturday := time.Date(2026, time.June, 20, 12, 0, 0, 0, taipei)
+
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"@sunset-20m", event(Sunset, 2026, time.June, 20).Add(-20 * time.Minute)},
+		{"@sunrise", event(Sunrise, 2026, time.June, 21)},
+		{"30 @civil_dawn * * 1-5", event(CivilDawn, 2026, time.June, 22).Add(30 * time.Minute)},
+		{"-15 @sunset * 7-8 *", event(Sunset, 2026, time.July, 1).Add(-15 * time.Minute)},
+		{"10 @noon+1h * * *", event(SolarNoon, 2026, time.June, 20).Add(70 * time.Minute)},
+	}
+	for _, tt := range tests {
+		c, err := ParseCron(tt.spec)
+		if err != nil {
+			t.Errorf("ParseCron(%q): %v", tt.spec, err)
+			continue
+		}
+		if got := c.Next(saturday, sky); !got.Equal(tt.want) {
+			t.Errorf("%q after %s = %s, want %s", tt.spec, saturday, got, tt.want)
+		}
+	}
+}
+
+func TestParseCronErrors(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"0 0 0 * *",
+		"0 0 32 * *",
+		"0 0 * 13 *",
+		"0 0 * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"x @sunset * * *",
+		"0 @teatime * * *",
+		"@weekdays",
+	} {
+		if _, err := ParseCron(spec); err == nil {
+			t.Errorf("ParseCron(%q) succeeded, want an error", spec)
+		}
+	}
+}
+
+func TestSchedulerCron(t *testing.T) {
+	now := time.Now()
+	sky := scriptedSky{{Type: Sunset, Time: now.Add(time.Hour + 50*time.Millisecond)}}
+	s := NewScheduler(sky)
+	if err := s.Cron("0 @teatime * * *", func() {}); err == nil {
+		t.Error("Cron accepted an unknown event")
+	}
+	calls := make(chan EventType, 1)
+	if err := s.Cron("@sunset-1h", func() { calls <- Sunset }); err != nil {
+		t.Fatal(err)
+	}
+	s.Start()
+	defer s.Stop()
+	receive(t, calls, 1)
+}
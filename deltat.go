@@ -0,0 +1,100 @@
+package sunevent
+
+import "time"
+
+// DeltaT returns ΔT = TT − UT at the instant t: how far the uniform time
+// of the ephemerides runs ahead of the time kept by the Earth's rotation.
+// It is about 69 seconds in the 2020s and grows to hours in antiquity.
+//
+// It uses the polynomial fits of Espenak and Meeus to historical
+// observations from −500 to 2150 and the long-term parabola of Morrison
+// and Stephenson outside them. The future values are extrapolations, off
+// by minutes within a few centuries; WithDeltaT replaces them with a
+// measured or predicted value.
+func DeltaT(t time.Time) time.Duration {
+	return time.Duration(deltaTSeconds(decimalYear(t)) * float64(time.Second))
+}
+
+// WithDeltaT sets ΔT to d instead of the value of DeltaT, such as a value
+// published by the IERS. Options.DeltaT lists the calculations it affects.
+func WithDeltaT(d time.Duration) Option {
+	return WithDeltaTModel(func(time.Time) time.Duration {
+		return d
+	})
+}
+
+// WithDeltaTModel computes ΔT with model instead of DeltaT.
+func WithDeltaTModel(model func(t time.Time) time.Duration) Option {
+	return func(o *Options) {
+		o.DeltaT = model
+	}
+}
+
+// deltaT returns ΔT at t with the model of o.
+func (o Options) deltaT(t time.Time) time.Duration {
+	if o.DeltaT == nil {
+		return DeltaT(t)
+	}
+	return o.DeltaT(t)
+}
+
+// julianCentury returns the Julian centuries since J2000.0 in terrestrial
+// time at the instant t.
+func (o Options) julianCentury(t time.Time) float64 {
+	return julianCentury(t.Add(o.deltaT(t)))
+}
+
+// decimalYear returns the year of t with the fraction elapsed.
+func decimalYear(t time.Time) float64 {
+	return float64(t.Year()) + (float64(t.YearDay())-0.5)/365.25
+}
+
+// deltaTSeconds returns ΔT in seconds at the decimal year y.
+//
+// Reference
+// https://eclipse.gsfc.nasa.gov/SEhelp/deltatpoly2004.html
+func deltaTSeconds(y float64) float64 {
+	switch {
+	case y >= -500 && y < 500:
+		u := y / 100
+		return 10583.6 - 1014.41*u + 33.78311*u*u - 5.952053*u*u*u - 0.1798452*u*u*u*u + 0.022174192*u*u*u*u*u + 0.0090316521*u*u*u*u*u*u
+	case y >= 500 && y < 1600:
+		u := (y - 1000) / 100
+		return 1574.2 - 556.01*u + 71.23472*u*u + 0.319781*u*u*u - 0.8503463*u*u*u*u - 0.005050998*u*u*u*u*u + 0.0083572073*u*u*u*u*u*u
+	case y >= 1600 && y < 1700:
+		u := y - 1600
+		return 120 - 0.9808*u - 0.01532*u*u + u*u*u/7129
+	case y >= 1700 && y < 1800:
+		u := y - 1700
+		return 8.83 + 0.1603*u - 0.0059285*u*u + 0.00013336*u*u*u - u*u*u*u/1174000
+	case y >= 1800 && y < 1860:
+		u := y - 1800
+		return 13.72 - 0.332447*u + 0.0068612*u*u + 0.0041116*u*u*u - 0.00037436*u*u*u*u + 0.0000121272*u*u*u*u*u - 0.0000001699*u*u*u*u*u*u + 0.000000000875*u*u*u*u*u*u*u
+	case y >= 1860 && y < 1900:
+		u := y - 1860
+		return 7.62 + 0.5737*u - 0.251754*u*u + 0.01680668*u*u*u - 0.0004473624*u*u*u*u + u*u*u*u*u/233174
+	case y >= 1900 && y < 1920:
+		u := y - 1900
+		return -2.79 + 1.494119*u - 0.0598939*u*u + 0.0061966*u*u*u - 0.000197*u*u*u*u
+	case y >= 1920 && y < 1941:
+		u := y - 1920
+		return 21.20 + 0.84493*u - 0.076100*u*u + 0.0020936*u*u*u
+	case y >= 1941 && y < 1961:
+		u := y - 1950
+		return 29.07 + 0.407*u - u*u/233 + u*u*u/2547
+	case y >= 1961 && y < 1986:
+		u := y - 1975
+		return 45.45 + 1.067*u - u*u/260 - u*u*u/718
+	case y >= 1986 && y < 2005:
+		u := y - 2000
+		return 63.86 + 0.3345*u - 0.060374*u*u + 0.0017275*u*u*u + 0.000651814*u*u*u*u + 0.00002373599*u*u*u*u*u
+	case y >= 2005 && y < 2050:
+		u := y - 2000
+		return 62.92 + 0.32217*u + 0.005589*u*u
+	case y >= 2050 && y < 2150:
+		u := (y - 1820) / 100
+		return -20 + 32*u*u - 0.5628*(2150-y)
+	}
+	u := (y - 1820) / 100
+	return -20 + 32*u*u
+}
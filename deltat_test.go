@@ -0,0 +1,56 @@
+package sunevent
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeltaT(t *testing.T) {
+	tests := []struct {
+		year      int
+		seconds   float64
+		tolerance float64
+	}{
+		{-500, 17190, 20},
+		{1800, 13.7, 0.5},
+		{1900, -2.8, 0.5},
+		{1950, 29.1, 0.5},
+		{2000, 63.9, 0.5},
+		{2026, 75.1, 0.5},
+	}
+	for _, tt := range tests {
+		at := time.Date(tt.year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		if got := DeltaT(at).Seconds(); !near(got, tt.seconds, tt.tolerance) {
+			t.Errorf("ΔT in %d = %vs, want %vs", tt.year, got, tt.seconds)
+		}
+	}
+
+	// the fits join up at the ends of their ranges
+	for _, y := range []float64{500, 1600, 1700, 1800, 1860, 1900, 1920, 1941, 1961, 1986, 2005, 2050, 2150} {
+		if d := deltaTSeconds(y) - deltaTSeconds(y-1e-6); !near(d, 0, 2) {
+			t.Errorf("ΔT jumps by %vs in %v", d, y)
+		}
+	}
+}
+
+func TestWithDeltaT(t *testing.T) {
+	date := time.Date(2026, time.March, 20, 12, 0, 0, 0, time.UTC)
+
+	// a day of ΔT moves the Sun by a day of declination, about 0.4° at
+	// the equinox, which NOAA sunrise feels and the almanac ignores
+	noaa, _ := SunRiseOn(date, 45, 7, WithAlgorithm(NOAA), WithDeltaT(0))
+	shifted, _ := SunRiseOn(date, 45, 7, WithAlgorithm(NOAA), WithDeltaT(24*time.Hour))
+	if d := noaa.Sub(shifted); d < time.Minute || d > 3*time.Minute {
+		t.Errorf("NOAA sunrise moves by %v with a day of ΔT", d)
+	}
+
+	almanac, _ := SunRiseOn(date, 45, 7, WithDeltaT(0))
+	if got, _ := SunRiseOn(date, 45, 7, WithDeltaT(24*time.Hour)); !got.Equal(almanac) {
+		t.Errorf("almanac sunrise moves with ΔT: %s, want %s", got, almanac)
+	}
+
+	model := WithDeltaTModel(func(time.Time) time.Duration { return 24 * time.Hour })
+	if got, _ := SunRiseOn(date, 45, 7, WithAlgorithm(NOAA), model); !got.Equal(shifted) {
+		t.Errorf("NOAA sunrise with a ΔT model = %s, want %s", got, shifted)
+	}
+}
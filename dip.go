@@ -0,0 +1,25 @@
+package sunevent
+
+import "math"
+
+// WithObserverElevation sets the height of the observer above the
+// surrounding terrain or sea level in metres, for a mountain top or an
+// aircraft. The horizon then lies below the horizontal by the dip, so the
+// Sun rises earlier and sets later: from 3000 m by 6 minutes at the
+// equator and over 10 at mid latitudes. Twilight events keep their
+// definitions relative to the true horizon and are not affected.
+func WithObserverElevation(metres float64) Option {
+	return func(o *Options) {
+		o.ObserverElevation = metres
+	}
+}
+
+// dip returns the dip of the horizon in degrees for the observer
+// elevation of o, including typical terrestrial refraction.
+// dip = 1.76′ √h, h in metres
+func (o Options) dip() float64 {
+	if o.ObserverElevation <= 0 {
+		return 0
+	}
+	return 1.76 * math.Sqrt(o.ObserverElevation) / 60.0
+}
@@ -0,0 +1,52 @@
+package sunevent
+
+import (
+	"testing"
+	"time"
+)
+
+func TestObserverElevation(t *testing.T) {
+	if d := (Options{ObserverElevation: 100}).dip(); !near(d*60, 17.6, 0.01) {
+		t.Errorf("dip from 100 m = %v′, want 17.6′", d*60)
+	}
+	if d := (Options{ObserverElevation: -10}).dip(); d != 0 {
+		t.Errorf("dip below sea level = %v", d)
+	}
+
+	date := time.Date(2026, time.March, 20, 12, 0, 0, 0, time.UTC)
+	for _, tt := range []struct {
+		latitude float64
+		min, max time.Duration
+	}{
+		// from 3000 m the Sun rises 6 minutes earlier at the equator and
+		// more at mid latitudes, where it climbs at a shallower angle
+		{0, 6 * time.Minute, 7 * time.Minute},
+		{50, 9 * time.Minute, 11 * time.Minute},
+	} {
+		rise, _ := SunRiseOn(date, tt.latitude, 7)
+		set, _ := SunSetOn(date, tt.latitude, 7)
+		highRise, _ := SunRiseOn(date, tt.latitude, 7, WithObserverElevation(3000))
+		highSet, _ := SunSetOn(date, tt.latitude, 7, WithObserverElevation(3000))
+		if d := rise.Sub(highRise); d < tt.min || d > tt.max {
+			t.Errorf("latitude %v: sunrise from 3000 m %v earlier", tt.latitude, d)
+		}
+		if d := highSet.Sub(set); d < tt.min || d > tt.max {
+			t.Errorf("latitude %v: sunset from 3000 m %v later", tt.latitude, d)
+		}
+
+		// twilight is measured from the true horizon
+		dusk, _ := CivilDusk.On(date, tt.latitude, 7)
+		highDusk, _ := CivilDusk.On(date, tt.latitude, 7, WithObserverElevation(3000))
+		if !dusk.Equal(highDusk) {
+			t.Errorf("latitude %v: civil dusk from 3000 m at %s, want %s", tt.latitude, highDusk, dusk)
+		}
+	}
+
+	// the Observer applies its elevation
+	o := Observer{Latitude: 50, Longitude: 7, Elevation: 3000}
+	ev, _ := o.EventOn(Sunrise, date)
+	want, _ := SunRiseOn(date, 50, 7, WithObserverElevation(3000))
+	if !ev.Time.Equal(want) {
+		t.Errorf("Observer sunrise from 3000 m = %s, want %s", ev.Time, want)
+	}
+}
@@ -0,0 +1,107 @@
+package sunevent
+
+import "time"
+
+// DSTPolicy selects how a local wall-clock time is resolved when a time
+// zone change makes it nonexistent, in a spring-forward gap, or ambiguous,
+// in a fall-back overlap. It applies wherever the package builds a wall
+// time itself, such as the midnight that starts a day; event times are
+// computed as instants and are never affected.
+//
+// Each wall time has two readings, one at the offset before the change and
+// one at the offset after it. In an overlap both readings exist; in a gap
+// neither does, and a reading is shown on the other side of the gap,
+// shifted by its length. A day never starts on the date before: when
+// midnight falls in a gap the day starts at the end of the gap under every
+// policy.
+type DSTPolicy int
+
+const (
+	// DSTShiftForward moves a time in a gap forward by the length of the
+	// gap (02:30 in a gap from 02:00 to 03:00 becomes 03:30) and picks the
+	// first occurrence of a repeated time, as RFC 5545 does. A day that
+	// starts in a gap thus starts when the gap ends. This is the default.
+	DSTShiftForward DSTPolicy = iota
+	// DSTEarlier picks the earlier instant: the first occurrence of a
+	// repeated time, and the time moved back across a gap (02:30 becomes
+	// 01:30).
+	DSTEarlier
+	// DSTLater picks the later instant: the second occurrence of a
+	// repeated time, and the time moved forward across a gap.
+	DSTLater
+)
+
+// WithDSTPolicy selects how nonexistent and ambiguous local times are
+// resolved.
+func WithDSTPolicy(p DSTPolicy) Option {
+	return func(o *Options) {
+		o.DST = p
+	}
+}
+
+// midnight returns the start of the civil date of date in the location of
+// date.
+func (o Options) midnight(date time.Time) time.Time {
+	return o.dayStart(date.Year(), date.Month(), date.Day(), date.Location())
+}
+
+// civilDay returns the half-open interval [start, end) of the civil date
+// of date in the location of date. It lasts 23 or 25 hours on days of a
+// daylight saving change.
+func (o Options) civilDay(date time.Time) (start, end time.Time) {
+	loc := date.Location()
+	start = o.dayStart(date.Year(), date.Month(), date.Day(), loc)
+	end = o.dayStart(date.Year(), date.Month(), date.Day()+1, loc)
+	return start, end
+}
+
+// dayStart returns the first instant of the given date in loc. Where
+// midnight falls in a gap, DSTEarlier would move it back onto the day
+// before, so the day starts when the gap ends whatever the policy, as with
+// DSTShiftForward. Out of range days normalize as in time.Date.
+func (o Options) dayStart(year int, month time.Month, day int, loc *time.Location) time.Time {
+	t := o.wallTime(year, month, day, 0, loc)
+	date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	if y, m, d := t.Date(); y != date.Year() || m != date.Month() || d != date.Day() {
+		o.DST = DSTShiftForward
+		t = o.wallTime(year, month, day, 0, loc)
+	}
+	return t
+}
+
+// wallTime returns the instant when the wall clock in loc reads clock past
+// midnight of the given date, resolved by the DST policy of o. Unlike
+// time.Date, whose choice in a gap or overlap is unspecified, the result
+// is deterministic. Out of range days normalize as in time.Date.
+func (o Options) wallTime(year int, month time.Month, day int, clock time.Duration, loc *time.Location) time.Time {
+	// the wall time read as UTC, to compare wall clocks without zones
+	want := time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Add(clock)
+	t := time.Date(year, month, day, 0, 0, 0, 0, loc).Add(clock)
+
+	// zone changes are rarer than twice a day, so the offsets half a day
+	// either side are the ones before and after any change near t
+	_, before := t.Add(-12 * time.Hour).Zone()
+	_, after := t.Add(12 * time.Hour).Zone()
+	a := want.Add(-time.Duration(before) * time.Second).In(loc)
+	b := want.Add(-time.Duration(after) * time.Second).In(loc)
+	if b.Before(a) {
+		a, b = b, a
+	}
+
+	okA, okB := sameWallClock(a, want), sameWallClock(b, want)
+	switch {
+	case okA && !okB:
+		return a
+	case okB && !okA:
+		return b
+	case o.DST == DSTLater, o.DST == DSTShiftForward && !okA:
+		return b
+	}
+	return a
+}
+
+// sameWallClock reports whether t reads the wall time want, given in UTC.
+func sameWallClock(t, want time.Time) bool {
+	_, offset := t.Zone()
+	return t.Add(time.Duration(offset) * time.Second).UTC().Equal(want)
+}
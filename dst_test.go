@@ -0,0 +1,85 @@
+package sunevent
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWallTime(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone America/New_York: %v", err)
+	}
+	utc := func(month time.Month, day, hour, min int) time.Time {
+		return time.Date(2026, month, day, hour, min, 0, 0, time.UTC)
+	}
+	clock := func(hour, min int) time.Duration {
+		return time.Duration(hour)*time.Hour + time.Duration(min)*time.Minute
+	}
+
+	tests := []struct {
+		name   string
+		month  time.Month
+		day    int
+		clock  time.Duration
+		policy DSTPolicy
+		want   time.Time
+	}{
+		// 02:00 EST jumps to 03:00 EDT on 8 March 2026
+		{"gap shift forward", time.March, 8, clock(2, 30), DSTShiftForward, utc(time.March, 8, 7, 30)},
+		{"gap earlier", time.March, 8, clock(2, 30), DSTEarlier, utc(time.March, 8, 6, 30)},
+		{"gap later", time.March, 8, clock(2, 30), DSTLater, utc(time.March, 8, 7, 30)},
+		{"before gap", time.March, 8, clock(1, 59), DSTLater, utc(time.March, 8, 6, 59)},
+		{"after gap", time.March, 8, clock(3, 0), DSTEarlier, utc(time.March, 8, 7, 0)},
+
+		// 02:00 EDT falls back to 01:00 EST on 1 November 2026
+		{"overlap shift forward", time.November, 1, clock(1, 30), DSTShiftForward, utc(time.November, 1, 5, 30)},
+		{"overlap earlier", time.November, 1, clock(1, 30), DSTEarlier, utc(time.November, 1, 5, 30)},
+		{"overlap later", time.November, 1, clock(1, 30), DSTLater, utc(time.November, 1, 6, 30)},
+		{"after overlap", time.November, 1, clock(2, 0), DSTEarlier, utc(time.November, 1, 7, 0)},
+
+		{"ordinary day", time.June, 21, clock(12, 0), DSTLater, utc(time.June, 21, 16, 0)},
+		{"normalized day", time.June, 31, 0, DSTShiftForward, utc(time.July, 1, 4, 0)},
+	}
+	for _, tt := range tests {
+		o := Options{DST: tt.policy}
+		got := o.wallTime(2026, tt.month, tt.day, tt.clock, ny)
+		if !got.Equal(tt.want) {
+			t.Errorf("%s: wallTime = %s, want %s", tt.name, got.UTC(), tt.want)
+		}
+	}
+}
+
+func TestCivilDay(t *testing.T) {
+	tests := []struct {
+		zone   string
+		date   time.Time
+		policy DSTPolicy
+		length time.Duration
+		start  string
+	}{
+		{"America/New_York", time.Date(2026, time.March, 8, 12, 0, 0, 0, time.UTC), DSTShiftForward, 23 * time.Hour, "00:00"},
+		{"America/New_York", time.Date(2026, time.November, 1, 12, 0, 0, 0, time.UTC), DSTShiftForward, 25 * time.Hour, "00:00"},
+		{"America/New_York", time.Date(2026, time.June, 21, 12, 0, 0, 0, time.UTC), DSTShiftForward, 24 * time.Hour, "00:00"},
+		// midnight is skipped in Chile, where the day starts at 01:00
+		{"America/Santiago", time.Date(2026, time.September, 6, 12, 0, 0, 0, time.UTC), DSTShiftForward, 23 * time.Hour, "01:00"},
+		{"America/Santiago", time.Date(2026, time.September, 6, 12, 0, 0, 0, time.UTC), DSTEarlier, 23 * time.Hour, "01:00"},
+	}
+	for _, tt := range tests {
+		loc, err := time.LoadLocation(tt.zone)
+		if err != nil {
+			t.Skipf("time zone %s: %v", tt.zone, err)
+		}
+		date := tt.date.In(loc)
+		start, end := Options{DST: tt.policy}.civilDay(date)
+		if got := end.Sub(start); got != tt.length {
+			t.Errorf("%s %s: civil day lasts %v, want %v", tt.zone, date.Format("2006-01-02"), got, tt.length)
+		}
+		if start.Day() != date.Day() || !end.After(date) {
+			t.Errorf("%s %s: civil day %s–%s does not hold the date", tt.zone, date.Format("2006-01-02"), start, end)
+		}
+		if got := start.Format("15:04"); got != tt.start {
+			t.Errorf("%s %s: civil day starts at %s, want %s", tt.zone, date.Format("2006-01-02"), got, tt.start)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package sunevent
+
+import (
+	"time"
+)
+
+// Ephemeris supplies the apparent position of the Sun to the functions
+// that compute it at an instant, such as SunPosition, Elevation, SunVector,
+// PhaseAt and ClassifyMany, in place of the almanac formulas. Event times
+// are not affected, nor are ElevationSeries, AdaptiveElevationSeries,
+// SunPath and TimeAtAzimuth, which interpolate over the day. The vsop87
+// subpackage provides an implementation accurate to about an arcsecond.
+type Ephemeris interface {
+	// SunGreenwich returns the Sun's Greenwich hour angle and declination
+	// in degrees at the instant t.
+	SunGreenwich(t time.Time) (hourAngle, declination float64)
+}
+
+// WithEphemeris selects the source of the Sun's position.
+func WithEphemeris(e Ephemeris) Option {
+	return func(o *Options) {
+		o.Ephemeris = e
+	}
+}
+
+// sunAt returns the azimuth (north clockwise) and elevation of the Sun in
+// degrees at the instant t, from the ephemeris of o if set. The longitude
+// is in degrees east.
+func (o Options) sunAt(t time.Time, latitude, longitude float64) (azimuth, elevation float64) {
+	if o.Ephemeris == nil {
+		return sunPosition(julianCentury(t), t, latitude, longitude)
+	}
+	H, dec := o.Ephemeris.SunGreenwich(t)
+	return horizontal(H+longitude, degreeSin(dec), degreeCos(dec), latitude)
+}
+
+// hourAngle returns the Sun's local hour angle in degrees in [-180, 180)
+// at the instant t, from the ephemeris of o if set.
+func (o Options) hourAngle(t time.Time, longitude float64) float64 {
+	if o.Ephemeris == nil {
+		return hourAngle(julianCentury(t), t, longitude)
+	}
+	H, _ := o.Ephemeris.SunGreenwich(t)
+	return normalizeRange(H+longitude+180.0, 360) - 180.0
+}
@@ -0,0 +1,215 @@
+package sunevent
+
+import (
+	"sort"
+	"time"
+)
+
+// EventType identifies a daily solar event.
+type EventType int
+
+const (
+	Sunrise EventType = iota
+	Sunset
+	SolarNoon
+	CivilDawn
+	CivilDusk
+	NauticalDawn
+	NauticalDusk
+	AstronomicalDawn
+	AstronomicalDusk
+)
+
+// EventTypes lists every event type in the order of the constants.
+var EventTypes = []EventType{
+	Sunrise,
+	Sunset,
+	SolarNoon,
+	CivilDawn,
+	CivilDusk,
+	NauticalDawn,
+	NauticalDusk,
+	AstronomicalDawn,
+	AstronomicalDusk,
+}
+
+var eventNames = [...]string{
+	Sunrise:          "sunrise",
+	Sunset:           "sunset",
+	SolarNoon:        "solar_noon",
+	CivilDawn:        "civil_dawn",
+	CivilDusk:        "civil_dusk",
+	NauticalDawn:     "nautical_dawn",
+	NauticalDusk:     "nautical_dusk",
+	AstronomicalDawn: "astronomical_dawn",
+	AstronomicalDusk: "astronomical_dusk",
+}
+
+func (e EventType) String() string {
+	if e < 0 || int(e) >= len(eventNames) {
+		return "unknown"
+	}
+	return eventNames[e]
+}
+
+// Altitude returns the altitude of the Sun in degrees at the event and
+// whether the Sun is rising. It returns 0, false for SolarNoon, which is
+// defined by the meridian rather than an altitude. Sunrise and sunset are
+// lowered by WithRefractionModel; see Options.RefractionModel.
+func (e EventType) Altitude() (altitude float64, rising bool) {
+	switch e {
+	case Sunrise:
+		return 0.0, true
+	case Sunset:
+		return 0.0, false
+	case CivilDawn:
+		return 90.0 - 96.0, true
+	case CivilDusk:
+		return 90.0 - 96.0, false
+	case NauticalDawn:
+		return 90.0 - 102.0, true
+	case NauticalDusk:
+		return 90.0 - 102.0, false
+	case AstronomicalDawn:
+		return 90.0 - 108.0, true
+	case AstronomicalDusk:
+		return 90.0 - 108.0, false
+	}
+	return 0.0, false
+}
+
+// Event is an occurrence of a solar event.
+type Event struct {
+	Type EventType
+	Time time.Time
+
+	// Accuracy is the estimated error of Time, from the envelope of the
+	// algorithm and how steeply the Sun crosses the altitude of the event,
+	// so that schedules can leave enough slack. It grows near the polar
+	// circles, up to an hour where the Sun barely reaches the altitude.
+	// It is zero for Approximate events and for algorithms from outside
+	// the package, whose accuracy is unknown.
+	Accuracy time.Duration
+
+	// Approximate is set when the event does not happen and Time is the
+	// substitute chosen by WithPolarFallback.
+	Approximate bool
+
+	// Warning is set by EventOn when the query lies outside the validated
+	// envelope of the algorithm; see CheckEnvelope.
+	Warning error
+}
+
+// EventOn returns the event of type e on the civil date of date, in the
+// time zone of date. Without WithPolarFallback it returns the error of
+// TimeAtAltitude when the event does not happen that day.
+func EventOn(e EventType, date time.Time, latitude, longitude float64, opts ...Option) (Event, error) {
+	warning := CheckEnvelope(date, latitude, opts...)
+
+	o := newOptions(opts)
+	t, err := e.On(date, latitude, longitude, opts...)
+	if err == nil {
+		return Event{Type: e, Time: t, Accuracy: o.accuracy(e, t, latitude, longitude), Warning: warning}, nil
+	}
+
+	if !o.PolarFallback {
+		return Event{}, err
+	}
+
+	noon, _ := SolarNoonOn(date, latitude, longitude, opts...)
+	if err == ErrSunNeverRises {
+		// closest to the altitude at the brightest moment
+		return Event{Type: e, Time: noon, Approximate: true, Warning: warning}, nil
+	}
+
+	// closest at the darkest moment, the solar midnight within the date
+	midnight := noon.Add(-12 * time.Hour)
+	if midnight.Day() != noon.Day() {
+		midnight = noon.Add(12 * time.Hour)
+	}
+	return Event{Type: e, Time: midnight, Approximate: true, Warning: warning}, nil
+}
+
+// On returns the time of the event on the civil date of date, in the time
+// zone of date.
+func (e EventType) On(date time.Time, latitude, longitude float64, opts ...Option) (time.Time, error) {
+	switch e {
+	case SolarNoon:
+		return SolarNoonOn(date, latitude, longitude, opts...)
+	case Sunrise:
+		return SunRiseOn(date, latitude, longitude, opts...)
+	case Sunset:
+		return SunSetOn(date, latitude, longitude, opts...)
+	}
+	altitude, rising := e.Altitude()
+	return TimeAtAltitude(date, latitude, longitude, altitude, rising, opts...)
+}
+
+// SolarNoonOn returns the time of local apparent noon, when the Sun crosses
+// the meridian, on the civil date of date in the time zone of date. It
+// never fails; the error is returned for symmetry with the other events.
+func SolarNoonOn(date time.Time, latitude, longitude float64, opts ...Option) (time.Time, error) {
+	o := newOptions(opts)
+	longitude = o.longitude(longitude)
+
+	// noon in local mean time is 12h, corrected by the equation of time
+	guess := localMeanTime(date, longitude, 12.0)
+	eot := EquationOfTime(guess)
+	if o.algorithm() == NOAA {
+		_, minutes := noaaSun(o.julianCentury(guess))
+		eot = time.Duration(minutes * float64(time.Minute))
+	}
+	noon := guess.Add(-eot)
+	return noon.In(date.Location()).Round(time.Second), nil
+}
+
+// EventsBetween returns the events of the given types, or of every type
+// if none are given, that happen in [from, to), in chronological order.
+// The window may span any number of days; dates are taken in the time
+// zone of from, which is also the zone of the returned times.
+func EventsBetween(latitude, longitude float64, from, to time.Time, types ...EventType) []Event {
+	return eventsBetween(latitude, longitude, from, to, types, nil)
+}
+
+func eventsBetween(latitude, longitude float64, from, to time.Time, types []EventType, opts []Option) []Event {
+	if !from.Before(to) {
+		return nil
+	}
+	if len(types) == 0 {
+		types = EventTypes
+	}
+
+	o := newOptions(opts)
+	loc := from.Location()
+	to = to.In(loc)
+
+	// an event computed for one civil date can fall just outside it, so
+	// look one day beyond each end of the window
+	first := time.Date(from.Year(), from.Month(), from.Day()-1, 12, 0, 0, 0, loc)
+	last := time.Date(to.Year(), to.Month(), to.Day()+1, 12, 0, 0, 0, loc)
+
+	type key struct {
+		e    EventType
+		unix int64
+	}
+
+	var events []Event
+	seen := make(map[key]bool)
+	for date := first; !date.After(last); date = date.AddDate(0, 0, 1) {
+		for _, e := range types {
+			t, err := e.On(date, latitude, longitude, opts...)
+			if err != nil || t.Before(from) || !t.Before(to) {
+				continue
+			}
+			if k := (key{e, t.Unix()}); !seen[k] {
+				seen[k] = true
+				events = append(events, Event{Type: e, Time: t, Accuracy: o.accuracy(e, t, latitude, longitude)})
+			}
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Time.Before(events[j].Time)
+	})
+	return events
+}
@@ -0,0 +1,144 @@
+package sunevent
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestEventTypeAltitude(t *testing.T) {
+	tests := []struct {
+		e        EventType
+		name     string
+		altitude float64
+		rising   bool
+	}{
+		{Sunrise, "sunrise", 0, true},
+		{Sunset, "sunset", 0, false},
+		{SolarNoon, "solar_noon", 0, false},
+		{CivilDawn, "civil_dawn", -6, true},
+		{CivilDusk, "civil_dusk", -6, false},
+		{NauticalDawn, "nautical_dawn", -12, true},
+		{NauticalDusk, "nautical_dusk", -12, false},
+		{AstronomicalDawn, "astronomical_dawn", -18, true},
+		{AstronomicalDusk, "astronomical_dusk", -18, false},
+	}
+	for _, tt := range tests {
+		altitude, rising := tt.e.Altitude()
+		if tt.e.String() != tt.name || altitude != tt.altitude || rising != tt.rising {
+			t.Errorf("%v: %q, %v, %v, want %q, %v, %v", int(tt.e), tt.e, altitude, rising, tt.name, tt.altitude, tt.rising)
+		}
+	}
+	if s := EventType(-1).String(); s != "unknown" {
+		t.Errorf("EventType(-1) = %q", s)
+	}
+}
+
+func TestEventTypeOn(t *testing.T) {
+	taipei := time.FixedZone("CST", 8*3600)
+	date := time.Date(2026, time.March, 21, 12, 0, 0, 0, taipei)
+	const latitude, longitude = 22.63, 120.30
+
+	var prev time.Time
+	for _, e := range []EventType{AstronomicalDawn, NauticalDawn, CivilDawn, Sunrise, SolarNoon, Sunset, CivilDusk, NauticalDusk, AstronomicalDusk} {
+		got, err := e.On(date, latitude, longitude)
+		if err != nil {
+			t.Fatalf("%v: %v", e, err)
+		}
+		if got.Location() != taipei || got.Day() != 21 {
+			t.Errorf("%v = %s, want on 21 March in the time zone of the date", e, got)
+		}
+		if !got.After(prev) {
+			t.Errorf("%v = %s, not after the previous event %s", e, got, prev)
+		}
+		prev = got
+
+		ev, err := EventOn(e, date, latitude, longitude)
+		if err != nil || !ev.Time.Equal(got) || ev.Type != e {
+			t.Errorf("EventOn(%v) = %+v, %v, want %s", e, ev, err, got)
+		}
+	}
+
+	// civil twilight lasts about 24 minutes near the tropic at the equinox
+	dawn, _ := CivilDawn.On(date, latitude, longitude)
+	rise, _ := Sunrise.On(date, latitude, longitude)
+	if d := rise.Sub(dawn); d < 20*time.Minute || d > 30*time.Minute {
+		t.Errorf("civil twilight lasts %v", d)
+	}
+}
+
+func TestEventOnPolarFallback(t *testing.T) {
+	const latitude, longitude = 78.22, 15.65
+	oslo := time.FixedZone("CET", 3600)
+	winter := time.Date(2026, time.December, 21, 12, 0, 0, 0, oslo)
+	summer := time.Date(2026, time.June, 21, 12, 0, 0, 0, oslo)
+
+	if _, err := EventOn(Sunrise, winter, latitude, longitude); err != ErrSunNeverRises {
+		t.Errorf("sunrise in the polar night: %v, want ErrSunNeverRises", err)
+	}
+
+	// the polar night falls back to solar noon
+	ev, err := EventOn(Sunrise, winter, latitude, longitude, WithPolarFallback())
+	if err != nil {
+		t.Fatal(err)
+	}
+	noon, _ := SolarNoonOn(winter, latitude, longitude)
+	if !ev.Approximate || !ev.Time.Equal(noon) || ev.Accuracy != 0 {
+		t.Errorf("winter sunrise = %+v, want the approximate noon %s", ev, noon)
+	}
+
+	// the midnight Sun falls back to solar midnight on the same date
+	ev, err = EventOn(Sunset, summer, latitude, longitude, WithPolarFallback())
+	if err != nil {
+		t.Fatal(err)
+	}
+	noon, _ = SolarNoonOn(summer, latitude, longitude)
+	if !ev.Approximate || ev.Time.Day() != 21 || !near(math.Abs(noon.Sub(ev.Time).Hours()), 12, 0.1) {
+		t.Errorf("summer sunset = %+v, want the approximate midnight 12h from %s", ev, noon)
+	}
+
+	// events that do happen are not approximate
+	ev, err = EventOn(SolarNoon, summer, latitude, longitude, WithPolarFallback())
+	if err != nil || ev.Approximate || ev.Accuracy == 0 {
+		t.Errorf("summer noon = %+v, %v", ev, err)
+	}
+}
+
+func TestEventsBetween(t *testing.T) {
+	taipei := time.FixedZone("CST", 8*3600)
+	const latitude, longitude = 22.63, 120.30
+	from := time.Date(2026, time.March, 1, 0, 0, 0, 0, taipei)
+	to := from.AddDate(0, 0, 3)
+
+	events := EventsBetween(latitude, longitude, from, to, Sunrise, Sunset)
+	if len(events) != 6 {
+		t.Fatalf("%d sunrises and sunsets in three days, want 6", len(events))
+	}
+	for i, ev := range events {
+		want := Sunrise
+		if i%2 == 1 {
+			want = Sunset
+		}
+		day := time.Date(2026, time.March, 1+i/2, 12, 0, 0, 0, taipei)
+		at, _ := want.On(day, latitude, longitude)
+		if ev.Type != want || !ev.Time.Equal(at) || ev.Time.Location() != taipei {
+			t.Errorf("event %d = %v at %s, want %v at %s", i, ev.Type, ev.Time, want, at)
+		}
+	}
+
+	if n := len(EventsBetween(latitude, longitude, from, to)); n != 3*len(EventTypes) {
+		t.Errorf("%d events of every type, want %d", n, 3*len(EventTypes))
+	}
+
+	// the window is half-open
+	rise := events[0].Time
+	if got := EventsBetween(latitude, longitude, rise, rise.Add(time.Minute), Sunrise); len(got) != 1 {
+		t.Errorf("window starting at sunrise: %+v", got)
+	}
+	if got := EventsBetween(latitude, longitude, rise.Add(-time.Minute), rise, Sunrise); len(got) != 0 {
+		t.Errorf("window ending at sunrise: %+v", got)
+	}
+	if got := EventsBetween(latitude, longitude, to, from); got != nil {
+		t.Errorf("reversed window: %+v", got)
+	}
+}
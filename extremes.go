@@ -0,0 +1,51 @@
+package sunevent
+
+import (
+	"time"
+)
+
+// SunTimeExtremes holds the earliest and latest sunrise and sunset of a
+// year by the wall clock. They do not fall on the solstices: the equation
+// of time shifts solar noon through the year, so at mid northern latitudes
+// the earliest sunset comes early in December and, without daylight saving
+// time, the latest sunrise in early January.
+type SunTimeExtremes struct {
+	EarliestSunrise time.Time
+	LatestSunrise   time.Time
+	EarliestSunset  time.Time
+	LatestSunset    time.Time
+}
+
+// ExtremeSunTimes returns the earliest and latest sunrise and sunset of
+// year at the location, compared by their wall-clock times in loc, so
+// daylight saving time counts as the clock shows it. Days without a
+// sunrise or sunset are skipped; the fields are zero if there is none in
+// the year. Of equal times, the first is returned.
+func ExtremeSunTimes(year int, latitude, longitude float64, loc *time.Location, opts ...Option) SunTimeExtremes {
+	var e SunTimeExtremes
+	for date := time.Date(year, time.January, 1, 12, 0, 0, 0, loc); date.Year() == year; date = time.Date(year, time.January, date.YearDay()+1, 12, 0, 0, 0, loc) {
+		if t, err := SunRiseOn(date, latitude, longitude, opts...); err == nil {
+			if e.EarliestSunrise.IsZero() || clock(t) < clock(e.EarliestSunrise) {
+				e.EarliestSunrise = t
+			}
+			if e.LatestSunrise.IsZero() || clock(t) > clock(e.LatestSunrise) {
+				e.LatestSunrise = t
+			}
+		}
+		if t, err := SunSetOn(date, latitude, longitude, opts...); err == nil {
+			if e.EarliestSunset.IsZero() || clock(t) < clock(e.EarliestSunset) {
+				e.EarliestSunset = t
+			}
+			if e.LatestSunset.IsZero() || clock(t) > clock(e.LatestSunset) {
+				e.LatestSunset = t
+			}
+		}
+	}
+	return e
+}
+
+// clock returns the wall-clock time of t as a duration since midnight.
+func clock(t time.Time) time.Duration {
+	h, m, s := t.Clock()
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(s)*time.Second
+}
@@ -0,0 +1,43 @@
+package sunevent
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExtremeSunTimes(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+	const latitude, longitude = 40.7, -74.0
+	date := func(t time.Time) string { return t.Format("Jan 2") }
+
+	// by standard time the extremes straddle the solstices
+	e := ExtremeSunTimes(2026, latitude, longitude, time.FixedZone("EST", -5*3600))
+	for _, c := range []struct{ name, got, want string }{
+		{"earliest sunrise", date(e.EarliestSunrise), "Jun 13"},
+		{"latest sunrise", date(e.LatestSunrise), "Jan 3"},
+		{"earliest sunset", date(e.EarliestSunset), "Dec 7"},
+		{"latest sunset", date(e.LatestSunset), "Jun 27"},
+	} {
+		if c.got != c.want {
+			t.Errorf("EST %s on %s, want %s", c.name, c.got, c.want)
+		}
+	}
+
+	// daylight saving time moves the latest sunrise to the last day before
+	// the clocks go back
+	e = ExtremeSunTimes(2026, latitude, longitude, newYork)
+	if got := date(e.LatestSunrise); got != "Oct 31" {
+		t.Errorf("New York latest sunrise on %s, want Oct 31", got)
+	}
+	if e.LatestSunset.Hour() != 20 || e.EarliestSunrise.Hour() != 5 {
+		t.Errorf("New York sunrise from %s, sunset until %s", e.EarliestSunrise, e.LatestSunset)
+	}
+
+	// at the pole the Sun does not cross the horizon on any civil date
+	if e = ExtremeSunTimes(2026, 90, 0, time.UTC); e != (SunTimeExtremes{}) {
+		t.Errorf("North Pole: %+v, want zero", e)
+	}
+}
@@ -0,0 +1,139 @@
+package grpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/cfw011566/sunevent"
+)
+
+// Client calls a server of the SunEvent service. Servers other than
+// those of NewHandler require HTTP/2, which the default client of package
+// net/http negotiates for https URLs.
+type Client struct {
+	url string
+	hc  *http.Client
+}
+
+// NewClient returns a client of the service at baseURL, such as
+// "https://sun.example.com:8443", using hc, or http.DefaultClient if hc
+// is nil.
+func NewClient(baseURL string, hc *http.Client) *Client {
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	return &Client{url: strings.TrimSuffix(baseURL, "/"), hc: hc}
+}
+
+// Day returns the events of the requested date, in the requested time
+// zone.
+func (c *Client) Day(ctx context.Context, req DayRequest) (sunevent.SunDay, error) {
+	loc, err := loadLocation(req.TimeZone)
+	if err != nil {
+		return sunevent.SunDay{}, err
+	}
+	var d sunevent.SunDay
+	err = c.invoke(ctx, "GetDay", req.marshal(), func(msg []byte) error {
+		d, err = unmarshalSunDay(msg, loc)
+		return err
+	})
+	return d, err
+}
+
+// Range returns the events of every date of the range in order.
+func (c *Client) Range(ctx context.Context, req RangeRequest) ([]sunevent.SunDay, error) {
+	loc, err := loadLocation(req.TimeZone)
+	if err != nil {
+		return nil, err
+	}
+	var days []sunevent.SunDay
+	err = c.invoke(ctx, "GetRange", req.marshal(), func(msg []byte) error {
+		d, err := unmarshalSunDay(msg, loc)
+		if err != nil {
+			return err
+		}
+		days = append(days, d)
+		return nil
+	})
+	return days, err
+}
+
+// Position returns the position of the Sun at the requested time.
+func (c *Client) Position(ctx context.Context, req PositionRequest) (sunevent.Position, error) {
+	var p sunevent.Position
+	err := c.invoke(ctx, "GetPosition", req.marshal(), func(msg []byte) error {
+		var err error
+		p, err = unmarshalPosition(msg)
+		return err
+	})
+	return p, err
+}
+
+// invoke calls a method with the request message req and passes each
+// response message to recv. The status of the call is returned as an
+// *Error.
+func (c *Client) invoke(ctx context.Context, method string, req []byte, recv func([]byte) error) error {
+	var body bytes.Buffer
+	var prefix [5]byte
+	binary.BigEndian.PutUint32(prefix[1:], uint32(len(req)))
+	body.Write(prefix[:])
+	body.Write(req)
+
+	r, err := http.NewRequest(http.MethodPost, c.url+servicePath+method, &body)
+	if err != nil {
+		return err
+	}
+	r = r.WithContext(ctx)
+	r.Header.Set("Content-Type", "application/grpc+proto")
+	r.Header.Set("TE", "trailers")
+
+	resp, err := c.hc.Do(r)
+	if err != nil {
+		return err
+	}
+	defer discard(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("sunevent/grpc: %s", resp.Status)
+	}
+
+	for {
+		var prefix [5]byte
+		if _, err := io.ReadFull(resp.Body, prefix[:]); err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		if prefix[0] != 0 {
+			return fmt.Errorf("sunevent/grpc: compressed response")
+		}
+		msg := make([]byte, binary.BigEndian.Uint32(prefix[1:]))
+		if _, err := io.ReadFull(resp.Body, msg); err != nil {
+			return err
+		}
+		if err := recv(msg); err != nil {
+			return err
+		}
+	}
+
+	// a response without messages may carry the status in its headers
+	status := resp.Trailer.Get("Grpc-Status")
+	message := resp.Trailer.Get("Grpc-Message")
+	if status == "" {
+		status = resp.Header.Get("Grpc-Status")
+		message = resp.Header.Get("Grpc-Message")
+	}
+	code, err := strconv.Atoi(status)
+	if err != nil {
+		return fmt.Errorf("sunevent/grpc: response without status")
+	}
+	if code != CodeOK {
+		return &Error{Code: code, Message: decodeMessage(message)}
+	}
+	return nil
+}
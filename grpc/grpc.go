@@ -0,0 +1,288 @@
+// Package grpc serves sunevent calculations as the gRPC service of
+// sunevent.proto, for environments that standardize on gRPC, without
+// depending on the gRPC and protocol buffer modules: the few messages of
+// the service are encoded by hand.
+//
+// The handler speaks gRPC over HTTP/2, which Go servers negotiate over
+// TLS:
+//
+//	srv := &http.Server{Addr: ":8443", Handler: grpc.NewHandler()}
+//	srv.ListenAndServeTLS("cert.pem", "key.pem")
+//
+// Clients generated from sunevent.proto in any language can call it, and
+// Client calls it, or any server of the service, from Go. Compressed
+// messages are not supported.
+package grpc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cfw011566/sunevent"
+)
+
+// Status codes of gRPC used by the service.
+const (
+	CodeOK              = 0
+	CodeInvalidArgument = 3
+	CodeUnimplemented   = 12
+	CodeInternal        = 13
+)
+
+// Error is a gRPC status other than OK.
+type Error struct {
+	Code    int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("sunevent/grpc: code %d: %s", e.Code, e.Message)
+}
+
+// servicePath is the prefix of the paths of the methods.
+const servicePath = "/sunevent.v1.SunEvent/"
+
+// maxMessage bounds the size of a request.
+const maxMessage = 1 << 16
+
+// maxRange is the largest number of dates GetRange returns.
+const maxRange = 366
+
+// NewHandler returns a handler serving the methods of the SunEvent
+// service at /sunevent.v1.SunEvent/GetDay and so on.
+func NewHandler() http.Handler {
+	return http.HandlerFunc(serve)
+}
+
+func serve(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+		http.Error(w, "unsupported content type", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/grpc+proto")
+	w.Header().Set("Trailer", "Grpc-Status, Grpc-Message")
+	w.WriteHeader(http.StatusOK)
+
+	err := call(w, r)
+	status, message := CodeOK, ""
+	if err != nil {
+		status, message = CodeInternal, err.Error()
+		if e, ok := err.(*Error); ok {
+			status, message = e.Code, e.Message
+		}
+	}
+	w.Header().Set("Grpc-Status", strconv.Itoa(status))
+	if message != "" {
+		w.Header().Set("Grpc-Message", encodeMessage(message))
+	}
+}
+
+// call reads the request message, calls the method and writes the
+// response messages.
+func call(w http.ResponseWriter, r *http.Request) error {
+	req, err := readMessage(io.LimitReader(r.Body, maxMessage+5))
+	if err != nil {
+		return err
+	}
+
+	switch strings.TrimPrefix(r.URL.Path, servicePath) {
+	case "GetDay":
+		var q DayRequest
+		if err := q.unmarshal(req); err != nil {
+			return invalid(err.Error())
+		}
+		d, err := getDay(q)
+		if err != nil {
+			return err
+		}
+		return writeMessage(w, marshalSunDay(d))
+
+	case "GetRange":
+		var q RangeRequest
+		if err := q.unmarshal(req); err != nil {
+			return invalid(err.Error())
+		}
+		return getRange(q, func(d sunevent.SunDay) error {
+			return writeMessage(w, marshalSunDay(d))
+		})
+
+	case "GetPosition":
+		var q PositionRequest
+		if err := q.unmarshal(req); err != nil {
+			return invalid(err.Error())
+		}
+		if err := checkCoordinates(q.Latitude, q.Longitude); err != nil {
+			return err
+		}
+		t := q.Time
+		if t.IsZero() {
+			t = time.Now()
+		}
+		return writeMessage(w, marshalPosition(t, sunevent.SunPosition(t, q.Latitude, q.Longitude)))
+	}
+	return &Error{Code: CodeUnimplemented, Message: "unknown method " + r.URL.Path}
+}
+
+func getDay(q DayRequest) (sunevent.SunDay, error) {
+	if err := checkCoordinates(q.Latitude, q.Longitude); err != nil {
+		return sunevent.SunDay{}, err
+	}
+	loc, err := loadLocation(q.TimeZone)
+	if err != nil {
+		return sunevent.SunDay{}, err
+	}
+	date := time.Now().In(loc)
+	if q.Date != "" {
+		if date, err = parseDate(q.Date, loc); err != nil {
+			return sunevent.SunDay{}, err
+		}
+	}
+	noon := time.Date(date.Year(), date.Month(), date.Day(), 12, 0, 0, 0, loc)
+	return sunevent.SunDayOn(noon, q.Latitude, q.Longitude), nil
+}
+
+func getRange(q RangeRequest, send func(sunevent.SunDay) error) error {
+	if err := checkCoordinates(q.Latitude, q.Longitude); err != nil {
+		return err
+	}
+	loc, err := loadLocation(q.TimeZone)
+	if err != nil {
+		return err
+	}
+	first, err := parseDate(q.First, loc)
+	if err != nil {
+		return err
+	}
+	last, err := parseDate(q.Last, loc)
+	if err != nil {
+		return err
+	}
+	if last.Before(first) || last.After(first.AddDate(0, 0, maxRange-1)) {
+		return invalid(fmt.Sprintf("the range must hold 1 to %d dates", maxRange))
+	}
+
+	// noon keeps the dates away from daylight saving transitions
+	for date := first.Add(12 * time.Hour); !date.After(last.Add(12 * time.Hour)); date = date.AddDate(0, 0, 1) {
+		if err := send(sunevent.SunDayOn(date, q.Latitude, q.Longitude)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkCoordinates(latitude, longitude float64) error {
+	if !(latitude >= -90 && latitude <= 90) {
+		return invalid("latitude must be from -90 to 90")
+	}
+	if !(longitude >= -180 && longitude <= 180) {
+		return invalid("longitude must be from -180 to 180")
+	}
+	return nil
+}
+
+func loadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, invalid(fmt.Sprintf("unknown time zone %q", name))
+	}
+	return loc, nil
+}
+
+func parseDate(s string, loc *time.Location) (time.Time, error) {
+	d, err := time.ParseInLocation("2006-01-02", s, loc)
+	if err != nil {
+		return time.Time{}, invalid(fmt.Sprintf("invalid date %q", s))
+	}
+	return d, nil
+}
+
+func invalid(message string) error {
+	return &Error{Code: CodeInvalidArgument, Message: message}
+}
+
+// readMessage reads one length-prefixed message.
+func readMessage(r io.Reader) ([]byte, error) {
+	var prefix [5]byte
+	if _, err := io.ReadFull(r, prefix[:]); err != nil {
+		return nil, invalid("missing message")
+	}
+	if prefix[0] != 0 {
+		return nil, &Error{Code: CodeUnimplemented, Message: "compressed messages are not supported"}
+	}
+	n := binary.BigEndian.Uint32(prefix[1:])
+	if n > maxMessage {
+		return nil, invalid("message too large")
+	}
+	msg := make([]byte, n)
+	if _, err := io.ReadFull(r, msg); err != nil {
+		return nil, invalid("truncated message")
+	}
+	return msg, nil
+}
+
+// writeMessage writes one length-prefixed message and flushes it, so that
+// streamed messages reach the client as they are computed.
+func writeMessage(w io.Writer, msg []byte) error {
+	var prefix [5]byte
+	binary.BigEndian.PutUint32(prefix[1:], uint32(len(msg)))
+	if _, err := w.Write(append(prefix[:], msg...)); err != nil {
+		return err
+	}
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+// encodeMessage percent-encodes a status message for the grpc-message
+// trailer.
+func encodeMessage(s string) string {
+	var b bytes.Buffer
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c < 0x20 || c > 0x7e || c == '%' {
+			fmt.Fprintf(&b, "%%%02X", c)
+		} else {
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// decodeMessage reverses encodeMessage.
+func decodeMessage(s string) string {
+	var b bytes.Buffer
+	for i := 0; i < len(s); i++ {
+		if s[i] == '%' && i+2 < len(s) {
+			if v, err := strconv.ParseUint(s[i+1:i+3], 16, 8); err == nil {
+				b.WriteByte(byte(v))
+				i += 2
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// discard drains and closes a response body so the connection can be
+// reused.
+func discard(body io.ReadCloser) {
+	io.Copy(ioutil.Discard, body)
+	body.Close()
+}
@@ -0,0 +1,95 @@
+package grpc
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cfw011566/sunevent"
+)
+
+func TestClient(t *testing.T) {
+	srv := httptest.NewServer(NewHandler())
+	defer srv.Close()
+	c := NewClient(srv.URL, srv.Client())
+	ctx := context.Background()
+
+	want := sunevent.SunDayOn(time.Date(2026, time.June, 21, 12, 0, 0, 0, time.UTC), 22.63, 120.30)
+	d, err := c.Day(ctx, DayRequest{Latitude: 22.63, Longitude: 120.30, Date: "2026-06-21"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !d.Sunrise.Equal(want.Sunrise) || !d.Sunset.Equal(want.Sunset) || d.Type != want.Type || d.DayLength != want.DayLength {
+		t.Errorf("Day = %+v, want %+v", d, want)
+	}
+
+	// midnight sun in Tromsø
+	days, err := c.Range(ctx, RangeRequest{Latitude: 69.65, Longitude: 18.96, First: "2026-06-01", Last: "2026-06-30"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(days) != 30 || days[0].Date.Day() != 1 || days[29].Date.Day() != 30 {
+		t.Fatalf("Range returned %d days", len(days))
+	}
+	if days[20].Type != sunevent.PolarDay || !days[20].Sunrise.IsZero() {
+		t.Errorf("21 June = %+v, want a polar day", days[20])
+	}
+
+	at := time.Date(2026, time.June, 21, 4, 0, 0, 0, time.UTC)
+	p, err := c.Position(ctx, PositionRequest{Latitude: 22.63, Longitude: 120.30, Time: at})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w := sunevent.SunPosition(at, 22.63, 120.30); math.Abs(p.Altitude-w.Altitude) > 1e-9 || math.Abs(p.Azimuth-w.Azimuth) > 1e-9 {
+		t.Errorf("Position = %+v, want %+v", p, w)
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	srv := httptest.NewServer(NewHandler())
+	defer srv.Close()
+	c := NewClient(srv.URL+"/", nil)
+	ctx := context.Background()
+
+	for _, req := range []DayRequest{
+		{Latitude: 91},
+		{Longitude: math.NaN()},
+		{Date: "21 June"},
+	} {
+		_, err := c.Day(ctx, req)
+		if e, ok := err.(*Error); !ok || e.Code != CodeInvalidArgument {
+			t.Errorf("Day(%+v): %v, want invalid argument", req, err)
+		}
+	}
+	_, err := c.Range(ctx, RangeRequest{First: "2026-01-01", Last: "2027-01-02"})
+	if e, ok := err.(*Error); !ok || e.Code != CodeInvalidArgument || !strings.Contains(e.Message, "366") {
+		t.Errorf("Range of 367 dates: %v", err)
+	}
+	if err := c.invoke(ctx, "GetMoon", nil, func([]byte) error { return nil }); err == nil || err.(*Error).Code != CodeUnimplemented {
+		t.Errorf("GetMoon: %v, want unimplemented", err)
+	}
+
+	// not gRPC
+	resp, err := http.Post(srv.URL+servicePath+"GetDay", "application/json", strings.NewReader("{}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnsupportedMediaType {
+		t.Errorf("JSON request: status %d", resp.StatusCode)
+	}
+}
+
+func TestStatusMessage(t *testing.T) {
+	s := `invalid date "21 June" 100% ☀`
+	if got := decodeMessage(encodeMessage(s)); got != s {
+		t.Errorf("message %q came back as %q", s, got)
+	}
+	if got := encodeMessage(s); !strings.HasSuffix(got, " 100%25 %E2%98%80") {
+		t.Errorf("encodeMessage(%q) = %q", s, got)
+	}
+}
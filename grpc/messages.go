@@ -0,0 +1,194 @@
+package grpc
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cfw011566/sunevent"
+)
+
+// DayRequest asks for the events of one date.
+type DayRequest struct {
+	Latitude  float64
+	Longitude float64
+
+	// Date is written 2006-01-02; empty means today.
+	Date string
+
+	// TimeZone is an IANA time zone in which the date is taken; empty
+	// means UTC.
+	TimeZone string
+}
+
+// RangeRequest asks for the events of every date from First to Last, at
+// most 366 dates.
+type RangeRequest struct {
+	Latitude    float64
+	Longitude   float64
+	First, Last string
+	TimeZone    string
+}
+
+// PositionRequest asks for the position of the Sun at Time, or now if it
+// is zero.
+type PositionRequest struct {
+	Latitude  float64
+	Longitude float64
+	Time      time.Time
+}
+
+func (r *DayRequest) marshal() []byte {
+	var e encoder
+	e.double(1, r.Latitude)
+	e.double(2, r.Longitude)
+	e.string(3, r.Date)
+	e.string(4, r.TimeZone)
+	return e.b
+}
+
+func (r *DayRequest) unmarshal(b []byte) error {
+	return decode(b, func(f field) error {
+		switch f.num {
+		case 1:
+			r.Latitude = f.double()
+		case 2:
+			r.Longitude = f.double()
+		case 3:
+			r.Date = f.string()
+		case 4:
+			r.TimeZone = f.string()
+		}
+		return nil
+	})
+}
+
+func (r *RangeRequest) marshal() []byte {
+	var e encoder
+	e.double(1, r.Latitude)
+	e.double(2, r.Longitude)
+	e.string(3, r.First)
+	e.string(4, r.Last)
+	e.string(5, r.TimeZone)
+	return e.b
+}
+
+func (r *RangeRequest) unmarshal(b []byte) error {
+	return decode(b, func(f field) error {
+		switch f.num {
+		case 1:
+			r.Latitude = f.double()
+		case 2:
+			r.Longitude = f.double()
+		case 3:
+			r.First = f.string()
+		case 4:
+			r.Last = f.string()
+		case 5:
+			r.TimeZone = f.string()
+		}
+		return nil
+	})
+}
+
+func (r *PositionRequest) marshal() []byte {
+	var e encoder
+	e.double(1, r.Latitude)
+	e.double(2, r.Longitude)
+	e.timestamp(3, r.Time)
+	return e.b
+}
+
+func (r *PositionRequest) unmarshal(b []byte) error {
+	return decode(b, func(f field) error {
+		var err error
+		switch f.num {
+		case 1:
+			r.Latitude = f.double()
+		case 2:
+			r.Longitude = f.double()
+		case 3:
+			r.Time, err = decodeTimestamp(f.data)
+		}
+		return err
+	})
+}
+
+// sunDayEvents maps the fields of the SunDay message, from 3, to the
+// fields of sunevent.SunDay.
+func sunDayEvents(d *sunevent.SunDay) []*time.Time {
+	return []*time.Time{
+		&d.AstronomicalDawn,
+		&d.NauticalDawn,
+		&d.CivilDawn,
+		&d.Sunrise,
+		&d.SolarNoon,
+		&d.Sunset,
+		&d.CivilDusk,
+		&d.NauticalDusk,
+		&d.AstronomicalDusk,
+	}
+}
+
+func marshalSunDay(d sunevent.SunDay) []byte {
+	var e encoder
+	e.string(1, d.Date.Format("2006-01-02"))
+	e.int(2, int64(d.Type))
+	for i, t := range sunDayEvents(&d) {
+		e.timestamp(3+i, *t)
+	}
+	e.duration(12, d.DayLength)
+	e.bool(13, d.Inverted)
+	if d.Warning != nil {
+		e.string(14, d.Warning.Error())
+	}
+	return e.b
+}
+
+// unmarshalSunDay decodes a SunDay with its date and times in loc.
+func unmarshalSunDay(b []byte, loc *time.Location) (sunevent.SunDay, error) {
+	var d sunevent.SunDay
+	events := sunDayEvents(&d)
+	err := decode(b, func(f field) error {
+		var err error
+		switch {
+		case f.num == 1:
+			d.Date, err = time.ParseInLocation("2006-01-02", f.string(), loc)
+		case f.num == 2:
+			d.Type = sunevent.DayType(f.v)
+		case f.num >= 3 && f.num < 3+len(events):
+			var t time.Time
+			t, err = decodeTimestamp(f.data)
+			*events[f.num-3] = t.In(loc)
+		case f.num == 12:
+			d.DayLength, err = decodeDuration(f.data)
+		case f.num == 13:
+			d.Inverted = f.v != 0
+		case f.num == 14:
+			d.Warning = errors.New(f.string())
+		}
+		return err
+	})
+	return d, err
+}
+
+func marshalPosition(t time.Time, p sunevent.Position) []byte {
+	var e encoder
+	e.timestamp(1, t)
+	e.double(2, p.Azimuth)
+	e.double(3, p.Altitude)
+	return e.b
+}
+
+func unmarshalPosition(b []byte) (sunevent.Position, error) {
+	var p sunevent.Position
+	err := decode(b, func(f field) error {
+		switch f.num {
+		case 2:
+			p.Azimuth = f.double()
+		case 3:
+			p.Altitude = f.double()
+		}
+		return nil
+	})
+	return p, err
+}
@@ -0,0 +1,180 @@
+package grpc
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"time"
+)
+
+// The protocol buffer wire format, for the few messages of the service.
+// Fields holding the default value are omitted, as in proto3.
+
+// Wire types.
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+var errTruncated = errors.New("sunevent/grpc: truncated message")
+
+// encoder appends fields to a message.
+type encoder struct {
+	b []byte
+}
+
+func (e *encoder) varint(v uint64) {
+	for v >= 0x80 {
+		e.b = append(e.b, byte(v)|0x80)
+		v >>= 7
+	}
+	e.b = append(e.b, byte(v))
+}
+
+func (e *encoder) tag(field, wire int) {
+	e.varint(uint64(field)<<3 | uint64(wire))
+}
+
+func (e *encoder) int(field int, v int64) {
+	if v != 0 {
+		e.tag(field, wireVarint)
+		e.varint(uint64(v))
+	}
+}
+
+func (e *encoder) bool(field int, v bool) {
+	if v {
+		e.int(field, 1)
+	}
+}
+
+func (e *encoder) double(field int, v float64) {
+	if v != 0 {
+		e.tag(field, wireFixed64)
+		var buf [8]byte
+		binary.LittleEndian.PutUint64(buf[:], math.Float64bits(v))
+		e.b = append(e.b, buf[:]...)
+	}
+}
+
+func (e *encoder) bytes(field int, v []byte) {
+	e.tag(field, wireBytes)
+	e.varint(uint64(len(v)))
+	e.b = append(e.b, v...)
+}
+
+func (e *encoder) string(field int, v string) {
+	if v != "" {
+		e.bytes(field, []byte(v))
+	}
+}
+
+// timestamp writes a google.protobuf.Timestamp, or nothing for the zero
+// time.
+func (e *encoder) timestamp(field int, t time.Time) {
+	if t.IsZero() {
+		return
+	}
+	var m encoder
+	m.int(1, t.Unix())
+	m.int(2, int64(t.Nanosecond()))
+	e.bytes(field, m.b)
+}
+
+// duration writes a google.protobuf.Duration.
+func (e *encoder) duration(field int, d time.Duration) {
+	var m encoder
+	m.int(1, int64(d/time.Second))
+	m.int(2, int64(d%time.Second))
+	e.bytes(field, m.b)
+}
+
+// field is a decoded field: v holds varint and fixed values, data the
+// contents of length-delimited ones.
+type field struct {
+	num  int
+	wire int
+	v    uint64
+	data []byte
+}
+
+func (f field) double() float64 {
+	return math.Float64frombits(f.v)
+}
+
+func (f field) string() string {
+	return string(f.data)
+}
+
+// decode calls fn for every field of the message b.
+func decode(b []byte, fn func(f field) error) error {
+	for len(b) > 0 {
+		tag, n := binary.Uvarint(b)
+		if n <= 0 {
+			return errTruncated
+		}
+		b = b[n:]
+		f := field{num: int(tag >> 3), wire: int(tag & 7)}
+		switch f.wire {
+		case wireVarint:
+			if f.v, n = binary.Uvarint(b); n <= 0 {
+				return errTruncated
+			}
+			b = b[n:]
+		case wireFixed64:
+			if len(b) < 8 {
+				return errTruncated
+			}
+			f.v, b = binary.LittleEndian.Uint64(b), b[8:]
+		case wireFixed32:
+			if len(b) < 4 {
+				return errTruncated
+			}
+			f.v, b = uint64(binary.LittleEndian.Uint32(b)), b[4:]
+		case wireBytes:
+			l, n := binary.Uvarint(b)
+			if n <= 0 || uint64(len(b)-n) < l {
+				return errTruncated
+			}
+			f.data, b = b[n:n+int(l)], b[n+int(l):]
+		default:
+			return errors.New("sunevent/grpc: unsupported wire type")
+		}
+		if err := fn(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeTimestamp decodes a google.protobuf.Timestamp.
+func decodeTimestamp(b []byte) (time.Time, error) {
+	var sec, nsec int64
+	err := decode(b, func(f field) error {
+		switch f.num {
+		case 1:
+			sec = int64(f.v)
+		case 2:
+			nsec = int64(f.v)
+		}
+		return nil
+	})
+	return time.Unix(sec, nsec).UTC(), err
+}
+
+// decodeDuration decodes a google.protobuf.Duration.
+func decodeDuration(b []byte) (time.Duration, error) {
+	var sec, nsec int64
+	err := decode(b, func(f field) error {
+		switch f.num {
+		case 1:
+			sec = int64(f.v)
+		case 2:
+			nsec = int64(int32(f.v))
+		}
+		return nil
+	})
+	return time.Duration(sec)*time.Second + time.Duration(nsec), err
+}
@@ -0,0 +1,44 @@
+package httpapi
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/cfw011566/sunevent/ical"
+)
+
+// maxDays bounds the horizon of a calendar.
+const maxDays = 366
+
+// calendar serves the upcoming events at the location of the query as an
+// iCalendar feed.
+func calendar(w http.ResponseWriter, r *http.Request) {
+	if !allowGet(w, r) {
+		return
+	}
+	q, err := parseQuery(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	f := &ical.Feed{
+		Name:      r.FormValue("name"),
+		Latitude:  q.latitude,
+		Longitude: q.longitude,
+	}
+	if f.Name == "" {
+		f.Name = fmt.Sprintf("Sun at %.2f, %.2f", q.latitude, q.longitude)
+	}
+	if f.Events, err = parseEvents(r.FormValue("events")); err != nil {
+		writeError(w, err)
+		return
+	}
+	if s := r.FormValue("days"); s != "" {
+		if f.Days, err = strconv.Atoi(s); err != nil || f.Days < 1 || f.Days > maxDays {
+			writeError(w, fmt.Errorf("parameter days must be a number from 1 to %d", maxDays))
+			return
+		}
+	}
+	f.ServeHTTP(w, r)
+}
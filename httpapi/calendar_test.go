@@ -0,0 +1,36 @@
+package httpapi
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCalendar(t *testing.T) {
+	h := NewHandler()
+	w := get(t, h, "/v1/calendar.ics?lat=22.63&lon=120.30&days=7&events=sunrise,sunset")
+	if w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "text/calendar") {
+		t.Fatalf("status %d, content type %q", w.Code, w.Header().Get("Content-Type"))
+	}
+	body := w.Body.String()
+	if !strings.Contains(body, "X-WR-CALNAME:Sun at 22.63\\, 120.30\r\n") {
+		t.Error("calendar lacks the default name")
+	}
+	if rises, sets := strings.Count(body, "SUMMARY:Sunrise\r\n"), strings.Count(body, "SUMMARY:Sunset\r\n"); rises != 8 || sets != 8 {
+		t.Errorf("%d sunrises and %d sunsets, want 8 of each", rises, sets)
+	}
+	if strings.Contains(body, "SUMMARY:Civil dawn") {
+		t.Error("calendar holds events not asked for")
+	}
+
+	for _, target := range []string{
+		"/v1/calendar.ics?lat=22.63",
+		"/v1/calendar.ics?lat=22.63&lon=120.30&days=0",
+		"/v1/calendar.ics?lat=22.63&lon=120.30&days=367",
+		"/v1/calendar.ics?lat=22.63&lon=120.30&events=teatime",
+	} {
+		if w := get(t, h, target); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status %d", target, w.Code)
+		}
+	}
+}
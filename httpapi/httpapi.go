@@ -0,0 +1,203 @@
+// Package httpapi serves sunevent calculations over HTTP as JSON.
+//
+// The handler can be mounted in an existing server:
+//
+//	http.Handle("/v1/", httpapi.NewHandler())
+package httpapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cfw011566/sunevent"
+)
+
+// NewHandler returns a handler serving the API endpoints:
+//
+//	GET /v1/capabilities                   supported events, algorithms and angle presets
+//	GET /v1/sun?lat=&lon=[&date=][&tz=]    the events of a day, as a SunDay
+//	GET /v1/stream?lat=&lon=[&events=][&interval=][&tz=]
+//	                                       a WebSocket of events and positions
+//	GET /v1/calendar.ics?lat=&lon=[&days=][&events=][&name=]
+//	                                       an iCalendar feed of the coming events
+//
+// Dates are written 2006-01-02 and default to today; tz is an IANA time
+// zone, by default UTC, in which the date is taken and the times are
+// written. Invalid parameters are answered with status 400 and a JSON
+// object with an error message.
+//
+// The stream sends a JSON text message for each event as it happens,
+//
+//	{"type":"event","time":"2026-06-21T19:03:12+08:00","event":"sunset"}
+//
+// and for the position of the Sun on connection and then every interval,
+// by default 1m:
+//
+//	{"type":"position","time":"...","azimuth":291.4,"altitude":0.3,"phase":"day"}
+//
+// events is a comma-separated list of the events to send, by default all.
+//
+// Calendar applications subscribe to the feed, by webcal:// URL or as a
+// calendar from the Internet, and fetch it again every 12 hours. It holds
+// the events of the next days, by default 30, of the events, by default
+// those of ical.DefaultEvents.
+func NewHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/capabilities", capabilities)
+	mux.HandleFunc("/v1/sun", sun)
+	mux.HandleFunc("/v1/stream", stream)
+	mux.HandleFunc("/v1/calendar.ics", calendar)
+	return mux
+}
+
+func capabilities(w http.ResponseWriter, r *http.Request) {
+	if !allowGet(w, r) {
+		return
+	}
+	writeJSON(w, http.StatusOK, sunevent.Capabilities())
+}
+
+// sunDay is a SunDay in the API. Times are RFC 3339 and null for events
+// that do not happen.
+type sunDay struct {
+	Date             string     `json:"date"`
+	Type             string     `json:"type"`
+	AstronomicalDawn *time.Time `json:"astronomical_dawn"`
+	NauticalDawn     *time.Time `json:"nautical_dawn"`
+	CivilDawn        *time.Time `json:"civil_dawn"`
+	Sunrise          *time.Time `json:"sunrise"`
+	SolarNoon        *time.Time `json:"solar_noon"`
+	Sunset           *time.Time `json:"sunset"`
+	CivilDusk        *time.Time `json:"civil_dusk"`
+	NauticalDusk     *time.Time `json:"nautical_dusk"`
+	AstronomicalDusk *time.Time `json:"astronomical_dusk"`
+
+	// DayLength is in seconds.
+	DayLength float64 `json:"day_length"`
+	Inverted  bool    `json:"inverted"`
+	Warning   string  `json:"warning,omitempty"`
+}
+
+func newSunDay(d sunevent.SunDay) sunDay {
+	v := sunDay{
+		Date:             d.Date.Format("2006-01-02"),
+		Type:             d.Type.String(),
+		AstronomicalDawn: optionalTime(d.AstronomicalDawn),
+		NauticalDawn:     optionalTime(d.NauticalDawn),
+		CivilDawn:        optionalTime(d.CivilDawn),
+		Sunrise:          optionalTime(d.Sunrise),
+		SolarNoon:        optionalTime(d.SolarNoon),
+		Sunset:           optionalTime(d.Sunset),
+		CivilDusk:        optionalTime(d.CivilDusk),
+		NauticalDusk:     optionalTime(d.NauticalDusk),
+		AstronomicalDusk: optionalTime(d.AstronomicalDusk),
+		DayLength:        d.DayLength.Seconds(),
+		Inverted:         d.Inverted,
+	}
+	if d.Warning != nil {
+		v.Warning = d.Warning.Error()
+	}
+	return v
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	t = t.Round(time.Second)
+	return &t
+}
+
+func sun(w http.ResponseWriter, r *http.Request) {
+	if !allowGet(w, r) {
+		return
+	}
+	q, err := parseQuery(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	date, err := q.date(r.FormValue("date"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newSunDay(sunevent.SunDayOn(date, q.latitude, q.longitude)))
+}
+
+// query holds the location parameters common to the endpoints.
+type query struct {
+	latitude, longitude float64
+	loc                 *time.Location
+}
+
+// parseQuery parses the lat, lon and tz parameters.
+func parseQuery(r *http.Request) (query, error) {
+	q := query{loc: time.UTC}
+	var err error
+	if q.latitude, err = parseCoordinate(r, "lat", 90); err != nil {
+		return q, err
+	}
+	if q.longitude, err = parseCoordinate(r, "lon", 180); err != nil {
+		return q, err
+	}
+	if tz := r.FormValue("tz"); tz != "" {
+		if q.loc, err = time.LoadLocation(tz); err != nil {
+			return q, fmt.Errorf("unknown time zone %q", tz)
+		}
+	}
+	return q, nil
+}
+
+// parseCoordinate parses the required parameter name as an angle within
+// [-limit, limit].
+func parseCoordinate(r *http.Request, name string, limit float64) (float64, error) {
+	s := r.FormValue(name)
+	if s == "" {
+		return 0, fmt.Errorf("missing parameter %s", name)
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(v) || v < -limit || v > limit {
+		return 0, fmt.Errorf("parameter %s must be a number from %v to %v", name, -limit, limit)
+	}
+	return v, nil
+}
+
+// date returns the date s, or today if s is empty, at noon in the time
+// zone of the query.
+func (q query) date(s string) (time.Time, error) {
+	d := time.Now().In(q.loc)
+	if s != "" {
+		var err error
+		if d, err = time.ParseInLocation("2006-01-02", s, q.loc); err != nil {
+			return time.Time{}, fmt.Errorf("invalid date %q", s)
+		}
+	}
+	return time.Date(d.Year(), d.Month(), d.Day(), 12, 0, 0, 0, q.loc), nil
+}
+
+// allowGet answers requests other than GET and HEAD with status 405 and
+// reports whether the request may proceed.
+func allowGet(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return false
+	}
+	return true
+}
+
+// writeError answers a request with invalid parameters.
+func writeError(w http.ResponseWriter, err error) {
+	writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
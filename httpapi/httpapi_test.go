@@ -0,0 +1,83 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cfw011566/sunevent"
+)
+
+// get serves a request of the handler and returns the response.
+func get(t *testing.T, h http.Handler, target string) *httptest.ResponseRecorder {
+	t.Helper()
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+	return w
+}
+
+func TestCapabilities(t *testing.T) {
+	w := get(t, NewHandler(), "/v1/capabilities")
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("status %d, content type %q", w.Code, w.Header().Get("Content-Type"))
+	}
+	var c sunevent.CapabilitySet
+	if err := json.Unmarshal(w.Body.Bytes(), &c); err != nil {
+		t.Fatal(err)
+	}
+	if c.CalcVersion != sunevent.CalcVersion || len(c.Events) != len(sunevent.EventTypes) || len(c.Angles) == 0 {
+		t.Errorf("capabilities = %+v", c)
+	}
+
+	w = httptest.NewRecorder()
+	NewHandler().ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/capabilities", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST status %d, want 405", w.Code)
+	}
+}
+
+func TestSun(t *testing.T) {
+	h := NewHandler()
+	w := get(t, h, "/v1/sun?lat=22.63&lon=120.30&date=2026-06-21&tz=Asia/Taipei")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", w.Code, w.Body)
+	}
+	var d sunDay
+	if err := json.Unmarshal(w.Body.Bytes(), &d); err != nil {
+		t.Fatal(err)
+	}
+	if d.Date != "2026-06-21" || d.Type != "normal" || d.Sunrise == nil || d.Sunset == nil {
+		t.Fatalf("day = %s", w.Body)
+	}
+	if _, offset := d.Sunrise.Zone(); offset != 8*3600 {
+		t.Errorf("sunrise %s not in Taipei time", d.Sunrise)
+	}
+	if l := d.Sunset.Sub(*d.Sunrise).Seconds(); d.DayLength < l-1 || d.DayLength > l+1 {
+		t.Errorf("day length %v s, want %v", d.DayLength, l)
+	}
+
+	// midnight sun in Tromsø: null sunrise and sunset
+	w = get(t, h, "/v1/sun?lat=69.65&lon=18.96&date=2026-06-21")
+	if err := json.Unmarshal(w.Body.Bytes(), &d); err != nil {
+		t.Fatal(err)
+	}
+	if d.Type != "polar_day" || d.Sunrise != nil || d.Sunset != nil || d.DayLength != 86400 {
+		t.Errorf("polar day = %s", w.Body)
+	}
+
+	for _, target := range []string{
+		"/v1/sun?lon=120.30",
+		"/v1/sun?lat=91&lon=120.30",
+		"/v1/sun?lat=22.63&lon=east",
+		"/v1/sun?lat=22.63&lon=NaN",
+		"/v1/sun?lat=22.63&lon=120.30&date=21+June",
+		"/v1/sun?lat=22.63&lon=120.30&tz=Nowhere/Special",
+	} {
+		w := get(t, h, target)
+		var e map[string]string
+		if w.Code != http.StatusBadRequest || json.Unmarshal(w.Body.Bytes(), &e) != nil || e["error"] == "" {
+			t.Errorf("%s: status %d, %s", target, w.Code, w.Body)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cfw011566/sunevent"
+)
+
+// minInterval bounds how often a stream sends the position.
+const minInterval = time.Second
+
+// streamMessage is a message of /v1/stream: an event as it happens, or
+// the position of the Sun.
+type streamMessage struct {
+	Type string    `json:"type"`
+	Time time.Time `json:"time"`
+
+	// of events
+	Event string `json:"event,omitempty"`
+
+	// of positions
+	Azimuth  *float64 `json:"azimuth,omitempty"`
+	Altitude *float64 `json:"altitude,omitempty"`
+	Phase    string   `json:"phase,omitempty"`
+}
+
+func positionMessage(t time.Time, latitude, longitude float64) streamMessage {
+	p := sunevent.SunPosition(t, latitude, longitude)
+	return streamMessage{
+		Type:     "position",
+		Time:     t.Round(time.Second),
+		Azimuth:  &p.Azimuth,
+		Altitude: &p.Altitude,
+		Phase:    sunevent.PhaseAt(t, latitude, longitude).String(),
+	}
+}
+
+// stream pushes the events and the position of the Sun over a WebSocket
+// until the client goes away.
+func stream(w http.ResponseWriter, r *http.Request) {
+	if !allowGet(w, r) {
+		return
+	}
+	q, err := parseQuery(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	types, err := parseEvents(r.FormValue("events"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	interval := time.Minute
+	if s := r.FormValue("interval"); s != "" {
+		if interval, err = time.ParseDuration(s); err != nil || interval < minInterval {
+			writeError(w, fmt.Errorf("parameter interval must be a duration of at least %v", minInterval))
+			return
+		}
+	}
+	if !isWebSocket(r) {
+		w.Header().Set("Upgrade", "websocket")
+		writeJSON(w, http.StatusUpgradeRequired, map[string]string{"error": "/v1/stream is a WebSocket"})
+		return
+	}
+	c := upgrade(w, r)
+	if c == nil {
+		return
+	}
+	defer c.nc.Close()
+
+	done := make(chan struct{})
+	go c.read(done)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := sunevent.Subscribe(ctx, q.latitude, q.longitude, types...)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	send := func(m streamMessage) error {
+		m.Time = m.Time.In(q.loc)
+		b, err := json.Marshal(m)
+		if err != nil {
+			return err
+		}
+		return c.writeText(b)
+	}
+	if send(positionMessage(time.Now(), q.latitude, q.longitude)) != nil {
+		return
+	}
+	for {
+		var m streamMessage
+		select {
+		case <-done:
+			return
+		case o := <-events:
+			m = streamMessage{Type: "event", Time: o.Time.Round(time.Second), Event: o.Type.String()}
+		case now := <-ticker.C:
+			m = positionMessage(now, q.latitude, q.longitude)
+		}
+		if send(m) != nil {
+			return
+		}
+	}
+}
+
+// parseEvents parses a comma-separated list of events; empty means every
+// event.
+func parseEvents(s string) ([]sunevent.EventType, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var types []sunevent.EventType
+	for _, name := range strings.Split(s, ",") {
+		e, err := sunevent.ParseEventType(strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+		types = append(types, e)
+	}
+	return types, nil
+}
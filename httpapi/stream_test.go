@@ -0,0 +1,136 @@
+package httpapi
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// readServerFrame reads an unmasked frame of the server.
+func readServerFrame(t *testing.T, r *bufio.Reader) (byte, []byte) {
+	t.Helper()
+	var h [2]byte
+	if _, err := io.ReadFull(r, h[:]); err != nil {
+		t.Fatal(err)
+	}
+	n := uint64(h[1] & 0x7f)
+	switch n {
+	case 126:
+		var ext [2]byte
+		io.ReadFull(r, ext[:])
+		n = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		io.ReadFull(r, ext[:])
+		n = binary.BigEndian.Uint64(ext[:])
+	}
+	payload := make([]byte, n)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		t.Fatal(err)
+	}
+	return h[0] & 0x0f, payload
+}
+
+// writeClientFrame writes a masked frame of a client.
+func writeClientFrame(nc net.Conn, op byte, payload []byte) error {
+	mask := [4]byte{1, 2, 3, 4}
+	b := append([]byte{0x80 | op, 0x80 | byte(len(payload))}, mask[:]...)
+	for i, c := range payload {
+		b = append(b, c^mask[i%4])
+	}
+	_, err := nc.Write(b)
+	return err
+}
+
+func TestStream(t *testing.T) {
+	srv := httptest.NewServer(NewHandler())
+	defer srv.Close()
+	nc, err := net.Dial("tcp", strings.TrimPrefix(srv.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer nc.Close()
+	nc.SetDeadline(time.Now().Add(10 * time.Second))
+
+	// the handshake of RFC 6455, section 1.3
+	io.WriteString(nc, "GET /v1/stream?lat=22.63&lon=120.30&interval=1s&tz=Asia/Taipei HTTP/1.1\r\n"+
+		"Host: example.com\r\n"+
+		"Upgrade: websocket\r\n"+
+		"Connection: keep-alive, Upgrade\r\n"+
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n"+
+		"Sec-WebSocket-Version: 13\r\n\r\n")
+	r := bufio.NewReader(nc)
+	resp, err := http.ReadResponse(r, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols || resp.Header.Get("Sec-WebSocket-Accept") != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("handshake: %s, accept %q", resp.Status, resp.Header.Get("Sec-WebSocket-Accept"))
+	}
+
+	// the position on connection and after the interval
+	for i := 0; i < 2; i++ {
+		op, payload := readServerFrame(t, r)
+		var m streamMessage
+		if err := json.Unmarshal(payload, &m); op != opText || err != nil {
+			t.Fatalf("frame %d: opcode %d, %v", i, op, err)
+		}
+		if _, offset := m.Time.Zone(); m.Type != "position" || m.Azimuth == nil || m.Phase == "" || offset != 8*3600 {
+			t.Errorf("message %s", payload)
+		}
+	}
+
+	// a ping is answered, and so is a close; position messages may come
+	// between
+	writeClientFrame(nc, opPing, []byte("hello"))
+	writeClientFrame(nc, opClose, []byte{0x03, 0xe8})
+	var pong, closed bool
+	for !closed {
+		op, payload := readServerFrame(t, r)
+		switch op {
+		case opPong:
+			pong = string(payload) == "hello"
+		case opClose:
+			closed = true
+			if string(payload) != "\x03\xe8" {
+				t.Errorf("close status %q", payload)
+			}
+		}
+	}
+	if !pong {
+		t.Error("ping not answered")
+	}
+}
+
+func TestStreamErrors(t *testing.T) {
+	h := NewHandler()
+	if w := get(t, h, "/v1/stream?lat=22.63&lon=120.30"); w.Code != http.StatusUpgradeRequired {
+		t.Errorf("plain GET: status %d", w.Code)
+	}
+	for _, target := range []string{
+		"/v1/stream?lat=22.63",
+		"/v1/stream?lat=22.63&lon=120.30&interval=10ms",
+		"/v1/stream?lat=22.63&lon=120.30&events=sunset,teatime",
+	} {
+		if w := get(t, h, target); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status %d", target, w.Code)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/stream?lat=22.63&lon=120.30", nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "8")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest || w.Header().Get("Sec-WebSocket-Version") != "13" {
+		t.Errorf("version 8: status %d", w.Code)
+	}
+}
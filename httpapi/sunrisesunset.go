@@ -0,0 +1,160 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/cfw011566/sunevent"
+)
+
+// NewSunriseSunsetHandler returns a handler answering like the /json
+// endpoint of api.sunrise-sunset.org, so that clients of that service
+// can use a server of this package instead by changing the host:
+//
+//	http.Handle("/json", httpapi.NewSunriseSunsetHandler())
+//
+// It takes the parameters lat, lng, date (2006-01-02 or today, the
+// default), formatted (1, the default, for times such as 7:27:02 AM, or
+// 0 for ISO 8601 times and a day_length in seconds), tzid and callback
+// for JSONP, and answers
+//
+//	{"results":{"sunrise":"...","sunset":"...","solar_noon":"...","day_length":"...",
+//	  "civil_twilight_begin":"...","civil_twilight_end":"...", ...},"status":"OK","tzid":"UTC"}
+//
+// Sunrise and sunset are those of the upper limb of the Sun, with the
+// standard refraction of 34′ at the horizon, as that service computes
+// them. As it does, events that do not happen, in polar day or night, are
+// reported one second after midnight of 1 January 1970, and errors have a
+// status of INVALID_REQUEST, INVALID_DATE or INVALID_TZID.
+func NewSunriseSunsetHandler() http.Handler {
+	return http.HandlerFunc(sunriseSunset)
+}
+
+// Statuses of the sunrise-sunset.org API.
+const (
+	statusOK             = "OK"
+	statusInvalidRequest = "INVALID_REQUEST"
+	statusInvalidDate    = "INVALID_DATE"
+	statusInvalidTZID    = "INVALID_TZID"
+)
+
+// sunriseSunsetResults are the results of the sunrise-sunset.org API, as
+// strings when formatted and as times and seconds when not.
+type sunriseSunsetResults struct {
+	Sunrise                   interface{} `json:"sunrise"`
+	Sunset                    interface{} `json:"sunset"`
+	SolarNoon                 interface{} `json:"solar_noon"`
+	DayLength                 interface{} `json:"day_length"`
+	CivilTwilightBegin        interface{} `json:"civil_twilight_begin"`
+	CivilTwilightEnd          interface{} `json:"civil_twilight_end"`
+	NauticalTwilightBegin     interface{} `json:"nautical_twilight_begin"`
+	NauticalTwilightEnd       interface{} `json:"nautical_twilight_end"`
+	AstronomicalTwilightBegin interface{} `json:"astronomical_twilight_begin"`
+	AstronomicalTwilightEnd   interface{} `json:"astronomical_twilight_end"`
+}
+
+type sunriseSunsetResponse struct {
+	Results interface{} `json:"results"`
+	Status  string      `json:"status"`
+	TZID    string      `json:"tzid,omitempty"`
+}
+
+// missingEvent is how sunrise-sunset.org reports an event that does not
+// happen.
+var missingEvent = time.Unix(1, 0)
+
+// callbackName matches the JavaScript identifiers accepted as the name of
+// a JSONP callback.
+var callbackName = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$.]*$`)
+
+func sunriseSunset(w http.ResponseWriter, r *http.Request) {
+	if !allowGet(w, r) {
+		return
+	}
+	callback := r.FormValue("callback")
+	if callback != "" && !callbackName.MatchString(callback) {
+		writeSunriseSunset(w, "", http.StatusBadRequest, sunriseSunsetResponse{Results: "", Status: statusInvalidRequest})
+		return
+	}
+
+	lat, errLat := parseCoordinate(r, "lat", 90)
+	lng, errLng := parseCoordinate(r, "lng", 180)
+	if errLat != nil || errLng != nil {
+		writeSunriseSunset(w, callback, http.StatusBadRequest, sunriseSunsetResponse{Results: "", Status: statusInvalidRequest})
+		return
+	}
+	loc := time.UTC
+	if tzid := r.FormValue("tzid"); tzid != "" {
+		var err error
+		if loc, err = time.LoadLocation(tzid); err != nil {
+			writeSunriseSunset(w, callback, http.StatusBadRequest, sunriseSunsetResponse{Results: "", Status: statusInvalidTZID})
+			return
+		}
+	}
+	date := r.FormValue("date")
+	if date == "today" {
+		date = ""
+	}
+	day, err := query{loc: loc}.date(date)
+	if err != nil {
+		writeSunriseSunset(w, callback, http.StatusBadRequest, sunriseSunsetResponse{Results: "", Status: statusInvalidDate})
+		return
+	}
+
+	// sunrise and sunset are those of the upper limb with standard
+	// refraction, 50′ below the horizon, as on sunrise-sunset.org
+	d := sunevent.SunDayOn(day, lat, lng, sunevent.WithRefractionModel(sunevent.StandardRefraction{}))
+	formatted := r.FormValue("formatted") != "0"
+	format := func(t time.Time) interface{} {
+		if t.IsZero() {
+			t = missingEvent
+		}
+		t = t.In(loc).Round(time.Second)
+		if formatted {
+			return t.Format("3:04:05 PM")
+		}
+		return t.Format("2006-01-02T15:04:05-07:00")
+	}
+	results := sunriseSunsetResults{
+		Sunrise:                   format(d.Sunrise),
+		Sunset:                    format(d.Sunset),
+		SolarNoon:                 format(d.SolarNoon),
+		CivilTwilightBegin:        format(d.CivilDawn),
+		CivilTwilightEnd:          format(d.CivilDusk),
+		NauticalTwilightBegin:     format(d.NauticalDawn),
+		NauticalTwilightEnd:       format(d.NauticalDusk),
+		AstronomicalTwilightBegin: format(d.AstronomicalDawn),
+		AstronomicalTwilightEnd:   format(d.AstronomicalDusk),
+	}
+	seconds := int64(d.DayLength.Round(time.Second) / time.Second)
+	if formatted {
+		results.DayLength = fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+	} else {
+		results.DayLength = seconds
+	}
+	writeSunriseSunset(w, callback, http.StatusOK, sunriseSunsetResponse{
+		Results: results,
+		Status:  statusOK,
+		TZID:    loc.String(),
+	})
+}
+
+// writeSunriseSunset writes a response as JSON, or as JSONP if callback
+// is not empty.
+func writeSunriseSunset(w http.ResponseWriter, callback string, status int, v sunriseSunsetResponse) {
+	if callback == "" {
+		writeJSON(w, status, v)
+		return
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/javascript")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, "%s(%s);", callback, b)
+}
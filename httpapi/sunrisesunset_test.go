@@ -0,0 +1,75 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cfw011566/sunevent"
+)
+
+// sunriseSunsetJSON is a response of the sunrise-sunset.org API as its
+// clients decode it.
+type sunriseSunsetJSON struct {
+	Results map[string]interface{} `json:"results"`
+	Status  string                 `json:"status"`
+	TZID    string                 `json:"tzid"`
+}
+
+func TestSunriseSunset(t *testing.T) {
+	h := NewSunriseSunsetHandler()
+	w := get(t, h, "/json?lat=36.7201600&lng=-4.4203400&date=2026-06-21")
+	var resp sunriseSunsetJSON
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusOK || resp.Status != "OK" || resp.TZID != "UTC" {
+		t.Fatalf("status %d: %s", w.Code, w.Body)
+	}
+	// the upper limb, with standard refraction
+	rise, _ := sunevent.SunRiseOn(time.Date(2026, time.June, 21, 12, 0, 0, 0, time.UTC), 36.72016, -4.42034,
+		sunevent.WithRefractionModel(sunevent.StandardRefraction{}))
+	if got, want := resp.Results["sunrise"], rise.Round(time.Second).Format("3:04:05 PM"); got != want {
+		t.Errorf("sunrise %v, want %s", got, want)
+	}
+	if l, ok := resp.Results["day_length"].(string); !ok || !strings.HasPrefix(l, "14:") {
+		t.Errorf("day length %v", resp.Results["day_length"])
+	}
+
+	// unformatted, in a time zone, in polar day
+	w = get(t, h, "/json?lat=69.65&lng=18.96&date=2026-06-21&formatted=0&tzid=Europe/Oslo")
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Results["sunrise"] != "1970-01-01T01:00:01+01:00" || resp.Results["day_length"] != 86400.0 || resp.TZID != "Europe/Oslo" {
+		t.Errorf("polar day: %s", w.Body)
+	}
+
+	w = get(t, h, "/json?lat=36.72&lng=-4.42&callback=show")
+	if body := w.Body.String(); !strings.HasPrefix(body, `show({"results":{`) || !strings.HasSuffix(body, ");") || w.Header().Get("Content-Type") != "application/javascript" {
+		t.Errorf("JSONP %s", body)
+	}
+}
+
+func TestSunriseSunsetErrors(t *testing.T) {
+	h := NewSunriseSunsetHandler()
+	tests := []struct {
+		target, status string
+	}{
+		{"/json?lat=36.72", "INVALID_REQUEST"},
+		{"/json?lat=91&lng=0", "INVALID_REQUEST"},
+		{"/json?lat=36.72&lng=-4.42&date=tomorrow", "INVALID_DATE"},
+		{"/json?lat=36.72&lng=-4.42&tzid=Nowhere/Special", "INVALID_TZID"},
+		{"/json?lat=36.72&lng=-4.42&callback=alert(1)", "INVALID_REQUEST"},
+	}
+	for _, tt := range tests {
+		w := get(t, h, tt.target)
+		var resp sunriseSunsetJSON
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		if w.Code != http.StatusBadRequest || resp.Status != tt.status {
+			t.Errorf("%s: status %d, %s, want %s", tt.target, w.Code, w.Body, tt.status)
+		}
+	}
+}
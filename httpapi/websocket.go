@@ -0,0 +1,213 @@
+package httpapi
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The subset of RFC 6455 a server pushing text messages needs: the
+// opening handshake, unfragmented text frames, and answering pings and
+// the closing handshake. Messages of the client are read and ignored.
+
+// WebSocket opcodes.
+const (
+	opText  = 0x1
+	opClose = 0x8
+	opPing  = 0x9
+	opPong  = 0xa
+)
+
+// websocketGUID is appended to the key of the client to compute the
+// accept header.
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// maxControl bounds the payload of control frames, as RFC 6455 does.
+const maxControl = 125
+
+// maxData bounds the payload of the data frames of a client, which are
+// discarded.
+const maxData = 1 << 20
+
+// frameTimeout bounds each write to the client.
+const frameTimeout = 10 * time.Second
+
+// wsConn is a WebSocket connection of a client.
+type wsConn struct {
+	nc net.Conn
+	r  *bufio.Reader
+
+	mu sync.Mutex // serializes the frames written
+}
+
+// isWebSocket reports whether r asks for a WebSocket.
+func isWebSocket(r *http.Request) bool {
+	return headerContains(r.Header, "Connection", "upgrade") &&
+		headerContains(r.Header, "Upgrade", "websocket")
+}
+
+// headerContains reports whether the comma-separated values of the header
+// name hold token, ignoring case.
+func headerContains(h http.Header, name, token string) bool {
+	for _, v := range h[http.CanonicalHeaderKey(name)] {
+		for _, s := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(s), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// upgrade completes the opening handshake of a WebSocket request and
+// takes over the connection. If it cannot, it answers the request and
+// returns nil.
+func upgrade(w http.ResponseWriter, r *http.Request) *wsConn {
+	if r.Method != http.MethodGet {
+		writeError(w, errors.New("a WebSocket must be opened with GET"))
+		return nil
+	}
+	if r.Header.Get("Sec-Websocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		writeError(w, errors.New("unsupported WebSocket version"))
+		return nil
+	}
+	key := r.Header.Get("Sec-Websocket-Key")
+	if key == "" {
+		writeError(w, errors.New("missing Sec-WebSocket-Key"))
+		return nil
+	}
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		writeError(w, errors.New("WebSocket is only served over HTTP/1.1"))
+		return nil
+	}
+	nc, rw, err := hj.Hijack()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return nil
+	}
+
+	sum := sha1.Sum([]byte(key + websocketGUID))
+	nc.SetWriteDeadline(time.Now().Add(frameTimeout))
+	_, err = io.WriteString(nc, "HTTP/1.1 101 Switching Protocols\r\n"+
+		"Upgrade: websocket\r\n"+
+		"Connection: Upgrade\r\n"+
+		"Sec-WebSocket-Accept: "+base64.StdEncoding.EncodeToString(sum[:])+"\r\n\r\n")
+	if err != nil {
+		nc.Close()
+		return nil
+	}
+	return &wsConn{nc: nc, r: rw.Reader}
+}
+
+// writeText sends a text message.
+func (c *wsConn) writeText(msg []byte) error {
+	return c.writeFrame(opText, msg)
+}
+
+// writeFrame sends a final, unmasked frame.
+func (c *wsConn) writeFrame(op byte, payload []byte) error {
+	b := []byte{0x80 | op}
+	switch n := len(payload); {
+	case n <= 125:
+		b = append(b, byte(n))
+	case n <= 0xffff:
+		b = append(b, 126, byte(n>>8), byte(n))
+	default:
+		var ext [8]byte
+		binary.BigEndian.PutUint64(ext[:], uint64(n))
+		b = append(append(b, 127), ext[:]...)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.nc.SetWriteDeadline(time.Now().Add(frameTimeout))
+	_, err := c.nc.Write(append(b, payload...))
+	return err
+}
+
+// read reads the frames of the client, answering pings, until the client
+// closes the connection or it fails, and then closes done.
+func (c *wsConn) read(done chan<- struct{}) {
+	defer close(done)
+	for {
+		op, payload, err := c.readFrame()
+		if err != nil {
+			return
+		}
+		switch op {
+		case opPing:
+			if c.writeFrame(opPong, payload) != nil {
+				return
+			}
+		case opClose:
+			// echo the status code, completing the closing handshake
+			if len(payload) > 2 {
+				payload = payload[:2]
+			}
+			c.writeFrame(opClose, payload)
+			return
+		}
+	}
+}
+
+// readFrame reads a frame of the client and returns its opcode and, for
+// control frames, its payload; the payload of data frames is discarded.
+func (c *wsConn) readFrame() (byte, []byte, error) {
+	var h [2]byte
+	if _, err := io.ReadFull(c.r, h[:]); err != nil {
+		return 0, nil, err
+	}
+	op := h[0] & 0x0f
+	if h[1]&0x80 == 0 {
+		return 0, nil, errors.New("unmasked frame of a client")
+	}
+	n := uint64(h[1] & 0x7f)
+	switch n {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.r, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		n = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.r, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		n = binary.BigEndian.Uint64(ext[:])
+	}
+	var mask [4]byte
+	if _, err := io.ReadFull(c.r, mask[:]); err != nil {
+		return 0, nil, err
+	}
+
+	if op&0x8 == 0 {
+		if n > maxData {
+			return 0, nil, errors.New("frame too large")
+		}
+		_, err := io.CopyN(ioutil.Discard, c.r, int64(n))
+		return op, nil, err
+	}
+	if n > maxControl {
+		return 0, nil, errors.New("control frame too large")
+	}
+	payload := make([]byte, n)
+	if _, err := io.ReadFull(c.r, payload); err != nil {
+		return 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return op, payload, nil
+}
@@ -0,0 +1,85 @@
+package ical
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cfw011566/sunevent"
+)
+
+// DefaultEvents are the events of a Feed that does not choose them.
+var DefaultEvents = []sunevent.EventType{
+	sunevent.CivilDawn,
+	sunevent.Sunrise,
+	sunevent.Sunset,
+	sunevent.CivilDusk,
+}
+
+// Feed serves the upcoming events at a location as a calendar that
+// applications subscribe to, at a webcal:// URL or by adding it as a
+// calendar from the Internet, instead of importing a file once:
+//
+//	http.Handle("/calendar/home.ics", &ical.Feed{Name: "Sun at home", Latitude: lat, Longitude: lon})
+//
+// Every request computes the calendar again, from a day ago, so that the
+// events of today stay in it, to Days days ahead.
+type Feed struct {
+	Name      string
+	Latitude  float64
+	Longitude float64
+
+	// Events are the events of the calendar; nil means DefaultEvents.
+	Events []sunevent.EventType
+
+	// Days is the horizon of the calendar; zero means 30.
+	Days int
+
+	// Refresh is how often applications should fetch the calendar again;
+	// zero means 12 hours.
+	Refresh time.Duration
+}
+
+// ServeHTTP answers GET and HEAD requests with the calendar.
+func (f *Feed) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	events := f.Events
+	if events == nil {
+		events = DefaultEvents
+	}
+	days := f.Days
+	if days <= 0 {
+		days = 30
+	}
+	refresh := f.Refresh
+	if refresh <= 0 {
+		refresh = 12 * time.Hour
+	}
+
+	now := time.Now()
+	c := Calendar{
+		Name:      f.Name,
+		Latitude:  f.Latitude,
+		Longitude: f.Longitude,
+		Events:    sunevent.EventsBetween(f.Latitude, f.Longitude, now.Add(-24*time.Hour), now.AddDate(0, 0, days), events...),
+		Stamp:     now,
+		Refresh:   refresh,
+	}
+	var b bytes.Buffer
+	if err := c.Write(&b); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Cache-Control", "max-age="+strconv.Itoa(int(refresh/time.Second)))
+	w.Header().Set("Content-Length", strconv.Itoa(b.Len()))
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodGet {
+		b.WriteTo(w)
+	}
+}
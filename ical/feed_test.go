@@ -0,0 +1,55 @@
+package ical
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cfw011566/sunevent"
+)
+
+func TestFeed(t *testing.T) {
+	f := &Feed{Name: "Sun at home", Latitude: 22.63, Longitude: 120.30, Events: []sunevent.EventType{sunevent.Sunset}, Days: 7}
+	w := httptest.NewRecorder()
+	f.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/home.ics", nil))
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "text/calendar; charset=utf-8" || w.Header().Get("Cache-Control") != "max-age=43200" {
+		t.Fatalf("status %d, headers %v", w.Code, w.Header())
+	}
+	body := w.Body.String()
+	// from a day ago to a week ahead
+	if n := strings.Count(body, "SUMMARY:Sunset\r\n"); n != 8 {
+		t.Errorf("%d sunsets, want 8", n)
+	}
+	if !strings.Contains(body, "REFRESH-INTERVAL;VALUE=DURATION:PT12H\r\nX-PUBLISHED-TTL:PT12H\r\n") {
+		t.Error("feed lacks the refresh interval")
+	}
+
+	w = httptest.NewRecorder()
+	f.ServeHTTP(w, httptest.NewRequest(http.MethodHead, "/home.ics", nil))
+	if w.Body.Len() != 0 || w.Header().Get("Content-Length") != strconv.Itoa(len(body)) {
+		t.Errorf("HEAD: %d bytes of body, Content-Length %s", w.Body.Len(), w.Header().Get("Content-Length"))
+	}
+
+	w = httptest.NewRecorder()
+	f.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/home.ics", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST status %d", w.Code)
+	}
+}
+
+func TestDuration(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		12 * time.Hour:            "PT12H",
+		90 * time.Minute:          "PT1H30M",
+		time.Hour + 5*time.Second: "PT1H5S",
+		1500 * time.Millisecond:   "PT1S",
+		0:                         "PT0S",
+	} {
+		if got := duration(d); got != want {
+			t.Errorf("duration(%s) = %s, want %s", d, got, want)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+// Package ical writes solar events as an iCalendar (RFC 5545) calendar,
+// which calendar applications such as Google Calendar and Outlook can
+// import:
+//
+//	events := sunevent.EventsBetween(lat, lon, from, to, sunevent.Sunrise, sunevent.Sunset)
+//	c := ical.Calendar{Name: "Sun in Kaohsiung", Latitude: lat, Longitude: lon, Events: events}
+//	c.Write(os.Stdout)
+//
+// Each event is a moment without duration, written in UTC so that the
+// calendar shows it in the time zone of the reader.
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/cfw011566/sunevent"
+)
+
+// Calendar is a calendar of solar events at one location.
+type Calendar struct {
+	// Name is shown by the calendar application; it may be empty.
+	Name string
+
+	Latitude  float64
+	Longitude float64
+	Events    []sunevent.Event
+
+	// Stamp is the time the calendar was created, recorded in every
+	// event; the zero time means now.
+	Stamp time.Time
+
+	// Refresh, if not zero, is how often subscribed calendar applications
+	// should fetch the calendar again.
+	Refresh time.Duration
+}
+
+// utcLayout is the iCalendar form of a UTC time.
+const utcLayout = "20060102T150405Z"
+
+// Write writes the calendar to w.
+func (c *Calendar) Write(w io.Writer) error {
+	stamp := c.Stamp
+	if stamp.IsZero() {
+		stamp = time.Now()
+	}
+	b := bufio.NewWriter(w)
+	line := func(name, value string) {
+		writeLine(b, name+":"+value)
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//cfw011566//sunevent//EN")
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	if c.Name != "" {
+		line("X-WR-CALNAME", escape(c.Name))
+	}
+	if c.Refresh > 0 {
+		d := duration(c.Refresh)
+		line("REFRESH-INTERVAL;VALUE=DURATION", d)
+		line("X-PUBLISHED-TTL", d)
+	}
+	geo := fmt.Sprintf("%.6f;%.6f", c.Latitude, c.Longitude)
+	for _, e := range c.Events {
+		start := e.Time.UTC().Format(utcLayout)
+		line("BEGIN", "VEVENT")
+		line("UID", fmt.Sprintf("%s-%s-%.4f-%.4f@sunevent", e.Type, start, c.Latitude, c.Longitude))
+		line("DTSTAMP", stamp.UTC().Format(utcLayout))
+		line("DTSTART", start)
+		line("SUMMARY", escape(Summary(e.Type)))
+		line("GEO", geo)
+		line("TRANSP", "TRANSPARENT")
+		line("END", "VEVENT")
+	}
+	line("END", "VCALENDAR")
+	return b.Flush()
+}
+
+// Summary returns the title of an event type in a calendar, such as
+// "Civil dawn".
+func Summary(e sunevent.EventType) string {
+	s := strings.Replace(e.String(), "_", " ", -1)
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// duration returns the iCalendar form of d in whole seconds, such as
+// PT12H.
+func duration(d time.Duration) string {
+	s := int64(d / time.Second)
+	v := "PT"
+	if h := s / 3600; h > 0 {
+		v += fmt.Sprintf("%dH", h)
+	}
+	if m := s / 60 % 60; m > 0 {
+		v += fmt.Sprintf("%dM", m)
+	}
+	if s%60 > 0 || v == "PT" {
+		v += fmt.Sprintf("%dS", s%60)
+	}
+	return v
+}
+
+// escape escapes a text value.
+func escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(s)
+}
+
+// writeLine writes a content line ended by CRLF, folded so that no line
+// is longer than 75 octets, without splitting a UTF-8 sequence.
+func writeLine(w *bufio.Writer, s string) {
+	limit := 75
+	for len(s) > limit {
+		i := limit
+		for i > 0 && s[i]&0xC0 == 0x80 {
+			i--
+		}
+		w.WriteString(s[:i])
+		w.WriteString("\r\n ")
+		s = s[i:]
+		// the leading space counts towards the next line
+		limit = 74
+	}
+	w.WriteString(s)
+	w.WriteString("\r\n")
+}
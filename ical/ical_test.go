@@ -0,0 +1,57 @@
+package ical
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/cfw011566/sunevent"
+)
+
+func TestWrite(t *testing.T) {
+	rise := time.Date(2026, time.June, 21, 5, 5, 30, 0, time.FixedZone("CST", 8*3600))
+	c := Calendar{
+		Name:      "Sun in Kaohsiung; a long name, long enough to be folded over two lines",
+		Latitude:  22.63,
+		Longitude: 120.30,
+		Events:    []sunevent.Event{{Type: sunevent.CivilDawn, Time: rise}},
+		Stamp:     time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC),
+	}
+	var b bytes.Buffer
+	if err := c.Write(&b); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\nVERSION:2.0\r\n",
+		"X-WR-CALNAME:Sun in Kaohsiung\\; a long name\\, long enough to be folded over\r\n  two lines\r\n",
+		"UID:civil_dawn-20260620T210530Z-22.6300-120.3000@sunevent\r\n",
+		"DTSTAMP:20260101T000000Z\r\nDTSTART:20260620T210530Z\r\nSUMMARY:Civil dawn\r\nGEO:22.630000;120.300000\r\n",
+		"END:VEVENT\r\nEND:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("calendar lacks %q:\n%s", want, out)
+		}
+	}
+	for _, l := range strings.Split(out, "\r\n") {
+		if len(l) > 75 {
+			t.Errorf("line of %d octets: %q", len(l), l)
+		}
+	}
+}
+
+func TestWriteLineUTF8(t *testing.T) {
+	// a fold never splits a character
+	var b bytes.Buffer
+	w := bufio.NewWriter(&b)
+	writeLine(w, "X-WR-CALNAME:"+strings.Repeat("日", 40))
+	w.Flush()
+	for _, l := range strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n") {
+		if !utf8.ValidString(strings.TrimPrefix(l, " ")) || len(l) > 75 {
+			t.Errorf("folded line %q", l)
+		}
+	}
+}
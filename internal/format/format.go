@@ -0,0 +1,23 @@
+// Package format holds the text formatting of times and durations shared
+// by the report package and the sunevent command.
+package format
+
+import (
+	"fmt"
+	"time"
+)
+
+// Clock formats t as a wall-clock time, or as missing if t is zero because
+// the event does not happen.
+func Clock(t time.Time, missing string) string {
+	if t.IsZero() {
+		return missing
+	}
+	return t.Format("15:04")
+}
+
+// Length formats a day length as hours and minutes.
+func Length(d time.Duration) string {
+	d = d.Round(time.Minute)
+	return fmt.Sprintf("%dh %02dm", int(d/time.Hour), int(d%time.Hour/time.Minute))
+}
@@ -0,0 +1,33 @@
+package format
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClock(t *testing.T) {
+	at := time.Date(2026, time.March, 20, 6, 4, 59, 0, time.UTC)
+	if got := Clock(at, "-"); got != "06:04" {
+		t.Errorf("Clock = %q, want 06:04", got)
+	}
+	if got := Clock(time.Time{}, "—"); got != "—" {
+		t.Errorf("Clock of the zero time = %q, want the placeholder", got)
+	}
+}
+
+func TestLength(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{12*time.Hour + 7*time.Minute + 29*time.Second, "12h 07m"},
+		{12*time.Hour + 59*time.Minute + 30*time.Second, "13h 00m"},
+		{0, "0h 00m"},
+		{24 * time.Hour, "24h 00m"},
+	}
+	for _, tt := range tests {
+		if got := Length(tt.d); got != tt.want {
+			t.Errorf("Length(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
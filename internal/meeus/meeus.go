@@ -0,0 +1,26 @@
+// Package meeus holds formulas of Meeus, Astronomical Algorithms, shared
+// by the Moon calculations of package sunevent and by package vsop87.
+package meeus
+
+import "math"
+
+// Nutation returns the nutation in longitude and in obliquity in degrees
+// at T Julian centuries of terrestrial time from J2000.0, to about half an
+// arcsecond (Meeus, chapter 22).
+func Nutation(T float64) (dpsi, deps float64) {
+	omega := 125.04452 - 1934.136261*T
+	L := 280.4665 + 36000.7698*T
+	L1 := 218.3165 + 481267.8813*T
+
+	dpsi = -17.20*sind(omega) - 1.32*sind(2*L) - 0.23*sind(2*L1) + 0.21*sind(2*omega)
+	deps = 9.20*cosd(omega) + 0.57*cosd(2*L) + 0.10*cosd(2*L1) - 0.09*cosd(2*omega)
+	return dpsi / 3600.0, deps / 3600.0
+}
+
+func sind(x float64) float64 {
+	return math.Sin(x * math.Pi / 180.0)
+}
+
+func cosd(x float64) float64 {
+	return math.Cos(x * math.Pi / 180.0)
+}
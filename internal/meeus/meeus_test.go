@@ -0,0 +1,15 @@
+package meeus
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNutation(t *testing.T) {
+	// Meeus, example 22.a: 1987 April 10 at 0h TD
+	T := (2446895.5 - 2451545.0) / 36525
+	dpsi, deps := Nutation(T)
+	if math.Abs(dpsi*3600+3.788) > 0.5 || math.Abs(deps*3600-9.443) > 0.5 {
+		t.Errorf("nutation = %.3f″, %.3f″, want -3.788″, +9.443″", dpsi*3600, deps*3600)
+	}
+}
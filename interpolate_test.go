@@ -0,0 +1,70 @@
+package sunevent
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestInterpolatePosition(t *testing.T) {
+	t1 := time.Date(2026, time.June, 21, 0, 0, 0, 0, time.UTC)
+	t2 := t1.Add(10 * time.Minute)
+	tests := []struct {
+		name   string
+		p1, p2 Position
+		t      time.Time
+		opts   []Option
+		want   Position
+	}{
+		{"midpoint", Position{100, 10}, Position{110, 20}, t1.Add(5 * time.Minute), nil, Position{105, 15}},
+		{"start", Position{100, 10}, Position{110, 20}, t1, nil, Position{100, 10}},
+		{"across north", Position{350, 5}, Position{10, 5}, t1.Add(5 * time.Minute), nil, Position{0, 5}},
+		{"across north backwards", Position{10, 5}, Position{350, 5}, t1.Add(7*time.Minute + 30*time.Second), nil, Position{355, 5}},
+		{"extrapolated", Position{100, 10}, Position{110, 20}, t1.Add(20 * time.Minute), nil, Position{120, 30}},
+		{"radians", Position{2*math.Pi - 0.1, 0}, Position{0.1, 0.2}, t1.Add(5 * time.Minute), []Option{WithUnits(Radians)}, Position{0, 0.1}},
+	}
+	for _, tt := range tests {
+		got := InterpolatePosition(t1, tt.p1, t2, tt.p2, tt.t, tt.opts...)
+		full := 360.0
+		if len(tt.opts) > 0 {
+			full = 2 * math.Pi
+		}
+		if !near(wrapDifference(got.Azimuth, tt.want.Azimuth, full), 0, 1e-9) || !near(got.Altitude, tt.want.Altitude, 1e-9) {
+			t.Errorf("%s: InterpolatePosition = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestInterpolateSamples(t *testing.T) {
+	const latitude, longitude = 45.0, 7.0
+	date := time.Date(2026, time.June, 21, 12, 0, 0, 0, time.UTC)
+	samples := ElevationSeries(date, latitude, longitude, 15*time.Minute)
+
+	// the spline passes through the samples
+	for _, s := range samples[:8] {
+		if got := InterpolateSamples(samples, s.Time); !near(got.Azimuth, s.Azimuth, 1e-9) || !near(got.Altitude, s.Altitude, 1e-9) {
+			t.Errorf("at sample %s: %+v, want %+v", s.Time, got, s.Position)
+		}
+	}
+
+	// and stays close to the path between them
+	for tm := samples[0].Time; tm.Before(samples[len(samples)-1].Time); tm = tm.Add(7 * time.Minute) {
+		got := InterpolateSamples(samples, tm)
+		want := SunPosition(tm, latitude, longitude)
+		if !near(got.Altitude, want.Altitude, 0.02) || !near(wrapDifference(got.Azimuth, want.Azimuth, 360), 0, 0.5) {
+			t.Errorf("at %s: %+v, want %+v", tm, got, want)
+		}
+	}
+
+	// outside the series the ends are held
+	first, last := samples[0], samples[len(samples)-1]
+	if got := InterpolateSamples(samples, first.Time.Add(-time.Hour)); got != first.Position {
+		t.Errorf("before the series: %+v, want %+v", got, first.Position)
+	}
+	if got := InterpolateSamples(samples, last.Time.Add(time.Hour)); got != last.Position {
+		t.Errorf("after the series: %+v, want %+v", got, last.Position)
+	}
+	if got := InterpolateSamples(nil, first.Time); got != (Position{}) {
+		t.Errorf("no samples: %+v, want the zero position", got)
+	}
+}
@@ -0,0 +1,47 @@
+package sunevent
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// ErrLongitudeSign is returned by CheckLongitude when a longitude looks
+// like it has the wrong sign.
+var ErrLongitudeSign = errors.New("sunevent: longitude sign does not match the time zone")
+
+// ErrLongitudeRange is returned by CheckLongitude when a longitude lies
+// outside the range declared with WithLongitudeRange.
+var ErrLongitudeRange = errors.New("sunevent: longitude outside the declared range")
+
+// CheckLongitude reports whether longitude, interpreted with opts, is
+// plausible for the time zone of date. A time zone's offset is roughly
+// longitude/15 hours, so a longitude of the wrong sign puts every event
+// hours away from the expected local time. CheckLongitude returns
+// ErrLongitudeSign when negating the longitude would match the zone far
+// better; it cannot detect errors near Greenwich, where both signs fit.
+//
+// It first returns ErrLongitudeRange if longitude is outside the range
+// declared with WithLongitudeRange, such as 200 where [-180, 180] is
+// expected, which often means the data uses another convention.
+func CheckLongitude(longitude float64, date time.Time, opts ...Option) error {
+	o := newOptions(opts)
+	if !o.inLongitudeRange(longitude) {
+		return ErrLongitudeRange
+	}
+	longitude = o.longitude(longitude)
+
+	_, offset := date.Zone()
+	zone := float64(offset) / 3600.0 * 15.0
+
+	// zones commonly span 30° or more either side of their meridian
+	// (China, Spain), so only flag clear mismatches
+	const slack = 45.0
+
+	given := math.Abs(angleDifference(longitude, zone))
+	flipped := math.Abs(angleDifference(-longitude, zone))
+	if given > slack && flipped < given-slack {
+		return ErrLongitudeSign
+	}
+	return nil
+}
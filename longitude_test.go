@@ -0,0 +1,97 @@
+package sunevent
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestLongitudeConventions(t *testing.T) {
+	at := time.Date(2026, time.June, 21, 3, 0, 0, 0, time.UTC)
+	const latitude = 40.71
+	want := SunPosition(at, latitude, -74.01)
+
+	tests := []struct {
+		name      string
+		longitude float64
+		opts      []Option
+	}{
+		{"west positive", 74.01, []Option{WithLongitudeConvention(WestPositive)}},
+		{"unsigned", 285.99, nil},
+		{"unsigned range", 285.99, []Option{WithLongitudeRange(Unsigned360)}},
+		{"wrapped twice", -74.01 + 720, nil},
+	}
+	for _, tt := range tests {
+		if got := SunPosition(at, latitude, tt.longitude, tt.opts...); !near(got.Azimuth, want.Azimuth, 1e-9) || !near(got.Altitude, want.Altitude, 1e-9) {
+			t.Errorf("%s: SunPosition = %+v, want %+v", tt.name, got, want)
+		}
+	}
+
+	date := time.Date(2026, time.June, 21, 12, 0, 0, 0, time.FixedZone("EDT", -4*3600))
+	east, _ := SunRiseOn(date, latitude, -74.01)
+	west, err := SunRiseOn(date, latitude, 74.01, WithLongitudeConvention(WestPositive))
+	if err != nil || !west.Equal(east) {
+		t.Errorf("sunrise in New York, west positive = %s, %v, want %s", west, err, east)
+	}
+}
+
+func TestCheckLongitude(t *testing.T) {
+	taipei := time.Date(2026, time.June, 21, 12, 0, 0, 0, time.FixedZone("CST", 8*3600))
+	ny := time.Date(2026, time.June, 21, 12, 0, 0, 0, time.FixedZone("EDT", -4*3600))
+	london := time.Date(2026, time.June, 21, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		longitude float64
+		date      time.Time
+		opts      []Option
+		want      error
+	}{
+		{"Taipei", 120.30, taipei, nil, nil},
+		{"Taipei negated", -120.30, taipei, nil, ErrLongitudeSign},
+		{"New York", -74.01, ny, nil, nil},
+		{"New York negated", 74.01, ny, nil, ErrLongitudeSign},
+		{"New York west positive", 74.01, ny, []Option{WithLongitudeConvention(WestPositive)}, nil},
+		{"Greenwich either way", 2.35, london, nil, nil},
+		{"Greenwich negated", -2.35, london, nil, nil},
+		{"out of range", 285.99, ny, nil, ErrLongitudeRange},
+		{"unsigned", 285.99, ny, []Option{WithLongitudeRange(Unsigned360)}, nil},
+		{"negative unsigned", -74.01, ny, []Option{WithLongitudeRange(Unsigned360)}, ErrLongitudeRange},
+	}
+	for _, tt := range tests {
+		if got := CheckLongitude(tt.longitude, tt.date, tt.opts...); got != tt.want {
+			t.Errorf("%s: CheckLongitude(%v) = %v, want %v", tt.name, tt.longitude, got, tt.want)
+		}
+	}
+}
+
+func TestLongitudeNormalization(t *testing.T) {
+	tests := []struct {
+		in, want float64
+		o        Options
+	}{
+		{0, 0, Options{}},
+		{180, -180, Options{}},
+		{-180, -180, Options{}},
+		{200, -160, Options{}},
+		{359.5, -0.5, Options{}},
+		{-540, -180, Options{}},
+		{1e12 + 90, math.Mod(1e12+270, 360) - 180, Options{}},
+		{200, 160, Options{Longitude: WestPositive}},
+		{200, -160, Options{LongitudeRange: Unsigned360}},
+	}
+	for _, tt := range tests {
+		if got := tt.o.longitude(tt.in); !near(got, tt.want, 1e-6) {
+			t.Errorf("%+v: longitude(%v) = %v, want %v", tt.o, tt.in, got, tt.want)
+		}
+	}
+
+	// the declared range only matters to CheckLongitude
+	ny := time.Date(2026, time.June, 21, 12, 0, 0, 0, time.FixedZone("EDT", -4*3600))
+	if err := CheckLongitude(285.99, ny); err != ErrLongitudeRange {
+		t.Errorf("CheckLongitude(285.99) = %v, want ErrLongitudeRange", err)
+	}
+	if err := CheckLongitude(180, ny, WithLongitudeRange(Unsigned360)); err != nil {
+		t.Errorf("CheckLongitude(180) in [0, 360) = %v", err)
+	}
+}
@@ -0,0 +1,246 @@
+package sunevent
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/cfw011566/sunevent/internal/meeus"
+)
+
+var (
+	// ErrNoMoonrise is returned when the Moon does not rise on the date.
+	// Besides the polar regions this happens about once a month
+	// everywhere, as moonrise is some 50 minutes later each day and skips
+	// a date.
+	ErrNoMoonrise = errors.New("sunevent: the moon does not rise on this date")
+	// ErrNoMoonset is returned when the Moon does not set on the date.
+	ErrNoMoonset = errors.New("sunevent: the moon does not set on this date")
+	// ErrNoMoonTransit is returned when the Moon does not cross the
+	// meridian on the date, which happens about once a month.
+	ErrNoMoonTransit = errors.New("sunevent: the moon does not cross the meridian on this date")
+)
+
+// MoonRiseOn returns the moonrise on the civil date of date, in the time
+// zone of date: the moment the upper limb of the Moon appears on the
+// horizon, with standard refraction. It returns ErrNoMoonrise if the Moon
+// does not rise that day.
+func MoonRiseOn(date time.Time, latitude, longitude float64, opts ...Option) (time.Time, error) {
+	o := newOptions(opts)
+	t, ok := o.moonCrossing(date, latitude, o.longitude(longitude), true)
+	if !ok {
+		return time.Time{}, ErrNoMoonrise
+	}
+	return t, nil
+}
+
+// MoonSetOn returns the moonset on the civil date of date, in the time
+// zone of date. It returns ErrNoMoonset if the Moon does not set that day.
+func MoonSetOn(date time.Time, latitude, longitude float64, opts ...Option) (time.Time, error) {
+	o := newOptions(opts)
+	t, ok := o.moonCrossing(date, latitude, o.longitude(longitude), false)
+	if !ok {
+		return time.Time{}, ErrNoMoonset
+	}
+	return t, nil
+}
+
+// MoonTransitOn returns the upper transit of the Moon on the civil date of
+// date, in the time zone of date: the moment it crosses the meridian and
+// culminates, whether or not it is above the horizon. Solunar tables
+// count the transits as the major feeding periods. It returns
+// ErrNoMoonTransit if the Moon does not cross the meridian that day.
+func MoonTransitOn(date time.Time, latitude, longitude float64, opts ...Option) (time.Time, error) {
+	o := newOptions(opts)
+	longitude = o.longitude(longitude)
+	start, end := o.civilDay(date)
+
+	hourAngle := func(t time.Time) float64 {
+		ra, _, _ := moonEquatorial(o.julianCentury(t))
+		return localHourAngle(julianCentury(t), ra/15.0, t, longitude)
+	}
+
+	// the hour angle grows by about 14.5° an hour and wraps at 180°
+	const step = time.Hour
+	a, ha := start, hourAngle(start)
+	for a.Before(end) {
+		b := a.Add(step)
+		if b.After(end) {
+			b = end
+		}
+		hb := hourAngle(b)
+
+		if ha < 0 && hb >= 0 && hb < 90.0 {
+			for b.Sub(a) > time.Second {
+				mid := a.Add(b.Sub(a) / 2)
+				if hm := hourAngle(mid); hm < 0 {
+					a = mid
+				} else {
+					b = mid
+				}
+			}
+			if !b.Before(end) {
+				break
+			}
+			return b.In(date.Location()).Truncate(time.Second), nil
+		}
+		a, ha = b, hb
+	}
+	return time.Time{}, ErrNoMoonTransit
+}
+
+// MoonPosition returns the position of the Moon at the given instant as
+// seen by an observer at the location, and its distance from the observer
+// in km. The elevation is topocentric: at the horizon the parallax lowers
+// the Moon by about a degree compared to its position from the centre of
+// the Earth. The position options apply as for SunPosition.
+func MoonPosition(t time.Time, latitude, longitude float64, opts ...Option) (p Position, distance float64) {
+	o := newOptions(opts)
+	azimuth, elevation, geocentric := o.moonAt(t, latitude, o.longitude(longitude))
+
+	// seen from the surface, the Earth's radius shifts the Moon away from
+	// the zenith
+	// h' = h - parallax * cos(h)
+	// d' = sqrt(d^2 - 2 d r sin(h) + r^2)
+	const radius = 6378.14
+	topocentric := elevation - moonParallax(geocentric)*degreeCos(elevation)
+	distance = math.Sqrt(geocentric*geocentric - 2*geocentric*radius*degreeSin(elevation) + radius*radius)
+
+	return o.position(azimuth, topocentric), distance
+}
+
+// moonCrossing returns the first time on the civil date of date when the
+// Moon rises or sets. The altitude of the Moon above its rising altitude
+// is sampled hourly, which only misses brief appearances where the Moon
+// grazes the horizon at high latitudes, and sign changes are refined by
+// bisection to a second.
+func (o Options) moonCrossing(date time.Time, latitude, longitude float64, rising bool) (time.Time, bool) {
+	start, end := o.civilDay(date)
+
+	above := func(t time.Time) float64 {
+		_, elevation, distance := o.moonAt(t, latitude, longitude)
+		return elevation - o.moonriseAltitude(distance)
+	}
+
+	const step = time.Hour
+	a, fa := start, above(start)
+	for a.Before(end) {
+		b := a.Add(step)
+		if b.After(end) {
+			b = end
+		}
+		fb := above(b)
+
+		if (fa < 0 && fb >= 0 && rising) || (fa >= 0 && fb < 0 && !rising) {
+			for b.Sub(a) > time.Second {
+				mid := a.Add(b.Sub(a) / 2)
+				if fm := above(mid); (fm < 0) == (fa < 0) {
+					a, fa = mid, fm
+				} else {
+					b = mid
+				}
+			}
+			if !b.Before(end) {
+				return time.Time{}, false
+			}
+			return b.In(date.Location()).Truncate(time.Second), true
+		}
+		a, fa = b, fb
+	}
+	return time.Time{}, false
+}
+
+// moonriseAltitude returns the geocentric altitude of the centre of the
+// Moon at moonrise and moonset, for the Moon at distance km: the
+// horizontal parallax lifts the Moon seen from the surface less its
+// semidiameter, and refraction and the dip of the horizon lower it
+// (Meeus, chapter 15).
+// h0 = 0.7275 * parallax - 34′
+func (o Options) moonriseAltitude(distance float64) float64 {
+	return 0.7275*moonParallax(distance) - 34.0/60.0 - o.dip()
+}
+
+// moonParallax returns the equatorial horizontal parallax in degrees of
+// the Moon at distance km.
+func moonParallax(distance float64) float64 {
+	return degreeAsin(6378.14 / distance)
+}
+
+// moonAt returns the geocentric azimuth (north clockwise) and elevation of
+// the Moon in degrees and its distance in km at the instant t. The
+// longitude is in degrees east.
+func (o Options) moonAt(t time.Time, latitude, longitude float64) (azimuth, elevation, distance float64) {
+	ra, dec, distance := moonEquatorial(o.julianCentury(t))
+	H := localHourAngle(julianCentury(t), ra/15.0, t, longitude)
+	azimuth, elevation = horizontal(H, degreeSin(dec), degreeCos(dec), latitude)
+	return azimuth, elevation, distance
+}
+
+// moonEquatorial returns the Moon's apparent right ascension and
+// declination in degrees and its distance in km at T, Julian centuries
+// since J2000.0 in terrestrial time.
+func moonEquatorial(T float64) (ra, dec, distance float64) {
+	lambda, beta, distance := moonEcliptic(T)
+
+	dpsi, deps := meeus.Nutation(T)
+	lambda += dpsi
+	epsilon := 23.0 + 26.0/60.0 + (21.448-46.8150*T)/3600.0 + deps
+
+	ra = radianToDegree(math.Atan2(degreeSin(lambda)*degreeCos(epsilon)-degreeTan(beta)*degreeSin(epsilon), degreeCos(lambda)))
+	dec = degreeAsin(degreeSin(beta)*degreeCos(epsilon) + degreeCos(beta)*degreeSin(epsilon)*degreeSin(lambda))
+	return normalizeRange(ra, 360), dec, distance
+}
+
+// moonEcliptic returns the Moon's geocentric longitude and latitude in
+// degrees, referred to the mean equinox of the date, and its distance in
+// km at T (Meeus, chapter 47).
+func moonEcliptic(T float64) (lambda, beta, distance float64) {
+	Lp := 218.3164477 + T*(481267.88123421+T*(-0.0015786+T*(1.0/538841-T/65194000)))
+	D, M, Mp, F := moonArguments(T)
+	A1 := 119.75 + 131.849*T
+	A2 := 53.09 + 479264.290*T
+	A3 := 313.45 + 481266.484*T
+
+	// the terms in M depend on the eccentricity of the Earth's orbit
+	E := 1 - T*(0.002516+0.0000074*T)
+	eccentricity := func(m float64) float64 {
+		switch math.Abs(m) {
+		case 1:
+			return E
+		case 2:
+			return E * E
+		}
+		return 1
+	}
+
+	var sl, sr, sb float64
+	for _, t := range moonLongitudeDistance {
+		arg := t.d*D + t.m*M + t.mp*Mp + t.f*F
+		e := eccentricity(t.m)
+		sl += t.a * e * degreeSin(arg)
+		sr += t.r * e * degreeCos(arg)
+	}
+	for _, t := range moonLatitude {
+		sb += t.a * eccentricity(t.m) * degreeSin(t.d*D+t.m*M+t.mp*Mp+t.f*F)
+	}
+
+	sl += 3958*degreeSin(A1) + 1962*degreeSin(Lp-F) + 318*degreeSin(A2)
+	sb += -2235*degreeSin(Lp) + 382*degreeSin(A3) + 175*degreeSin(A1-F) +
+		175*degreeSin(A1+F) + 127*degreeSin(Lp-Mp) - 115*degreeSin(Lp+Mp)
+
+	lambda = normalizeRange(Lp+sl/1e6, 360)
+	beta = sb / 1e6
+	distance = 385000.56 + sr/1000
+	return lambda, beta, distance
+}
+
+// moonArguments returns the mean elongation of the Moon D, the mean
+// anomalies of the Sun M and of the Moon Mp and the Moon's argument of
+// latitude F in degrees at T (Meeus 47.2 to 47.5).
+func moonArguments(T float64) (D, M, Mp, F float64) {
+	D = 297.8501921 + T*(445267.1114034+T*(-0.0018819+T*(1.0/545868-T/113065000)))
+	M = 357.5291092 + T*(35999.0502909+T*(-0.0001536+T/24490000))
+	Mp = 134.9633964 + T*(477198.8675055+T*(0.0087414+T*(1.0/69699-T/14712000)))
+	F = 93.2720950 + T*(483202.0175233+T*(-0.0036539+T*(-1.0/3526000+T/863310000)))
+	return D, M, Mp, F
+}
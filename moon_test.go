@@ -0,0 +1,138 @@
+package sunevent
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMoonEquatorial(t *testing.T) {
+	// Meeus, Astronomical Algorithms, example 47.a: 1992 April 12, 0h TD
+	T := (2448724.5 - 2451545.0) / 36525
+	lambda, beta, _ := moonEcliptic(T)
+	if !near(lambda, 133.162655, 0.002) || !near(beta, -3.229126, 0.002) {
+		t.Errorf("moonEcliptic = %v°, %v°, want 133.162655°, -3.229126°", lambda, beta)
+	}
+	ra, dec, distance := moonEquatorial(T)
+	if !near(ra, 134.688470, 0.002) || !near(dec, 13.768368, 0.002) || !near(distance, 368409.7, 20) {
+		t.Errorf("moonEquatorial = %v°, %v°, %v km, want 134.688470°, 13.768368°, 368409.7 km", ra, dec, distance)
+	}
+}
+
+func TestMoonRiseSet(t *testing.T) {
+	const latitude, longitude = 40.7, -74.0
+	loc := time.FixedZone("EST", -5*3600)
+
+	for day := 1; day <= 31; day++ {
+		date := time.Date(2026, time.March, day, 12, 0, 0, 0, loc)
+		rise, errRise := MoonRiseOn(date, latitude, longitude)
+		set, errSet := MoonSetOn(date, latitude, longitude)
+
+		// moonrise is some 50 minutes later each day, so it skips a date
+		// once a month, and so does moonset
+		switch day {
+		case 9:
+			if errRise != ErrNoMoonrise {
+				t.Errorf("9 March: moonrise %s, %v, want %v", rise, errRise, ErrNoMoonrise)
+			}
+			continue
+		case 23:
+			if errSet != ErrNoMoonset {
+				t.Errorf("23 March: moonset %s, %v, want %v", set, errSet, ErrNoMoonset)
+			}
+			continue
+		}
+		if errRise != nil || errSet != nil {
+			t.Errorf("%d March: %v, %v", day, errRise, errSet)
+			continue
+		}
+
+		for _, at := range []time.Time{rise, set} {
+			if at.Day() != day || at.Location() != loc {
+				t.Errorf("%d March: %s not on the civil date", day, at)
+			}
+			// the upper limb is 34′ below the horizon, raised by refraction
+			p, distance := MoonPosition(at, latitude, longitude)
+			if limb := p.Altitude + degreeAsin(1737.4/distance); !near(limb, -34.0/60.0, 0.01) {
+				t.Errorf("%d March: upper limb at %v° at %s", day, limb, at)
+			}
+		}
+	}
+
+	// at 78°N the Moon is circumpolar or stays down for days on end
+	var neither int
+	for date := time.Date(2026, time.January, 1, 12, 0, 0, 0, time.UTC); date.Year() == 2026; date = date.AddDate(0, 0, 1) {
+		_, errRise := MoonRiseOn(date, 78, 15)
+		_, errSet := MoonSetOn(date, 78, 15)
+		if errRise != nil && errSet != nil {
+			neither++
+		}
+		if _, errRise = MoonRiseOn(date, 0, 15); errRise != nil {
+			if _, errSet = MoonSetOn(date, 0, 15); errSet != nil {
+				t.Errorf("%s: neither moonrise nor moonset at the equator", date)
+			}
+		}
+	}
+	if neither < 100 {
+		t.Errorf("%d days without moonrise or moonset at 78°N", neither)
+	}
+}
+
+func TestMoonPosition(t *testing.T) {
+	at := time.Date(2026, time.March, 5, 3, 0, 0, 0, time.UTC)
+	for _, latitude := range []float64{40.7, 0, -33.9} {
+		p, distance := MoonPosition(at, latitude, -74)
+		azimuth, elevation, geocentric := Options{}.moonAt(at, latitude, -74)
+
+		// the observer sees the Moon lower by the parallax, about a degree
+		// at the horizon, and closer by up to the Earth's radius
+		if drop := elevation - p.Altitude; !near(drop, moonParallax(geocentric)*degreeCos(elevation), 0.001) {
+			t.Errorf("latitude %v: parallax lowers the Moon by %v°", latitude, drop)
+		}
+		if closer := geocentric - distance; !near(closer, 6378.14*degreeSin(elevation), 100) {
+			t.Errorf("latitude %v: %v km from the observer, %v km from the centre", latitude, distance, geocentric)
+		}
+		if p.Azimuth != azimuth {
+			t.Errorf("latitude %v: azimuth %v, want %v", latitude, p.Azimuth, azimuth)
+		}
+
+		q, _ := MoonPosition(at, latitude, -74, WithAzimuth(SouthClockwise), WithVerticalAngle(ZenithAngle))
+		if !near(q.Azimuth, normalizeRange(p.Azimuth+180, 360), 1e-9) || !near(q.Altitude, 90-p.Altitude, 1e-9) {
+			t.Errorf("latitude %v: %+v with options, %+v without", latitude, q, p)
+		}
+	}
+}
+
+func TestMoonTransitOn(t *testing.T) {
+	const latitude, longitude = 40.7, -74.0
+	loc := time.FixedZone("EST", -5*3600)
+
+	for day := 1; day <= 31; day++ {
+		date := time.Date(2026, time.March, day, 12, 0, 0, 0, loc)
+		transit, err := MoonTransitOn(date, latitude, longitude)
+		// the transit is some 50 minutes later each day and skips a date
+		if day == 3 {
+			if err != ErrNoMoonTransit {
+				t.Errorf("3 March: transit %s, %v, want %v", transit, err, ErrNoMoonTransit)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%d March: %v", day, err)
+			continue
+		}
+		if transit.Day() != day || transit.Location() != loc {
+			t.Errorf("%d March: transit %s not on the civil date", day, transit)
+		}
+
+		// due south, at the highest point of the day's arc
+		p, _ := MoonPosition(transit, latitude, longitude)
+		if !near(p.Azimuth, 180, 0.01) {
+			t.Errorf("%d March: azimuth %v° at transit", day, p.Azimuth)
+		}
+		for _, d := range []time.Duration{-10 * time.Minute, 10 * time.Minute} {
+			if q, _ := MoonPosition(transit.Add(d), latitude, longitude); q.Altitude > p.Altitude {
+				t.Errorf("%d March: the Moon is higher %s from transit", day, d)
+			}
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package sunevent
+
+import (
+	"time"
+)
+
+// MoonDay holds the lunar events and the state of the Moon on one civil
+// date, the lunar counterpart of SunDay. Events that do not happen that
+// day are zero.
+type MoonDay struct {
+	Date time.Time
+
+	Moonrise time.Time
+	Moonset  time.Time
+	Transit  time.Time
+
+	// Phase and PhaseName are the phase of the Moon at noon, as returned
+	// by MoonPhase, and Illumination the illuminated fraction of its disk.
+	Phase        float64
+	PhaseName    MoonPhaseName
+	Illumination float64
+
+	// Distance is the distance between the centres of the Earth and the
+	// Moon at noon in km, as returned by MoonDistance.
+	Distance float64
+}
+
+// MoonDayOn returns the lunar events on the civil date of date, in the
+// time zone of date, with the phase, illumination and distance of the Moon
+// at noon. It never fails: events that do not happen that day, about once
+// a month for each, are left zero.
+func MoonDayOn(date time.Time, latitude, longitude float64, opts ...Option) MoonDay {
+	o := newOptions(opts)
+	noon := o.wallTime(date.Year(), date.Month(), date.Day(), 12*time.Hour, date.Location())
+
+	d := MoonDay{Date: o.midnight(date)}
+	d.Moonrise, _ = MoonRiseOn(date, latitude, longitude, opts...)
+	d.Moonset, _ = MoonSetOn(date, latitude, longitude, opts...)
+	d.Transit, _ = MoonTransitOn(date, latitude, longitude, opts...)
+	d.Phase, d.PhaseName = MoonPhase(noon)
+	d.Illumination = MoonIllumination(noon)
+	d.Distance = MoonDistance(noon)
+	return d
+}
+
+// LunarMonth returns the lunar events of every date of the month in loc,
+// in order, for printing a monthly page of an almanac.
+func LunarMonth(year int, month time.Month, latitude, longitude float64, loc *time.Location, opts ...Option) []MoonDay {
+	var days []MoonDay
+	for date := time.Date(year, month, 1, 12, 0, 0, 0, loc); date.Month() == month; date = time.Date(year, month, date.Day()+1, 12, 0, 0, 0, loc) {
+		days = append(days, MoonDayOn(date, latitude, longitude, opts...))
+	}
+	return days
+}
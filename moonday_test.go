@@ -0,0 +1,60 @@
+package sunevent
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLunarMonth(t *testing.T) {
+	loc := time.FixedZone("EST", -5*3600)
+	days := LunarMonth(2026, time.March, 40.7, -74, loc)
+	if len(days) != 31 {
+		t.Fatalf("%d days in March", len(days))
+	}
+	for i, d := range days {
+		if want := time.Date(2026, time.March, i+1, 0, 0, 0, 0, loc); !d.Date.Equal(want) {
+			t.Errorf("day %d: Date %s, want %s", i, d.Date, want)
+		}
+	}
+
+	// the dates skipped by moonrise, moonset and the transit are left zero
+	if !days[8].Moonrise.IsZero() || days[8].Moonset.IsZero() {
+		t.Errorf("9 March: moonrise %s, moonset %s", days[8].Moonrise, days[8].Moonset)
+	}
+	if !days[22].Moonset.IsZero() || days[22].Moonrise.IsZero() {
+		t.Errorf("23 March: moonrise %s, moonset %s", days[22].Moonrise, days[22].Moonset)
+	}
+	if !days[2].Transit.IsZero() {
+		t.Errorf("3 March: transit %s", days[2].Transit)
+	}
+
+	if n := len(LunarMonth(2028, time.February, 40.7, -74, loc)); n != 29 {
+		t.Errorf("%d days in February 2028", n)
+	}
+}
+
+func TestMoonDayOn(t *testing.T) {
+	loc := time.FixedZone("EST", -5*3600)
+	date := time.Date(2026, time.March, 12, 18, 30, 0, 0, loc)
+	d := MoonDayOn(date, 40.7, -74)
+
+	rise, _ := MoonRiseOn(date, 40.7, -74)
+	set, _ := MoonSetOn(date, 40.7, -74)
+	transit, _ := MoonTransitOn(date, 40.7, -74)
+	if !d.Moonrise.Equal(rise) || !d.Moonset.Equal(set) || !d.Transit.Equal(transit) {
+		t.Errorf("MoonDayOn events %s, %s, %s, want %s, %s, %s", d.Moonrise, d.Moonset, d.Transit, rise, set, transit)
+	}
+
+	// the state of the Moon is that at noon, whatever the time of date
+	noon := time.Date(2026, time.March, 12, 12, 0, 0, 0, loc)
+	phase, name := MoonPhase(noon)
+	if d.Phase != phase || d.PhaseName != name || d.PhaseName != LastQuarter {
+		t.Errorf("phase %v, %v, want %v, %v", d.Phase, d.PhaseName, phase, LastQuarter)
+	}
+	if d.Illumination != MoonIllumination(noon) || d.Distance != MoonDistance(noon) {
+		t.Errorf("illumination %v, distance %v km", d.Illumination, d.Distance)
+	}
+	if want := time.Date(2026, time.March, 12, 0, 0, 0, 0, loc); !d.Date.Equal(want) {
+		t.Errorf("Date %s, want %s", d.Date, want)
+	}
+}
@@ -0,0 +1,190 @@
+package sunevent
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/cfw011566/sunevent/internal/meeus"
+)
+
+// synodicMonth is the mean length of a lunation in days.
+const synodicMonth = 29.530588853
+
+// MoonPhaseName names the phase of the Moon.
+type MoonPhaseName int
+
+const (
+	NewMoon MoonPhaseName = iota
+	WaxingCrescent
+	FirstQuarter
+	WaxingGibbous
+	FullMoon
+	WaningGibbous
+	LastQuarter
+	WaningCrescent
+)
+
+var moonPhaseNames = [...]string{
+	NewMoon:        "new_moon",
+	WaxingCrescent: "waxing_crescent",
+	FirstQuarter:   "first_quarter",
+	WaxingGibbous:  "waxing_gibbous",
+	FullMoon:       "full_moon",
+	WaningGibbous:  "waning_gibbous",
+	LastQuarter:    "last_quarter",
+	WaningCrescent: "waning_crescent",
+}
+
+func (n MoonPhaseName) String() string {
+	if n < 0 || int(n) >= len(moonPhaseNames) {
+		return "unknown"
+	}
+	return moonPhaseNames[n]
+}
+
+// MoonPhase returns the phase of the Moon at the instant t as the fraction
+// of the lunation elapsed, in [0, 1): 0 at new moon, 0.25 at first
+// quarter, 0.5 at full moon and 0.75 at last quarter. The fraction is the
+// elongation of the Moon from the Sun in ecliptic longitude divided by
+// 360°, so it tracks the true Moon rather than a mean lunation.
+//
+// The name covers an eighth of the lunation centred on each principal
+// phase: FullMoon from 0.4375 to 0.5625, and so on.
+func MoonPhase(t time.Time) (phase float64, name MoonPhaseName) {
+	phase = moonElongation(Options{}.julianCentury(t)) / 360.0
+	return phase, MoonPhaseName(int(math.Floor(phase*8.0+0.5)) % 8)
+}
+
+// MoonAge returns the age of the Moon at the instant t: the days elapsed
+// since the last new moon, from 0 to about 29.5. Lunations vary in length
+// by over half a day, so the age is not proportional to the phase from
+// MoonPhase.
+func MoonAge(t time.Time) float64 {
+	next := nextMoonPhase(t, NewMoon)
+	// lunations last from 29.3 to 29.8 days
+	last := nextMoonPhase(next.Add(-31*24*time.Hour), NewMoon)
+	return t.Sub(last).Hours() / 24.0
+}
+
+// moonElongation returns the difference of the apparent longitudes of the
+// Moon and the Sun in degrees [0, 360) at T, Julian centuries in
+// terrestrial time.
+func moonElongation(T float64) float64 {
+	lambda, _, _ := moonEcliptic(T)
+	dpsi, _ := meeus.Nutation(T)
+	L, _, _, _ := sunCoordinates(T)
+	return normalizeRange(lambda+dpsi-L, 360)
+}
+
+// MoonIllumination returns the illuminated fraction of the Moon's disk at
+// the instant t, from 0 at new moon to 1 at full moon (Meeus, chapter
+// 48). Night photography and wildlife surveys can combine it with
+// MoonRiseOn and MoonSetOn to judge how dark a night is.
+//
+// k = (1 + cos(i)) / 2, with i the phase angle seen from the Moon
+func MoonIllumination(t time.Time) float64 {
+	T := Options{}.julianCentury(t)
+	lambda, beta, distance := moonEcliptic(T)
+	L, _, _, _ := sunCoordinates(T)
+
+	// geocentric elongation and phase angle
+	cosPsi := degreeCos(beta) * degreeCos(lambda-L)
+	psi := degreeAcos(cosPsi)
+	R := sunDistance(T)
+	i := radianToDegree(math.Atan2(R*degreeSin(psi), distance-R*cosPsi))
+
+	return (1 + degreeCos(i)) / 2
+}
+
+// sunDistance returns the distance of the Sun in km at T.
+// R = 1.00014 - 0.01671 * cos(M) - 0.00014 * cos(2 * M) au
+func sunDistance(T float64) float64 {
+	M := meanAnomaly(T)
+	return (1.00014 - 0.01671*degreeCos(M) - 0.00014*degreeCos(2*M)) * 149597870.7
+}
+
+// MoonPhaseEvent is the moment of a principal phase of the Moon: NewMoon,
+// FirstQuarter, FullMoon or LastQuarter.
+type MoonPhaseEvent struct {
+	Phase MoonPhaseName
+	Time  time.Time
+}
+
+// NextNewMoon returns the first new moon after the instant after, in the
+// time zone of after. New moon is when the Moon and the Sun have the same
+// ecliptic longitude; the result is accurate to about a minute.
+func NextNewMoon(after time.Time) time.Time {
+	return nextMoonPhase(after, NewMoon)
+}
+
+// NextFullMoon returns the first full moon after the instant after, in the
+// time zone of after.
+func NextFullMoon(after time.Time) time.Time {
+	return nextMoonPhase(after, FullMoon)
+}
+
+// NextMoonPhaseEvent returns the first principal phase of the Moon after
+// the instant after, whichever of new moon, first quarter, full moon and
+// last quarter it is, in the time zone of after.
+func NextMoonPhaseEvent(after time.Time) MoonPhaseEvent {
+	var next MoonPhaseEvent
+	for _, phase := range []MoonPhaseName{NewMoon, FirstQuarter, FullMoon, LastQuarter} {
+		if t := nextMoonPhase(after, phase); next.Time.IsZero() || t.Before(next.Time) {
+			next = MoonPhaseEvent{Phase: phase, Time: t}
+		}
+	}
+	return next
+}
+
+// MoonPhases returns the new moons, first quarters, full moons and last
+// quarters of year in UTC, in chronological order, as printed in
+// calendars.
+func MoonPhases(year int) []MoonPhaseEvent {
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(1, 0, 0)
+
+	var events []MoonPhaseEvent
+	for _, phase := range []MoonPhaseName{NewMoon, FirstQuarter, FullMoon, LastQuarter} {
+		// the first phase of the year may fall right at midnight
+		for t := nextMoonPhase(start.Add(-time.Second), phase); t.Before(end); t = nextMoonPhase(t, phase) {
+			events = append(events, MoonPhaseEvent{Phase: phase, Time: t})
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Time.Before(events[j].Time)
+	})
+	return events
+}
+
+// nextMoonPhase returns the first instant after the instant after when
+// the elongation of the Moon is that of the named phase, 45° times its
+// value. It starts from the mean motion of the Moon and refines the time
+// with Newton's method.
+func nextMoonPhase(after time.Time, phase MoonPhaseName) time.Time {
+	target := float64(phase) * 45.0
+	o := Options{}
+	elongation := func(t time.Time) float64 {
+		return moonElongation(o.julianCentury(t))
+	}
+	days := func(degrees float64) time.Duration {
+		return time.Duration(degrees / 360.0 * synodicMonth * float64(24*time.Hour))
+	}
+
+	t := after.Add(days(normalizeRange(target-elongation(after), 360)))
+	for i := 0; i < 10; i++ {
+		step := days(normalizeRange(target-elongation(t)+180.0, 360) - 180.0)
+		t = t.Add(step)
+		if step < time.Second && step > -time.Second {
+			break
+		}
+	}
+
+	if t.Sub(after) < time.Second {
+		// the phase at after, whose time may have been rounded, or just
+		// before it; take the next lunation
+		return nextMoonPhase(t.Add(24*time.Hour), phase)
+	}
+	return t.Round(time.Second).In(after.Location())
+}
@@ -0,0 +1,147 @@
+package sunevent
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMoonPhase(t *testing.T) {
+	tests := []struct {
+		at    time.Time
+		phase float64
+		name  MoonPhaseName
+	}{
+		// the principal phases of January 2026 at noon UTC, hours away
+		// from the exact moments
+		{time.Date(2026, time.January, 3, 12, 0, 0, 0, time.UTC), 0.503, FullMoon},
+		{time.Date(2026, time.January, 10, 12, 0, 0, 0, time.UTC), 0.745, LastQuarter},
+		{time.Date(2026, time.January, 18, 12, 0, 0, 0, time.UTC), 0.990, NewMoon},
+		{time.Date(2026, time.January, 26, 12, 0, 0, 0, time.UTC), 0.261, FirstQuarter},
+		{time.Date(2026, time.January, 7, 0, 0, 0, 0, time.UTC), 0.631, WaningGibbous},
+		{time.Date(2026, time.January, 14, 0, 0, 0, 0, time.UTC), 0.851, WaningCrescent},
+		{time.Date(2026, time.January, 22, 0, 0, 0, 0, time.UTC), 0.11, WaxingCrescent},
+		{time.Date(2026, time.January, 30, 0, 0, 0, 0, time.UTC), 0.39, WaxingGibbous},
+	}
+	for _, tt := range tests {
+		phase, name := MoonPhase(tt.at)
+		if !near(phase, tt.phase, 0.01) || name != tt.name {
+			t.Errorf("MoonPhase(%s) = %.3f, %v, want %.3f, %v", tt.at, phase, name, tt.phase, tt.name)
+		}
+	}
+
+	if s := WaxingGibbous.String(); s != "waxing_gibbous" {
+		t.Errorf("WaxingGibbous.String() = %q", s)
+	}
+	if s := MoonPhaseName(8).String(); s != "unknown" {
+		t.Errorf("MoonPhaseName(8).String() = %q", s)
+	}
+}
+
+func TestMoonIllumination(t *testing.T) {
+	// Meeus, Astronomical Algorithms, example 48.a: 1992 April 12, 0h TD
+	at := time.Date(1992, time.April, 12, 0, 0, 0, 0, time.UTC)
+	if k := MoonIllumination(at); !near(k, 0.6786, 0.0005) {
+		t.Errorf("MoonIllumination(%s) = %v, want 0.6786", at, k)
+	}
+
+	tests := []struct {
+		at       time.Time
+		min, max float64
+	}{
+		{time.Date(2026, time.January, 3, 10, 3, 0, 0, time.UTC), 0.99, 1},     // full moon
+		{time.Date(2026, time.January, 18, 19, 52, 0, 0, time.UTC), 0, 0.01},   // new moon
+		{time.Date(2026, time.January, 26, 4, 48, 0, 0, time.UTC), 0.45, 0.55}, // first quarter
+	}
+	for _, tt := range tests {
+		if k := MoonIllumination(tt.at); k < tt.min || k > tt.max {
+			t.Errorf("MoonIllumination(%s) = %v, want %v to %v", tt.at, k, tt.min, tt.max)
+		}
+	}
+}
+
+func TestMoonPhases(t *testing.T) {
+	phases := MoonPhases(2026)
+	if len(phases) != 50 {
+		t.Errorf("%d principal phases in 2026, want 50", len(phases))
+	}
+	for i, p := range phases {
+		if p.Time.Year() != 2026 || (i > 0 && !p.Time.After(phases[i-1].Time)) {
+			t.Errorf("phase %d: %v at %s out of order", i, p.Phase, p.Time)
+		}
+	}
+
+	// the published phases of early 2026, in UTC
+	want := []MoonPhaseEvent{
+		{FullMoon, time.Date(2026, time.January, 3, 10, 3, 0, 0, time.UTC)},
+		{LastQuarter, time.Date(2026, time.January, 10, 15, 48, 0, 0, time.UTC)},
+		{NewMoon, time.Date(2026, time.January, 18, 19, 52, 0, 0, time.UTC)},
+		{FirstQuarter, time.Date(2026, time.January, 26, 4, 47, 0, 0, time.UTC)},
+		{FullMoon, time.Date(2026, time.February, 1, 22, 9, 0, 0, time.UTC)},
+		{LastQuarter, time.Date(2026, time.February, 9, 12, 43, 0, 0, time.UTC)},
+		{NewMoon, time.Date(2026, time.February, 17, 12, 1, 0, 0, time.UTC)},
+		{FirstQuarter, time.Date(2026, time.February, 24, 12, 27, 0, 0, time.UTC)},
+		{FullMoon, time.Date(2026, time.March, 3, 11, 38, 0, 0, time.UTC)},
+	}
+	for i, w := range want {
+		if p := phases[i]; p.Phase != w.Phase || absDuration(p.Time.Sub(w.Time)) > 2*time.Minute {
+			t.Errorf("phase %d: %v at %s, want %v at %s", i, p.Phase, p.Time, w.Phase, w.Time)
+		}
+	}
+
+	// Meeus, Astronomical Algorithms, example 49.a: the new moon of
+	// 1977 February 18 at 3:37:40 TD, some 48 s after UT
+	taipei := time.FixedZone("CST", 8*3600)
+	next := NextNewMoon(time.Date(1977, time.February, 1, 0, 0, 0, 0, taipei))
+	if w := time.Date(1977, time.February, 18, 3, 36, 52, 0, time.UTC); absDuration(next.Sub(w)) > time.Minute || next.Location() != taipei {
+		t.Errorf("NextNewMoon = %s, want %s", next, w)
+	}
+
+	// the phase at the start instant is not next
+	full := phases[0].Time
+	if next := NextFullMoon(full); !next.Equal(phases[4].Time) {
+		t.Errorf("NextFullMoon(%s) = %s, want %s", full, next, phases[4].Time)
+	}
+}
+
+func TestMoonAge(t *testing.T) {
+	// new moons at 2026 January 18 19:52 and February 17 12:01 UTC
+	newMoon := time.Date(2026, time.January, 18, 19, 52, 22, 0, time.UTC)
+	tests := []struct {
+		at   time.Time
+		want float64
+	}{
+		{newMoon.Add(time.Minute), 0},
+		{newMoon.Add(7 * 24 * time.Hour), 7},
+		// lunations vary around the mean of 29.53 days: the one ending on
+		// 18 January lasts 29.76 days, the next 29.67
+		{newMoon.Add(-time.Minute), 29.76},
+		{time.Date(2026, time.February, 17, 12, 0, 0, 0, time.UTC), 29.67},
+	}
+	for _, tt := range tests {
+		if age := MoonAge(tt.at); !near(age, tt.want, 0.01) {
+			t.Errorf("MoonAge(%s) = %v, want %v", tt.at, age, tt.want)
+		}
+	}
+}
+
+func TestNextMoonPhaseEvent(t *testing.T) {
+	// the times are refined to a second from different starting points
+	same := func(a, b MoonPhaseEvent) bool {
+		return a.Phase == b.Phase && absDuration(a.Time.Sub(b.Time)) <= time.Second
+	}
+	phases := MoonPhases(2026)
+	for i, p := range phases[:len(phases)-1] {
+		// from just before and at the phase itself
+		if next := NextMoonPhaseEvent(p.Time.Add(-time.Minute)); !same(next, p) {
+			t.Errorf("before %v at %s: %v at %s", p.Phase, p.Time, next.Phase, next.Time)
+		}
+		if next := NextMoonPhaseEvent(p.Time); !same(next, phases[i+1]) {
+			t.Errorf("at %v at %s: %v at %s, want %v", p.Phase, p.Time, next.Phase, next.Time, phases[i+1].Phase)
+		}
+	}
+
+	taipei := time.FixedZone("CST", 8*3600)
+	if next := NextMoonPhaseEvent(time.Date(2026, time.January, 4, 0, 0, 0, 0, taipei)); next.Phase != LastQuarter || next.Time.Location() != taipei {
+		t.Errorf("after 4 January: %v at %s, want the last quarter in Taipei", next.Phase, next.Time)
+	}
+}
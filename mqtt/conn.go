@@ -0,0 +1,264 @@
+package mqtt
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// The subset of MQTT 3.1.1 a publisher needs: CONNECT with a last will,
+// PUBLISH at QoS 0, keep-alive pings and DISCONNECT.
+
+// Packet types, in the high nibble of the first byte.
+const (
+	packetConnect    = 1
+	packetConnack    = 2
+	packetPublish    = 3
+	packetPingreq    = 12
+	packetPingresp   = 13
+	packetDisconnect = 14
+)
+
+// writeTimeout bounds each write to the broker.
+const writeTimeout = 10 * time.Second
+
+// conn is a connection to a broker.
+type conn struct {
+	nc net.Conn
+	r  *bufio.Reader
+
+	// failed receives the error that ended the reading of the connection
+	failed chan error
+}
+
+// will is the message the broker publishes when the connection is lost.
+type will struct {
+	topic, payload string
+	retain         bool
+}
+
+// connectOptions are the fields of the CONNECT packet.
+type connectOptions struct {
+	clientID           string
+	username, password string
+	keepAlive          time.Duration
+	will               *will
+}
+
+// dial connects to the broker at addr, a URL such as tcp://host:1883 or
+// tls://host:8883, or a host and port for plain TCP.
+func dial(ctx context.Context, addr string, tlsConfig *tls.Config, opts connectOptions) (*conn, error) {
+	network, host, secure, err := parseBroker(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	var d net.Dialer
+	nc, err := d.DialContext(ctx, network, host)
+	if err != nil {
+		return nil, err
+	}
+	if secure {
+		cfg := &tls.Config{}
+		if tlsConfig != nil {
+			cfg = tlsConfig.Clone()
+		}
+		if cfg.ServerName == "" {
+			cfg.ServerName, _, _ = net.SplitHostPort(host)
+		}
+		tc := tls.Client(nc, cfg)
+		if err := tc.Handshake(); err != nil {
+			nc.Close()
+			return nil, err
+		}
+		nc = tc
+	}
+
+	c := &conn{nc: nc, r: bufio.NewReader(nc), failed: make(chan error, 1)}
+	if err := c.connect(opts); err != nil {
+		nc.Close()
+		return nil, err
+	}
+	go c.read()
+	return c, nil
+}
+
+// parseBroker returns the network, address and whether TLS is used for a
+// broker address.
+func parseBroker(addr string) (network, host string, secure bool, err error) {
+	if !strings.Contains(addr, "://") {
+		addr = "tcp://" + addr
+	}
+	u, err := url.Parse(addr)
+	if err != nil {
+		return "", "", false, fmt.Errorf("sunevent/mqtt: invalid broker %q", addr)
+	}
+	port := "1883"
+	switch u.Scheme {
+	case "tcp", "mqtt":
+	case "tls", "ssl", "mqtts":
+		secure, port = true, "8883"
+	default:
+		return "", "", false, fmt.Errorf("sunevent/mqtt: unsupported scheme %q", u.Scheme)
+	}
+	host = u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), port)
+	}
+	return "tcp", host, secure, nil
+}
+
+// connect sends CONNECT and waits for CONNACK.
+func (c *conn) connect(opts connectOptions) error {
+	var flags byte = 0x02 // clean session
+	var b []byte
+	b = appendString(b, "MQTT")
+	b = append(b, 4) // protocol level 3.1.1
+	flagsAt := len(b)
+	b = append(b, 0)
+	keepAlive := int(opts.keepAlive / time.Second)
+	b = append(b, byte(keepAlive>>8), byte(keepAlive))
+
+	b = appendString(b, opts.clientID)
+	if w := opts.will; w != nil {
+		flags |= 0x04
+		if w.retain {
+			flags |= 0x20
+		}
+		b = appendString(b, w.topic)
+		b = appendString(b, w.payload)
+	}
+	if opts.username != "" {
+		flags |= 0x80
+		b = appendString(b, opts.username)
+		if opts.password != "" {
+			flags |= 0x40
+			b = appendString(b, opts.password)
+		}
+	}
+	b[flagsAt] = flags
+
+	if err := c.write(packetConnect<<4, b); err != nil {
+		return err
+	}
+
+	c.nc.SetReadDeadline(time.Now().Add(writeTimeout))
+	defer c.nc.SetReadDeadline(time.Time{})
+	typ, body, err := readPacket(c.r)
+	if err != nil {
+		return err
+	}
+	if typ != packetConnack || len(body) != 2 {
+		return errors.New("sunevent/mqtt: expected CONNACK")
+	}
+	if code := body[1]; code != 0 {
+		return fmt.Errorf("sunevent/mqtt: connection refused: %s", connackReason(code))
+	}
+	return nil
+}
+
+func connackReason(code byte) string {
+	switch code {
+	case 1:
+		return "unacceptable protocol version"
+	case 2:
+		return "identifier rejected"
+	case 3:
+		return "server unavailable"
+	case 4:
+		return "bad user name or password"
+	case 5:
+		return "not authorized"
+	}
+	return fmt.Sprintf("code %d", code)
+}
+
+// publish sends a message at QoS 0.
+func (c *conn) publish(topic string, payload []byte, retain bool) error {
+	var first byte = packetPublish << 4
+	if retain {
+		first |= 0x01
+	}
+	return c.write(first, append(appendString(nil, topic), payload...))
+}
+
+func (c *conn) ping() error {
+	return c.write(packetPingreq<<4, nil)
+}
+
+// close sends DISCONNECT, so that the broker does not publish the will,
+// and closes the connection.
+func (c *conn) close() error {
+	c.write(packetDisconnect<<4, nil)
+	return c.nc.Close()
+}
+
+// write sends a packet with the first byte first and the given body.
+func (c *conn) write(first byte, body []byte) error {
+	b := []byte{first}
+	for n := len(body); ; {
+		d := byte(n % 128)
+		n /= 128
+		if n > 0 {
+			d |= 0x80
+		}
+		b = append(b, d)
+		if n == 0 {
+			break
+		}
+	}
+	c.nc.SetWriteDeadline(time.Now().Add(writeTimeout))
+	_, err := c.nc.Write(append(b, body...))
+	return err
+}
+
+// read consumes the packets of the broker, which are only ping responses
+// for a publisher, until the connection fails.
+func (c *conn) read() {
+	for {
+		if _, _, err := readPacket(c.r); err != nil {
+			c.failed <- err
+			return
+		}
+	}
+}
+
+// readPacket reads a packet and returns its type and body.
+func readPacket(r *bufio.Reader) (byte, []byte, error) {
+	first, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	n, shift := 0, uint(0)
+	for {
+		d, err := r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		n |= int(d&0x7f) << shift
+		if d&0x80 == 0 {
+			break
+		}
+		if shift += 7; shift > 21 {
+			return 0, nil, errors.New("sunevent/mqtt: malformed packet length")
+		}
+	}
+	body := make([]byte, n)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, nil, err
+	}
+	return first >> 4, body, nil
+}
+
+// appendString appends a length-prefixed UTF-8 string.
+func appendString(b []byte, s string) []byte {
+	b = append(b, byte(len(s)>>8), byte(len(s)))
+	return append(b, s...)
+}
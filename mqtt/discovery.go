@@ -0,0 +1,87 @@
+package mqtt
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/cfw011566/sunevent"
+)
+
+// sensor is the discovery configuration of a Home Assistant sensor.
+type sensor struct {
+	configTopic string
+	config      []byte
+}
+
+type discoveryConfig struct {
+	Name              string          `json:"name"`
+	UniqueID          string          `json:"unique_id"`
+	StateTopic        string          `json:"state_topic"`
+	ValueTemplate     string          `json:"value_template,omitempty"`
+	DeviceClass       string          `json:"device_class,omitempty"`
+	UnitOfMeasurement string          `json:"unit_of_measurement,omitempty"`
+	StateClass        string          `json:"state_class,omitempty"`
+	Options           []string        `json:"options,omitempty"`
+	AvailabilityTopic string          `json:"availability_topic"`
+	Device            discoveryDevice `json:"device"`
+}
+
+type discoveryDevice struct {
+	Identifiers  []string `json:"identifiers"`
+	Name         string   `json:"name"`
+	Manufacturer string   `json:"manufacturer"`
+}
+
+// discoveryEvents are the events offered as sensors of their next time.
+var discoveryEvents = []sunevent.EventType{
+	sunevent.CivilDawn,
+	sunevent.Sunrise,
+	sunevent.SolarNoon,
+	sunevent.Sunset,
+	sunevent.CivilDusk,
+}
+
+// unsafeID matches the characters not allowed in discovery identifiers.
+var unsafeID = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// sensors returns the discovery configurations of the sensors.
+func (p *Publisher) sensors() []sensor {
+	node := "sunevent_" + unsafeID.ReplaceAllString(p.name, "_")
+	device := discoveryDevice{
+		Identifiers:  []string{node},
+		Name:         "Sun " + p.name,
+		Manufacturer: "sunevent",
+	}
+	phases := []string{}
+	for k := sunevent.PhaseNight; k <= sunevent.PhaseDay; k++ {
+		phases = append(phases, k.String())
+	}
+
+	configs := []discoveryConfig{
+		{Name: "Phase", UniqueID: node + "_phase", StateTopic: p.topic("phase"), DeviceClass: "enum", Options: phases},
+		{Name: "Azimuth", UniqueID: node + "_azimuth", StateTopic: p.topic("position"), ValueTemplate: "{{ value_json.azimuth }}", UnitOfMeasurement: "°", StateClass: "measurement"},
+		{Name: "Altitude", UniqueID: node + "_altitude", StateTopic: p.topic("position"), ValueTemplate: "{{ value_json.altitude }}", UnitOfMeasurement: "°", StateClass: "measurement"},
+	}
+	for _, e := range discoveryEvents {
+		configs = append(configs, discoveryConfig{
+			Name:        "Next " + strings.Replace(e.String(), "_", " ", -1),
+			UniqueID:    node + "_next_" + e.String(),
+			StateTopic:  p.topic("next/" + e.String()),
+			DeviceClass: "timestamp",
+		})
+	}
+
+	var sensors []sensor
+	for _, cfg := range configs {
+		cfg.AvailabilityTopic = p.topic("availability")
+		cfg.Device = device
+		payload, _ := json.Marshal(cfg)
+		object := strings.TrimPrefix(cfg.UniqueID, node+"_")
+		sensors = append(sensors, sensor{
+			configTopic: p.discovery + "/sensor/" + node + "/" + object + "/config",
+			config:      payload,
+		})
+	}
+	return sensors
+}
@@ -0,0 +1,250 @@
+// Package mqtt publishes the solar events and the position of the Sun at
+// a location to an MQTT broker, as a bridge to home automation systems:
+//
+//	p := mqtt.NewPublisher("tcp://broker:1883", "home", 22.63, 120.30,
+//		mqtt.WithDiscovery("homeassistant"))
+//	log.Fatal(p.Run(ctx))
+//
+// Under the topic prefix, by default sunevent/<name>, the publisher sends
+//
+//	<prefix>/<event>          the time of each event as it happens, as in sunevent/home/sunset
+//	<prefix>/next/<event>     the time of the next occurrence of each event, retained
+//	<prefix>/phase            the phase of daylight, retained
+//	<prefix>/position         the azimuth and altitude as JSON, retained
+//	<prefix>/availability     online, or offline when the publisher is gone, retained
+//
+// Times are RFC 3339 and "None" when an event does not happen within two
+// days. With WithDiscovery, Home Assistant finds the sensors by MQTT
+// discovery. The package implements the small part of MQTT 3.1.1 it
+// needs and publishes at QoS 0.
+package mqtt
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cfw011566/sunevent"
+)
+
+// Publisher publishes the sun at one location to a broker.
+type Publisher struct {
+	broker              string
+	name                string
+	latitude, longitude float64
+
+	clientID           string
+	username, password string
+	tlsConfig          *tls.Config
+	prefix             string
+	discovery          string
+	interval           time.Duration
+	keepAlive          time.Duration
+}
+
+// Option configures a Publisher.
+type Option func(*Publisher)
+
+// WithCredentials logs in to the broker with a user name and password.
+func WithCredentials(username, password string) Option {
+	return func(p *Publisher) {
+		p.username, p.password = username, password
+	}
+}
+
+// WithClientID sets the client identifier, by default sunevent-<name>-
+// followed by the process ID.
+func WithClientID(id string) Option {
+	return func(p *Publisher) {
+		p.clientID = id
+	}
+}
+
+// WithTLSConfig sets the TLS configuration of tls:// brokers.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(p *Publisher) {
+		p.tlsConfig = cfg
+	}
+}
+
+// WithTopicPrefix sets the prefix of the topics, by default
+// sunevent/<name>.
+func WithTopicPrefix(prefix string) Option {
+	return func(p *Publisher) {
+		p.prefix = strings.TrimSuffix(prefix, "/")
+	}
+}
+
+// WithDiscovery publishes Home Assistant MQTT discovery configurations
+// under the discovery prefix, usually "homeassistant".
+func WithDiscovery(prefix string) Option {
+	return func(p *Publisher) {
+		p.discovery = strings.TrimSuffix(prefix, "/")
+	}
+}
+
+// WithPositionInterval sets how often the position and phase are
+// published, by default every minute.
+func WithPositionInterval(d time.Duration) Option {
+	return func(p *Publisher) {
+		if d > 0 {
+			p.interval = d
+		}
+	}
+}
+
+// NewPublisher returns a publisher for the location called name, which
+// names its topics, to the broker at broker: a URL such as
+// tcp://host:1883 or tls://host:8883, or a host and port.
+func NewPublisher(broker, name string, latitude, longitude float64, opts ...Option) *Publisher {
+	p := &Publisher{
+		broker:    broker,
+		name:      name,
+		latitude:  latitude,
+		longitude: longitude,
+		clientID:  fmt.Sprintf("sunevent-%s-%d", name, os.Getpid()),
+		prefix:    "sunevent/" + name,
+		interval:  time.Minute,
+		keepAlive: time.Minute,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// nextHorizon is how far ahead the next occurrences are looked for.
+const nextHorizon = 48 * time.Hour
+
+// Run connects to the broker and publishes until ctx is done, when it
+// disconnects and returns nil, or until the connection fails, when it
+// returns the error; call it again to reconnect.
+func (p *Publisher) Run(ctx context.Context) error {
+	c, err := dial(ctx, p.broker, p.tlsConfig, connectOptions{
+		clientID:  p.clientID,
+		username:  p.username,
+		password:  p.password,
+		keepAlive: p.keepAlive,
+		will:      &will{topic: p.topic("availability"), payload: "offline", retain: true},
+	})
+	if err != nil {
+		return err
+	}
+	defer c.close()
+
+	if p.discovery != "" {
+		for _, s := range p.sensors() {
+			if err := c.publish(s.configTopic, s.config, true); err != nil {
+				return err
+			}
+		}
+	}
+	if err := c.publish(p.topic("availability"), []byte("online"), true); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	events := sunevent.Subscribe(ctx, p.latitude, p.longitude)
+
+	position := time.NewTicker(p.interval)
+	defer position.Stop()
+	ping := time.NewTicker(p.keepAlive / 2)
+	defer ping.Stop()
+
+	if err := p.publishNext(c, time.Now()); err != nil {
+		return err
+	}
+	if err := p.publishPosition(c, time.Now()); err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			c.publish(p.topic("availability"), []byte("offline"), true)
+			return nil
+		case err := <-c.failed:
+			return err
+		case o, ok := <-events:
+			if !ok {
+				events = nil
+				continue
+			}
+			if err := c.publish(p.topic(o.Type.String()), []byte(o.Time.Format(time.RFC3339)), false); err != nil {
+				return err
+			}
+			if err := p.publishNext(c, o.Time); err != nil {
+				return err
+			}
+			if err := p.publishPosition(c, time.Now()); err != nil {
+				return err
+			}
+		case now := <-position.C:
+			if err := p.publishPosition(c, now); err != nil {
+				return err
+			}
+		case <-ping.C:
+			if err := c.ping(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (p *Publisher) topic(name string) string {
+	return p.prefix + "/" + name
+}
+
+// publishNext publishes the next occurrence of every event after now.
+func (p *Publisher) publishNext(c *conn, now time.Time) error {
+	next := make(map[sunevent.EventType]time.Time)
+	for _, e := range sunevent.EventsBetween(p.latitude, p.longitude, now, now.Add(nextHorizon)) {
+		if _, ok := next[e.Type]; !ok && e.Time.After(now) {
+			next[e.Type] = e.Time
+		}
+	}
+	for _, e := range sunevent.EventTypes {
+		payload := "None"
+		if t, ok := next[e]; ok {
+			payload = t.Format(time.RFC3339)
+		}
+		if err := c.publish(p.topic("next/"+e.String()), []byte(payload), true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type position struct {
+	Time     string  `json:"time"`
+	Azimuth  float64 `json:"azimuth"`
+	Altitude float64 `json:"altitude"`
+}
+
+// publishPosition publishes the position and phase at now.
+func (p *Publisher) publishPosition(c *conn, now time.Time) error {
+	pos := sunevent.SunPosition(now, p.latitude, p.longitude)
+	payload, err := json.Marshal(position{
+		Time:     now.Format(time.RFC3339),
+		Azimuth:  round(pos.Azimuth),
+		Altitude: round(pos.Altitude),
+	})
+	if err != nil {
+		return err
+	}
+	if err := c.publish(p.topic("position"), payload, true); err != nil {
+		return err
+	}
+	phase := sunevent.PhaseAt(now, p.latitude, p.longitude)
+	return c.publish(p.topic("phase"), []byte(phase.String()), true)
+}
+
+// round rounds an angle to two decimals, more than enough for automation.
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
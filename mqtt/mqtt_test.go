@@ -0,0 +1,156 @@
+package mqtt
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// message is a PUBLISH received by the broker of the tests.
+type message struct {
+	topic, payload string
+	retain         bool
+}
+
+// broker accepts one connection at a listener, answers its CONNECT with
+// the return code, and sends the CONNECT and then every message it
+// receives on the returned channel, which is closed when the client
+// disconnects.
+func broker(t *testing.T, code byte) (addr string, connect <-chan []byte, messages <-chan message) {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	connects := make(chan []byte, 1)
+	msgs := make(chan message, 100)
+	go func() {
+		defer close(msgs)
+		defer l.Close()
+		nc, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer nc.Close()
+		r := bufio.NewReader(nc)
+		typ, body, err := readPacket(r)
+		if err != nil || typ != packetConnect {
+			return
+		}
+		connects <- body
+		nc.Write([]byte{packetConnack << 4, 2, 0, code})
+		for {
+			first, err := r.Peek(1)
+			if err != nil {
+				return
+			}
+			retain := first[0]&0x01 != 0
+			typ, body, err := readPacket(r)
+			if err != nil || typ == packetDisconnect {
+				return
+			}
+			if typ == packetPublish {
+				n := int(body[0])<<8 | int(body[1])
+				msgs <- message{string(body[2 : 2+n]), string(body[2+n:]), retain}
+			}
+		}
+	}()
+	return l.Addr().String(), connects, msgs
+}
+
+func TestPublisher(t *testing.T) {
+	addr, connect, messages := broker(t, 0)
+	p := NewPublisher("tcp://"+addr, "home", 22.63, 120.30, WithDiscovery("homeassistant"), WithClientID("test"))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- p.Run(ctx) }()
+
+	select {
+	case body := <-connect:
+		for _, want := range []string{"MQTT", "test", "sunevent/home/availability", "offline"} {
+			if !strings.Contains(string(body), want) {
+				t.Errorf("CONNECT lacks %q", want)
+			}
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no CONNECT")
+	}
+
+	got := make(map[string]message)
+	var topics []string
+	for m := range messages {
+		if _, ok := got[m.topic]; !ok {
+			topics = append(topics, m.topic)
+		}
+		got[m.topic] = m
+		if m.topic == "sunevent/home/phase" {
+			cancel()
+		}
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	if topics[0] != "homeassistant/sensor/sunevent_home/phase/config" {
+		t.Errorf("first topic %s, want the discovery of the phase", topics[0])
+	}
+	var cfg discoveryConfig
+	if err := json.Unmarshal([]byte(got["homeassistant/sensor/sunevent_home/next_sunset/config"].payload), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.StateTopic != "sunevent/home/next/sunset" || cfg.DeviceClass != "timestamp" || cfg.AvailabilityTopic != "sunevent/home/availability" {
+		t.Errorf("discovery of the next sunset = %+v", cfg)
+	}
+
+	if m := got["sunevent/home/next/sunset"]; !m.retain {
+		t.Error("the next sunset is not retained")
+	} else if _, err := time.Parse(time.RFC3339, m.payload); err != nil {
+		t.Errorf("next sunset %q: %v", m.payload, err)
+	}
+	var pos position
+	if err := json.Unmarshal([]byte(got["sunevent/home/position"].payload), &pos); err != nil {
+		t.Fatal(err)
+	}
+	if pos.Azimuth < 0 || pos.Azimuth >= 360 || pos.Altitude < -90 || pos.Altitude > 90 {
+		t.Errorf("position %+v", pos)
+	}
+	// the last word is offline
+	if m := got["sunevent/home/availability"]; m.payload != "offline" || !m.retain {
+		t.Errorf("availability %+v after cancel", m)
+	}
+}
+
+func TestPublisherRefused(t *testing.T) {
+	addr, _, _ := broker(t, 5)
+	err := NewPublisher(addr, "home", 0, 0).Run(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "not authorized") {
+		t.Errorf("Run: %v, want not authorized", err)
+	}
+}
+
+func TestParseBroker(t *testing.T) {
+	tests := []struct {
+		addr, host string
+		secure     bool
+	}{
+		{"broker", "broker:1883", false},
+		{"broker:1884", "broker:1884", false},
+		{"tcp://broker", "broker:1883", false},
+		{"mqtts://broker", "broker:8883", true},
+		{"tls://[::1]:9000", "[::1]:9000", true},
+	}
+	for _, tt := range tests {
+		_, host, secure, err := parseBroker(tt.addr)
+		if err != nil || host != tt.host || secure != tt.secure {
+			t.Errorf("parseBroker(%q) = %s, %v, %v", tt.addr, host, secure, err)
+		}
+	}
+	if _, _, _, err := parseBroker("ws://broker"); err == nil {
+		t.Error("parseBroker accepted ws://")
+	}
+}
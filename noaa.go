@@ -0,0 +1,93 @@
+package sunevent
+
+import (
+	"math"
+	"time"
+)
+
+// noaaRiseSet computes the time on the civil date of today when the Sun
+// passes zenith, like sunRiseSet, with the equations of the NOAA solar
+// calculator evaluated in terrestrial time with the ΔT of o. The solar
+// parameters are refined at the estimated time of the event, which keeps
+// the error within a minute where the Sun crosses the horizon steeply.
+//
+// Reference
+// https://gml.noaa.gov/grad/solcalc/calcdetails.html
+func (o Options) noaaRiseSet(today time.Time, sunrise bool, latitude, longitude, zenith float64) (time.Time, error) {
+	guess := approximateTime(today, sunrise, longitude)
+
+	iterations := 3
+	if o.Precision == High {
+		iterations = 8
+	}
+
+	t := guess
+	for i := 0; i < iterations; i++ {
+		decl, eot := noaaSun(o.julianCentury(t))
+		cosH := noaaCosH(decl, latitude, zenith)
+		if cosH > 1.0 {
+			return time.Time{}, ErrSunNeverRises
+		}
+		if cosH < -1.0 {
+			return time.Time{}, ErrSunNeverSets
+		}
+
+		// the hour angle is negative in the morning
+		H := degreeAcos(cosH)
+		if sunrise {
+			H = -H
+		}
+
+		// solar noon is at 720 minutes UT less 4 minutes per degree east
+		// and the equation of time
+		minutes := 720.0 - 4.0*(longitude-H) - eot
+		next := nearestUT(guess, normalizeRange(minutes/60.0, 24.0))
+		settled := next.Sub(t) < 100*time.Millisecond && t.Sub(next) < 100*time.Millisecond
+		t = next
+		if settled {
+			break
+		}
+	}
+
+	return t.In(today.Location()).Truncate(time.Second), nil
+}
+
+// noaaCosH returns the cosine of the hour angle at which the Sun of
+// declination decl passes zenith at latitude.
+func noaaCosH(decl, latitude, zenith float64) float64 {
+	return (degreeCos(zenith) - degreeSin(latitude)*degreeSin(decl)) / (degreeCos(latitude) * degreeCos(decl))
+}
+
+// julianCentury returns the Julian centuries since J2000.0 at t, taken as
+// terrestrial time.
+func julianCentury(t time.Time) float64 {
+	jd := float64(t.UnixNano())/86400e9 + 2440587.5
+	return (jd - 2451545.0) / 36525.0
+}
+
+// noaaSun returns the Sun's apparent declination in degrees and the
+// equation of time in minutes at Julian century jc.
+func noaaSun(jc float64) (decl, eot float64) {
+	L0 := math.Mod(280.46646+jc*(36000.76983+jc*0.0003032), 360.0)
+	M := 357.52911 + jc*(35999.05029-0.0001537*jc)
+	e := 0.016708634 - jc*(0.000042037+0.0000001267*jc)
+	C := degreeSin(M)*(1.914602-jc*(0.004817+0.000014*jc)) +
+		degreeSin(2*M)*(0.019993-0.000101*jc) +
+		degreeSin(3*M)*0.000289
+	trueLong := L0 + C
+	omega := 125.04 - 1934.136*jc
+	appLong := trueLong - 0.00569 - 0.00478*degreeSin(omega)
+	meanObliq := 23.0 + (26.0+(21.448-jc*(46.815+jc*(0.00059-jc*0.001813)))/60.0)/60.0
+	obliq := meanObliq + 0.00256*degreeCos(omega)
+
+	decl = degreeAsin(degreeSin(obliq) * degreeSin(appLong))
+
+	y := degreeTan(obliq/2) * degreeTan(obliq/2)
+	eot = 4.0 * radianToDegree(y*degreeSin(2*L0)-
+		2*e*degreeSin(M)+
+		4*e*y*degreeSin(M)*degreeCos(2*L0)-
+		0.5*y*y*degreeSin(4*L0)-
+		1.25*e*e*degreeSin(2*M))
+
+	return decl, eot
+}
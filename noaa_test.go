@@ -0,0 +1,39 @@
+package sunevent
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNOAA(t *testing.T) {
+	// the two algorithms agree within their stated errors inside the
+	// almanac envelope
+	for _, latitude := range []float64{-45, 0, 22.63, 51.48, 60} {
+		for date := time.Date(2026, time.January, 5, 12, 0, 0, 0, time.UTC); date.Year() == 2026; date = date.AddDate(0, 0, 20) {
+			for _, rising := range []bool{true, false} {
+				almanac, err := TimeAtAltitude(date, latitude, 7, 0, rising)
+				if err != nil {
+					t.Fatal(err)
+				}
+				noaa, err := TimeAtAltitude(date, latitude, 7, 0, rising, WithAlgorithm(NOAA))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if d := noaa.Sub(almanac); d < -90*time.Second || d > 90*time.Second {
+					t.Errorf("latitude %v on %s: NOAA %s, almanac %s", latitude, date.Format("2006-01-02"), noaa, almanac)
+				}
+				if e := Elevation(noaa, latitude, 7); !near(e, 0, 0.1) {
+					t.Errorf("latitude %v on %s: elevation %v at the NOAA crossing", latitude, date.Format("2006-01-02"), e)
+				}
+			}
+		}
+	}
+
+	winter := time.Date(2026, time.December, 21, 12, 0, 0, 0, time.UTC)
+	if _, err := SunRiseOn(winter, 78.22, 15.65, WithAlgorithm(NOAA)); err != ErrSunNeverRises {
+		t.Errorf("NOAA sunrise in the polar night: %v, want ErrSunNeverRises", err)
+	}
+	if _, err := SunSetOn(winter, -77.85, 166.67, WithAlgorithm(NOAA)); err != ErrSunNeverSets {
+		t.Errorf("NOAA sunset in the midnight Sun: %v, want ErrSunNeverSets", err)
+	}
+}
@@ -0,0 +1,312 @@
+package sunevent
+
+import (
+	"math"
+	"time"
+)
+
+// Option configures a calculation.
+type Option func(*Options)
+
+// Options holds the settings applied by Option values. The zero value
+// selects the package defaults.
+type Options struct {
+	Azimuth  AzimuthConvention
+	Vertical VerticalAngle
+	Units    AngleUnit
+	Frame    Frame
+
+	// Longitude is the sign convention of longitude arguments.
+	Longitude LongitudeConvention
+
+	// LongitudeRange is the range longitude arguments are expected in.
+	LongitudeRange LongitudeRange
+
+	// PolarFallback makes EventOn return an approximate proxy instead of
+	// an error when an event does not happen.
+	PolarFallback bool
+
+	// Refraction adds the apparent lift of the Sun caused by atmospheric
+	// refraction to reported elevations.
+	Refraction bool
+
+	// RefractionModel computes the refraction and, when set, lowers
+	// sunrise and sunset to the apparent horizon. When nil, positions use
+	// StandardRefraction and sunrise and sunset are the geometric crossing
+	// of the horizon by the centre of the Sun.
+	RefractionModel RefractionModel
+
+	// ObserverElevation is the height of the observer in metres, which
+	// lowers the horizon for sunrise and sunset.
+	ObserverElevation float64
+
+	// Algorithm computes event times; nil selects Almanac.
+	Algorithm Algorithm
+
+	// Precision selects how far event times are refined.
+	Precision Precision
+
+	// DeltaT, if set, replaces DeltaT as the model of TT − UT. Only the
+	// NOAA algorithm evaluates the Sun in terrestrial time, so ΔT moves
+	// its event times, its solar noon and its test for polar days and
+	// nights. The almanac algorithm and the positions of the Sun, as in
+	// SunPosition, Elevation and ClassifyMany, take their formulas in UT
+	// and ignore it; the vsop87 ephemeris has a DeltaT of its own. The
+	// Moon is always evaluated in terrestrial time, so ΔT also moves
+	// MoonPosition, moonrise, moonset and the transit of the Moon; the
+	// functions without options, such as the moon phases, use DeltaT.
+	DeltaT func(t time.Time) time.Duration
+
+	// Ephemeris, if set, replaces the almanac position of the Sun.
+	Ephemeris Ephemeris
+
+	// DST resolves local wall-clock times, such as the midnight that
+	// starts a day, that a time zone change skips or repeats.
+	DST DSTPolicy
+
+	// SupermoonDistance is the distance of the Moon in km below which
+	// IsSupermoon counts a full moon; 0 selects DefaultSupermoonDistance.
+	SupermoonDistance float64
+}
+
+// AzimuthConvention selects where azimuth is measured from.
+type AzimuthConvention int
+
+const (
+	// NorthClockwise measures azimuth from north towards east, as on a
+	// compass. This is the default.
+	NorthClockwise AzimuthConvention = iota
+	// SouthClockwise measures azimuth from south towards west, as is
+	// customary in astronomy and some solar engineering tools.
+	SouthClockwise
+)
+
+// VerticalAngle selects how the height of the Sun is reported.
+type VerticalAngle int
+
+const (
+	// ElevationAngle is the angle above the horizon. This is the default.
+	ElevationAngle VerticalAngle = iota
+	// ZenithAngle is the angle from the zenith, 90° minus the elevation.
+	ZenithAngle
+)
+
+// AngleUnit selects the unit of angles returned by position functions.
+type AngleUnit int
+
+const (
+	Degrees AngleUnit = iota
+	Radians
+)
+
+// Frame selects the coordinate frame of direction vectors.
+type Frame int
+
+const (
+	// ENU is the observer's local East-North-Up frame. This is the default.
+	ENU Frame = iota
+	// ECEF is the Earth-Centered, Earth-Fixed frame: X through latitude 0
+	// and longitude 0, Z through the north pole.
+	ECEF
+)
+
+// LongitudeConvention selects the sign of longitudes passed to the
+// package.
+type LongitudeConvention int
+
+const (
+	// EastPositive treats longitudes east of Greenwich as positive, as in
+	// ISO 6709, GPS receivers and most maps. This is the default.
+	EastPositive LongitudeConvention = iota
+	// WestPositive treats longitudes west of Greenwich as positive, as in
+	// some older almanacs and astronomy software.
+	WestPositive
+)
+
+// LongitudeRange declares the range longitude arguments are given in.
+// Longitudes are wrapped into [-180, 180) before use whatever the range,
+// so 200 and -160 give the same results; the range only tells
+// CheckLongitude which values are out of place.
+type LongitudeRange int
+
+const (
+	// Signed180 expects longitudes in [-180, 180], as in ISO 6709 and most
+	// GPS receivers. This is the default.
+	Signed180 LongitudeRange = iota
+	// Unsigned360 expects longitudes in [0, 360), measured all the way
+	// round in the direction of the sign convention, as in many climate
+	// model grids and GIS rasters.
+	Unsigned360
+)
+
+// WithAzimuth selects the azimuth convention of position results.
+func WithAzimuth(c AzimuthConvention) Option {
+	return func(o *Options) {
+		o.Azimuth = c
+	}
+}
+
+// WithVerticalAngle selects whether position results report elevation or
+// zenith angle.
+func WithVerticalAngle(v VerticalAngle) Option {
+	return func(o *Options) {
+		o.Vertical = v
+	}
+}
+
+// WithUnits selects degrees or radians for position results.
+func WithUnits(u AngleUnit) Option {
+	return func(o *Options) {
+		o.Units = u
+	}
+}
+
+// WithFrame selects the coordinate frame of SunVector.
+func WithFrame(f Frame) Option {
+	return func(o *Options) {
+		o.Frame = f
+	}
+}
+
+// WithRefraction reports the apparent elevation of the Sun, including
+// atmospheric refraction, instead of the geometric elevation.
+func WithRefraction() Option {
+	return func(o *Options) {
+		o.Refraction = true
+	}
+}
+
+// WithLongitudeConvention selects the sign convention of longitude
+// arguments.
+func WithLongitudeConvention(c LongitudeConvention) Option {
+	return func(o *Options) {
+		o.Longitude = c
+	}
+}
+
+// WithLongitudeRange declares the range of longitude arguments, for
+// CheckLongitude to flag values outside it.
+func WithLongitudeRange(r LongitudeRange) Option {
+	return func(o *Options) {
+		o.LongitudeRange = r
+	}
+}
+
+// WithAlgorithm selects the algorithm that computes event times and, for
+// the built-in algorithms, solar noon. Positions are not affected.
+func WithAlgorithm(a Algorithm) Option {
+	return func(o *Options) {
+		o.Algorithm = a
+	}
+}
+
+// Precision selects the effort spent on event times.
+type Precision int
+
+const (
+	// Standard evaluates the Almanac algorithm once, at 6h or 18h local
+	// mean time, for errors within half a minute at moderate latitudes.
+	// This is the default.
+	Standard Precision = iota
+	// High recomputes the solar parameters at the estimated time of the
+	// event until the estimate settles within 0.1 seconds. This removes
+	// the error of the fixed guess, which reaches minutes at high
+	// latitudes, leaving errors of a few seconds. The NOAA algorithm,
+	// which always refines its estimate, then iterates to convergence.
+	High
+)
+
+// WithPrecision selects the precision of event times.
+func WithPrecision(p Precision) Option {
+	return func(o *Options) {
+		o.Precision = p
+	}
+}
+
+// WithPolarFallback makes EventOn report the nearest meaningful substitute
+// when an event does not happen, instead of an error: the brightest moment
+// of the day (solar noon) when the Sun stays below the event's altitude,
+// and the darkest moment (solar midnight) when it stays above. Such events
+// are marked Approximate. This suits displays that must always show a
+// time, such as consumer apps above the polar circles.
+func WithPolarFallback() Option {
+	return func(o *Options) {
+		o.PolarFallback = true
+	}
+}
+
+func newOptions(opts []Option) Options {
+	var o Options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// longitude converts a longitude argument into degrees east in
+// [-180, 180).
+func (o Options) longitude(longitude float64) float64 {
+	if o.Longitude == WestPositive {
+		longitude = -longitude
+	}
+	// math.Mod rather than normalizeRange, which loops on huge inputs
+	longitude = math.Mod(longitude+180.0, 360)
+	if longitude < 0 {
+		longitude += 360
+	}
+	return longitude - 180.0
+}
+
+// inLongitudeRange reports whether a longitude argument lies within the
+// range declared by o.
+func (o Options) inLongitudeRange(longitude float64) bool {
+	if o.LongitudeRange == Unsigned360 {
+		return longitude >= 0 && longitude < 360
+	}
+	return longitude >= -180 && longitude <= 180
+}
+
+// position converts an azimuth (north clockwise) and elevation in degrees
+// into the conventions selected by o.
+func (o Options) position(azimuth, elevation float64) Position {
+	if o.Refraction {
+		elevation += o.refraction(elevation)
+	}
+
+	p := Position{Azimuth: azimuth, Altitude: elevation}
+	if o.Azimuth == SouthClockwise {
+		p.Azimuth = normalizeRange(azimuth+180.0, 360)
+	}
+	if o.Vertical == ZenithAngle {
+		p.Altitude = 90.0 - elevation
+	}
+	if o.Units == Radians {
+		p.Azimuth = degreeToRadian(p.Azimuth)
+		p.Altitude = degreeToRadian(p.Altitude)
+	}
+	return p
+}
+
+// azimuth converts an azimuth given in the conventions selected by o into
+// degrees clockwise from north.
+func (o Options) azimuth(azimuth float64) float64 {
+	if o.Units == Radians {
+		azimuth = radianToDegree(azimuth)
+	}
+	if o.Azimuth == SouthClockwise {
+		azimuth += 180.0
+	}
+	return normalizeRange(azimuth, 360)
+}
+
+// elevation converts a vertical angle given in the conventions selected by
+// o into degrees above the horizon.
+func (o Options) elevation(angle float64) float64 {
+	if o.Units == Radians {
+		angle = radianToDegree(angle)
+	}
+	if o.Vertical == ZenithAngle {
+		angle = 90.0 - angle
+	}
+	return angle
+}
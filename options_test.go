@@ -0,0 +1,74 @@
+package sunevent
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestPositionConventions(t *testing.T) {
+	const latitude, longitude = 22.63, 120.30
+	at := time.Date(2026, time.June, 21, 1, 0, 0, 0, time.UTC)
+	p := SunPosition(at, latitude, longitude)
+
+	tests := []struct {
+		name string
+		opts []Option
+		want Position
+	}{
+		{"south clockwise", []Option{WithAzimuth(SouthClockwise)}, Position{math.Mod(p.Azimuth+180, 360), p.Altitude}},
+		{"zenith angle", []Option{WithVerticalAngle(ZenithAngle)}, Position{p.Azimuth, 90 - p.Altitude}},
+		{"radians", []Option{WithUnits(Radians)}, Position{p.Azimuth * math.Pi / 180, p.Altitude * math.Pi / 180}},
+		{"all", []Option{WithAzimuth(SouthClockwise), WithVerticalAngle(ZenithAngle), WithUnits(Radians)},
+			Position{math.Mod(p.Azimuth+180, 360) * math.Pi / 180, (90 - p.Altitude) * math.Pi / 180}},
+	}
+	for _, tt := range tests {
+		got := SunPosition(at, latitude, longitude, tt.opts...)
+		if !near(got.Azimuth, tt.want.Azimuth, 1e-9) || !near(got.Altitude, tt.want.Altitude, 1e-9) {
+			t.Errorf("%s: SunPosition = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPositionDefaults(t *testing.T) {
+	// at solar noon on the June solstice the Sun is due south at 45°N,
+	// 90° − 45° + 23.44° high
+	noon, err := SolarNoonOn(time.Date(2026, time.June, 21, 12, 0, 0, 0, time.UTC), 45, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := SunPosition(noon, 45, 0)
+	if !near(p.Azimuth, 180, 0.5) || !near(p.Altitude, 90-45+23.44, 0.05) {
+		t.Errorf("SunPosition at noon at 45°N = %+v, want south at 68.44°", p)
+	}
+}
+
+func TestWithPrecision(t *testing.T) {
+	// in May at 65°N sunrise is hours from the 6h guess of the Almanac
+	const latitude, longitude = 65, 10
+	date := time.Date(2026, time.May, 20, 12, 0, 0, 0, time.UTC)
+
+	standard, err := SunRiseOn(date, latitude, longitude)
+	if err != nil {
+		t.Fatal(err)
+	}
+	high, err := SunRiseOn(date, latitude, longitude, WithPrecision(High))
+	if err != nil {
+		t.Fatal(err)
+	}
+	noaa, err := SunRiseOn(date, latitude, longitude, WithAlgorithm(NOAA), WithPrecision(High))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the Sun is on the geometric horizon at sunrise
+	if alt := SunPosition(standard, latitude, longitude).Altitude; math.Abs(alt) < 0.01 {
+		t.Errorf("standard sunrise %v at altitude %.4f°, want the error of the fixed guess", standard, alt)
+	}
+	if alt := SunPosition(high, latitude, longitude).Altitude; math.Abs(alt) > 0.005 {
+		t.Errorf("high precision sunrise %v at altitude %.4f°, want 0", high, alt)
+	}
+	if d := high.Sub(noaa); d < -time.Second || d > time.Second {
+		t.Errorf("high precision sunrise %v, NOAA %v", high, noaa)
+	}
+}
@@ -0,0 +1,171 @@
+package sunevent
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// eventAliases maps alternative names, after normalization, to event
+// types. The canonical names from EventType.String are accepted as well.
+var eventAliases = map[string]EventType{
+	"rise":              Sunrise,
+	"sunup":             Sunrise,
+	"set":               Sunset,
+	"sundown":           Sunset,
+	"noon":              SolarNoon,
+	"midday":            SolarNoon,
+	"transit":           SolarNoon,
+	"dawn":              CivilDawn,
+	"first_light":       CivilDawn,
+	"dusk":              CivilDusk,
+	"last_light":        CivilDusk,
+	"nautical_twilight": NauticalDusk,
+	"astronomical_dark": AstronomicalDusk,
+	"nightfall":         AstronomicalDusk,
+	"daybreak":          AstronomicalDawn,
+
+	// Traditional Chinese (zh-TW)
+	"日出":    Sunrise,
+	"日落":    Sunset,
+	"日沒":    Sunset,
+	"正午":    SolarNoon,
+	"民用晨光始": CivilDawn,
+	"民用暮光終": CivilDusk,
+	"航海晨光始": NauticalDawn,
+	"航海暮光終": NauticalDusk,
+	"天文晨光始": AstronomicalDawn,
+	"天文暮光終": AstronomicalDusk,
+}
+
+// UnknownEventError is returned by ParseEventType for a name it does not
+// recognize. Suggestions holds the closest known names, if any.
+type UnknownEventError struct {
+	Name        string
+	Suggestions []string
+}
+
+func (e *UnknownEventError) Error() string {
+	if len(e.Suggestions) == 0 {
+		return fmt.Sprintf("sunevent: unknown event %q", e.Name)
+	}
+	return fmt.Sprintf("sunevent: unknown event %q, did you mean %s?", e.Name, strings.Join(e.Suggestions, ", "))
+}
+
+// ParseEventType returns the event type named s. It accepts the names
+// returned by EventType.String, common aliases such as "sundown" or
+// "first light", and Traditional Chinese names. Case, spaces and hyphens
+// are ignored. An unknown name yields an *UnknownEventError listing close
+// matches.
+func ParseEventType(s string) (EventType, error) {
+	name := normalizeEventName(s)
+	for _, e := range EventTypes {
+		if e.String() == name {
+			return e, nil
+		}
+	}
+	if e, ok := eventAliases[name]; ok {
+		return e, nil
+	}
+	return 0, &UnknownEventError{Name: s, Suggestions: suggestEventNames(name)}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (e EventType) MarshalText() ([]byte, error) {
+	if e < 0 || int(e) >= len(eventNames) {
+		return nil, fmt.Errorf("sunevent: invalid event type %d", int(e))
+	}
+	return []byte(e.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using ParseEventType.
+func (e *EventType) UnmarshalText(text []byte) error {
+	v, err := ParseEventType(string(text))
+	if err != nil {
+		return err
+	}
+	*e = v
+	return nil
+}
+
+func normalizeEventName(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' {
+			return '_'
+		}
+		return r
+	}, s)
+}
+
+// suggestEventNames returns the known names within a small edit distance
+// of name, closest first.
+func suggestEventNames(name string) []string {
+	type candidate struct {
+		name     string
+		distance int
+	}
+
+	var candidates []candidate
+	add := func(known string) {
+		limit := utf8.RuneCountInString(known) / 3
+		if limit < 1 {
+			limit = 1
+		}
+		if d := editDistance(name, known); d <= limit {
+			candidates = append(candidates, candidate{known, d})
+		}
+	}
+	for _, e := range EventTypes {
+		add(e.String())
+	}
+	for alias := range eventAliases {
+		add(alias)
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return candidates[i].name < candidates[j].name
+	})
+
+	var names []string
+	for _, c := range candidates {
+		if len(names) == 3 {
+			break
+		}
+		names = append(names, c.name)
+	}
+	return names
+}
+
+// editDistance returns the Levenshtein distance between a and b in runes.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(prev[j]+1, minInt(cur[j-1]+1, prev[j-1]+cost))
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
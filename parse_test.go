@@ -0,0 +1,53 @@
+package sunevent
+
+import "testing"
+
+func TestParseEventType(t *testing.T) {
+	tests := []struct {
+		in   string
+		want EventType
+	}{
+		{"sunrise", Sunrise},
+		{"civil_dawn", CivilDawn},
+		{"Civil Dawn", CivilDawn},
+		{"astronomical-dusk", AstronomicalDusk},
+		{"sundown", Sunset},
+		{"first light", CivilDawn},
+		{"noon", SolarNoon},
+		{" nightfall ", AstronomicalDusk},
+		{"日出", Sunrise},
+		{"民用暮光終", CivilDusk},
+	}
+	for _, tt := range tests {
+		got, err := ParseEventType(tt.in)
+		if err != nil {
+			t.Errorf("ParseEventType(%q): %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseEventType(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+
+	for _, e := range EventTypes {
+		if got, err := ParseEventType(e.String()); err != nil || got != e {
+			t.Errorf("ParseEventType(%q) = %v, %v, want %v", e.String(), got, err, e)
+		}
+	}
+}
+
+func TestParseEventTypeUnknown(t *testing.T) {
+	_, err := ParseEventType("sunsett")
+	u, ok := err.(*UnknownEventError)
+	if !ok {
+		t.Fatalf("ParseEventType(%q) error = %v, want *UnknownEventError", "sunsett", err)
+	}
+	if len(u.Suggestions) == 0 || u.Suggestions[0] != "sunset" {
+		t.Errorf("suggestions for %q = %v, want sunset first", "sunsett", u.Suggestions)
+	}
+
+	_, err = ParseEventType("breakfast")
+	if u, ok := err.(*UnknownEventError); !ok || len(u.Suggestions) != 0 {
+		t.Errorf("ParseEventType(%q) error = %v, want *UnknownEventError without suggestions", "breakfast", err)
+	}
+}
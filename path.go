@@ -0,0 +1,81 @@
+package sunevent
+
+import (
+	"time"
+)
+
+// PathPoint is one point of a sun-path diagram or analemma.
+type PathPoint Sample
+
+// SunPath returns the track of the Sun across the sky on the civil date of
+// date, one point every ten minutes while the Sun is above the horizon,
+// plus the points where it crosses the horizon. On a day of polar night it
+// returns no points.
+func SunPath(date time.Time, latitude, longitude float64, opts ...Option) []PathPoint {
+	o := newOptions(opts)
+	longitude = o.longitude(longitude)
+
+	start, end := o.civilDay(date)
+	day := newDayCoordinates(start, end)
+
+	point := func(t time.Time) PathPoint {
+		azimuth, elevation := day.position(t, latitude, longitude)
+		return PathPoint{Time: t, Position: Position{Azimuth: azimuth, Altitude: elevation}}
+	}
+
+	// horizon finds the crossing between a and b to the second
+	horizon := func(a, b PathPoint) PathPoint {
+		for b.Time.Sub(a.Time) > time.Second {
+			mid := point(a.Time.Add(b.Time.Sub(a.Time) / 2))
+			if (mid.Altitude < 0) == (a.Altitude < 0) {
+				a = mid
+			} else {
+				b = mid
+			}
+		}
+		p := point(a.Time.Round(time.Second))
+		p.Altitude = 0
+		return p
+	}
+
+	const step = 10 * time.Minute
+	var path []PathPoint
+	prev := point(start)
+	if prev.Altitude >= 0 {
+		path = append(path, prev)
+	}
+	for t := start.Add(step); !t.After(end); t = t.Add(step) {
+		p := point(t)
+		if (p.Altitude < 0) != (prev.Altitude < 0) {
+			path = append(path, horizon(prev, p))
+		}
+		if p.Altitude >= 0 && t.Before(end) {
+			path = append(path, p)
+		}
+		prev = p
+	}
+
+	for i := range path {
+		path[i].Position = o.position(path[i].Azimuth, path[i].Altitude)
+	}
+	return path
+}
+
+// Analemma returns the position of the Sun at the same local mean time,
+// hour:00, on every day of year. Local mean time follows the observer's
+// longitude rather than a time zone, so the figure is centred on the
+// meridian at hour 12. Points below the horizon are included.
+func Analemma(hour int, year int, latitude, longitude float64, opts ...Option) []PathPoint {
+	o := newOptions(opts)
+	longitude = o.longitude(longitude)
+
+	UT := time.Duration((float64(hour) - longitude/15.0) * float64(time.Hour))
+
+	var path []PathPoint
+	for date := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC); date.Year() == year; date = date.AddDate(0, 0, 1) {
+		t := date.Add(UT)
+		azimuth, elevation := o.sunAt(t, latitude, longitude)
+		path = append(path, PathPoint{Time: t, Position: o.position(azimuth, elevation)})
+	}
+	return path
+}
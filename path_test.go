@@ -0,0 +1,69 @@
+package sunevent
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSunPath(t *testing.T) {
+	const latitude, longitude = 45.0, 7.0
+	date := time.Date(2026, time.June, 21, 12, 0, 0, 0, time.UTC)
+	path := SunPath(date, latitude, longitude)
+	if len(path) < 90 {
+		t.Fatalf("%d points for a day of 15 hours", len(path))
+	}
+
+	first, last := path[0], path[len(path)-1]
+	if first.Altitude != 0 || last.Altitude != 0 {
+		t.Errorf("path from %.3f° to %.3f°, want the horizon at both ends", first.Altitude, last.Altitude)
+	}
+	// the ends are the geometric crossings of the horizon
+	for _, e := range []struct {
+		p      PathPoint
+		rising bool
+	}{{first, true}, {last, false}} {
+		want, err := TimeAtAltitude(date, latitude, longitude, 0, e.rising)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d := e.p.Time.Sub(want); d > time.Minute || d < -time.Minute {
+			t.Errorf("path ends at %s, want %s", e.p.Time, want)
+		}
+	}
+	for i, p := range path[1 : len(path)-1] {
+		if p.Altitude < 0 || !p.Time.After(path[i].Time) {
+			t.Errorf("point %d = %+v", i+1, p)
+		}
+	}
+
+	polarNight := time.Date(2026, time.December, 21, 12, 0, 0, 0, time.UTC)
+	if path := SunPath(polarNight, 78.22, 15.65); len(path) != 0 {
+		t.Errorf("%d points in polar night", len(path))
+	}
+}
+
+func TestAnalemma(t *testing.T) {
+	const latitude, longitude = 45.0, 30.0
+	points := Analemma(12, 2026, latitude, longitude)
+	if len(points) != 365 {
+		t.Fatalf("%d points in 2026", len(points))
+	}
+
+	// noon local mean time at 30°E is 10:00 UTC, and the altitude spans
+	// the two solstices
+	min, max := 90.0, -90.0
+	for _, p := range points {
+		if p.Time.UTC().Format("15:04") != "10:00" {
+			t.Fatalf("point at %s, want 10:00 UTC", p.Time)
+		}
+		if p.Altitude < min {
+			min = p.Altitude
+		}
+		if p.Altitude > max {
+			max = p.Altitude
+		}
+	}
+	if !near(max, 90-latitude+23.44, 0.3) || !near(min, 90-latitude-23.44, 0.3) {
+		t.Errorf("altitudes from %.2f° to %.2f°", min, max)
+	}
+}
@@ -0,0 +1,113 @@
+package sunevent
+
+import (
+	"time"
+)
+
+// PhaseKind classifies the light of the Sun at an instant by its elevation,
+// using the same altitudes as the events. The kinds are ordered from dark
+// to light, so they can be compared.
+type PhaseKind int
+
+const (
+	// PhaseNight has the Sun more than 18° below the horizon.
+	PhaseNight PhaseKind = iota
+	// PhaseAstronomicalTwilight has the Sun between 18° and 12° below the
+	// horizon.
+	PhaseAstronomicalTwilight
+	// PhaseNauticalTwilight has the Sun between 12° and 6° below the
+	// horizon.
+	PhaseNauticalTwilight
+	// PhaseCivilTwilight has the Sun between 6° below the horizon and
+	// sunrise or sunset.
+	PhaseCivilTwilight
+	// PhaseDay has the Sun above the horizon.
+	PhaseDay
+)
+
+func (p PhaseKind) String() string {
+	switch p {
+	case PhaseNight:
+		return "night"
+	case PhaseAstronomicalTwilight:
+		return "astronomical_twilight"
+	case PhaseNauticalTwilight:
+		return "nautical_twilight"
+	case PhaseCivilTwilight:
+		return "civil_twilight"
+	case PhaseDay:
+		return "day"
+	}
+	return "unknown"
+}
+
+// PhaseAt returns the phase of daylight at the instant t. Like the events
+// it uses the geometric elevation of the centre of the Sun.
+func PhaseAt(t time.Time, latitude, longitude float64, opts ...Option) PhaseKind {
+	o := newOptions(opts)
+	_, elevation := o.sunAt(t, latitude, o.longitude(longitude))
+	return phaseOf(elevation)
+}
+
+// phaseOf returns the phase for the Sun at elevation degrees.
+func phaseOf(elevation float64) PhaseKind {
+	switch {
+	case elevation >= 0:
+		return PhaseDay
+	case elevation >= -6:
+		return PhaseCivilTwilight
+	case elevation >= -12:
+		return PhaseNauticalTwilight
+	case elevation >= -18:
+		return PhaseAstronomicalTwilight
+	}
+	return PhaseNight
+}
+
+// Coordinates is a location on the Earth in degrees.
+type Coordinates struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// phaseSines holds the sines of the altitudes that bound the phases, from
+// light to dark.
+var phaseSines = [...]float64{
+	degreeSin(0),
+	degreeSin(-6),
+	degreeSin(-12),
+	degreeSin(-18),
+}
+
+// ClassifyMany returns the phase of daylight at the instant t for each
+// location, as PhaseAt would. The Sun's coordinates and the hour angle at
+// Greenwich are computed once for all locations, and the elevation is
+// compared through its sine, so each location costs a few multiplications
+// and cosines. This suits dashboards tracking thousands of assets.
+func ClassifyMany(t time.Time, locations []Coordinates, opts ...Option) []PhaseKind {
+	o := newOptions(opts)
+	day := julianCentury(t)
+	_, RA, sinDec, cosDec := sunCoordinates(day)
+	greenwich := localHourAngle(day, RA, t, 0)
+	if o.Ephemeris != nil {
+		var dec float64
+		greenwich, dec = o.Ephemeris.SunGreenwich(t)
+		sinDec, cosDec = degreeSin(dec), degreeCos(dec)
+	}
+
+	phases := make([]PhaseKind, len(locations))
+	for i, c := range locations {
+		H := greenwich + o.longitude(c.Longitude)
+		sinAlt := sinDec*degreeSin(c.Latitude) + cosDec*degreeCos(c.Latitude)*degreeCos(H)
+
+		phase := PhaseNight
+		for j, s := range phaseSines {
+			if sinAlt >= s {
+				phase = PhaseDay - PhaseKind(j)
+				break
+			}
+		}
+		phases[i] = phase
+	}
+	return phases
+}
@@ -0,0 +1,72 @@
+package sunevent
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPhaseAt(t *testing.T) {
+	tests := []struct {
+		elevation float64
+		want      PhaseKind
+	}{
+		{30, PhaseDay},
+		{0, PhaseDay},
+		{-0.1, PhaseCivilTwilight},
+		{-6, PhaseCivilTwilight},
+		{-6.1, PhaseNauticalTwilight},
+		{-12.1, PhaseAstronomicalTwilight},
+		{-18.1, PhaseNight},
+	}
+	for _, tt := range tests {
+		if got := phaseOf(tt.elevation); got != tt.want {
+			t.Errorf("phaseOf(%v) = %v, want %v", tt.elevation, got, tt.want)
+		}
+	}
+	if s := PhaseNauticalTwilight.String(); s != "nautical_twilight" {
+		t.Errorf("String = %q", s)
+	}
+}
+
+func TestClassifyMany(t *testing.T) {
+	var locations []Coordinates
+	for latitude := -80.0; latitude <= 80; latitude += 20 {
+		for longitude := -180.0; longitude < 180; longitude += 30 {
+			locations = append(locations, Coordinates{latitude, longitude})
+		}
+	}
+
+	// away from the phase boundaries the shared parameters give the phase
+	// of each location
+	for _, at := range []time.Time{
+		time.Date(2026, time.March, 20, 6, 0, 0, 0, time.UTC),
+		time.Date(2026, time.December, 21, 18, 30, 0, 0, time.UTC),
+	} {
+		phases := ClassifyMany(at, locations)
+		if len(phases) != len(locations) {
+			t.Fatalf("%d phases for %d locations", len(phases), len(locations))
+		}
+		for i, c := range locations {
+			e := Elevation(at, c.Latitude, c.Longitude)
+			want := PhaseAt(at, c.Latitude, c.Longitude)
+			if phases[i] != want && !nearBoundary(e) {
+				t.Errorf("%s at %v: %v, want %v at elevation %v", at, c, phases[i], want, e)
+			}
+		}
+	}
+
+	if phases := ClassifyMany(time.Now(), nil); len(phases) != 0 {
+		t.Errorf("phases of no locations: %v", phases)
+	}
+}
+
+// nearBoundary reports whether elevation is within a hundredth of a degree
+// of the altitude between two phases.
+func nearBoundary(elevation float64) bool {
+	for _, a := range []float64{0, -6, -12, -18} {
+		if near(elevation, a, 0.01) {
+			return true
+		}
+	}
+	return false
+}
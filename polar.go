@@ -0,0 +1,151 @@
+package sunevent
+
+import (
+	"time"
+)
+
+// DayType classifies a day by whether the Sun rises and sets.
+type DayType int
+
+const (
+	// NormalDay has a sunrise and a sunset.
+	NormalDay DayType = iota
+	// PolarDay is a day of midnight sun: the Sun never sets.
+	PolarDay
+	// PolarNight is a day the Sun never rises.
+	PolarNight
+)
+
+func (d DayType) String() string {
+	switch d {
+	case NormalDay:
+		return "normal"
+	case PolarDay:
+		return "polar_day"
+	case PolarNight:
+		return "polar_night"
+	}
+	return "unknown"
+}
+
+// PolarPeriod is a run of consecutive polar days or polar nights. Start
+// and End are the first and last dates of the run, at midnight UTC.
+// Periods are clipped to the requested year; Clipped reports that the
+// period continues past the start or end of the year.
+type PolarPeriod struct {
+	Type       DayType
+	Start, End time.Time
+	Clipped    bool
+}
+
+// PolarTransitions returns the periods of midnight sun and polar night in
+// year for the given location, in chronological order. It returns nil
+// where every day has a sunrise and a sunset.
+func PolarTransitions(latitude, longitude float64, year int, opts ...Option) []PolarPeriod {
+	var periods []PolarPeriod
+	var current *PolarPeriod
+
+	first := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	for date := first; date.Year() == year; date = date.AddDate(0, 0, 1) {
+		kind := dayType(date.Add(12*time.Hour), latitude, longitude, opts)
+
+		if current != nil && current.Type == kind {
+			current.End = date
+			continue
+		}
+		if current != nil {
+			periods = append(periods, *current)
+			current = nil
+		}
+		if kind != NormalDay {
+			current = &PolarPeriod{Type: kind, Start: date, End: date, Clipped: date.Equal(first)}
+		}
+	}
+	if current != nil {
+		current.Clipped = true
+		periods = append(periods, *current)
+	}
+
+	return periods
+}
+
+// IsPolarDay reports whether the Sun stays above the horizon all day on
+// the civil date of date. It only evaluates the hour angle condition, so
+// it is much cheaper than computing the events.
+func IsPolarDay(date time.Time, latitude, longitude float64, opts ...Option) bool {
+	return dayType(date, latitude, longitude, opts) == PolarDay
+}
+
+// IsPolarNight reports whether the Sun stays below the horizon all day on
+// the civil date of date. Like IsPolarDay it does not compute the events.
+func IsPolarNight(date time.Time, latitude, longitude float64, opts ...Option) bool {
+	return dayType(date, latitude, longitude, opts) == PolarNight
+}
+
+// dayType classifies a date with the same hour angle test sunRiseSet
+// uses, checking the morning and then the evening approximation.
+func dayType(date time.Time, latitude, longitude float64, opts []Option) DayType {
+	o := newOptions(opts)
+	longitude = o.longitude(longitude)
+	if _, ok := algorithmInfo(o.algorithm()); !ok {
+		rise, set, err := o.Algorithm.SunTimes(date, latitude, longitude, 90.0-o.sunriseAltitude())
+		switch {
+		case !rise.IsZero() && !set.IsZero():
+			return NormalDay
+		case err == ErrSunNeverSets:
+			return PolarDay
+		}
+		return PolarNight
+	}
+	for _, rising := range []bool{true, false} {
+		guess := approximateTime(date, rising, longitude)
+		zenith := 90.0 - o.sunriseAltitude()
+		_, _, cosH := horizonCosH(guess, latitude, zenith)
+		if o.algorithm() == NOAA {
+			decl, _ := noaaSun(o.julianCentury(guess))
+			cosH = noaaCosH(decl, latitude, zenith)
+		}
+		if cosH > 1.0 {
+			return PolarNight
+		}
+		if cosH < -1.0 {
+			return PolarDay
+		}
+	}
+	return NormalDay
+}
+
+// NextSunriseDate returns the first sunrise after the given instant,
+// scanning forward through polar night if needed, so that high-latitude
+// users can be told when the sun returns. Dates are taken in the time
+// zone of after. It returns ErrSunNeverRises if no sunrise occurs within
+// a year.
+func NextSunriseDate(after time.Time, latitude, longitude float64, opts ...Option) (time.Time, error) {
+	return nextEvent(after, true, latitude, longitude, opts)
+}
+
+// NextSunsetDate returns the first sunset after the given instant,
+// scanning forward through midnight sun if needed. It returns
+// ErrSunNeverSets if no sunset occurs within a year.
+func NextSunsetDate(after time.Time, latitude, longitude float64, opts ...Option) (time.Time, error) {
+	return nextEvent(after, false, latitude, longitude, opts)
+}
+
+func nextEvent(after time.Time, rising bool, latitude, longitude float64, opts []Option) (time.Time, error) {
+	o := newOptions(opts)
+	date := time.Date(after.Year(), after.Month(), after.Day(), 12, 0, 0, 0, after.Location())
+	for i := 0; i <= 366; i++ {
+		if dayType(date, latitude, longitude, opts) == NormalDay {
+			t, err := TimeAtAltitude(date, latitude, longitude, o.sunriseAltitude(), rising, opts...)
+			if err == nil && t.After(after) {
+				return t, nil
+			}
+		}
+		date = date.AddDate(0, 0, 1)
+	}
+
+	if rising {
+		return time.Time{}, ErrSunNeverRises
+	}
+	return time.Time{}, ErrSunNeverSets
+}
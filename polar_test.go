@@ -0,0 +1,123 @@
+package sunevent
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPolarTransitions(t *testing.T) {
+	date := func(month time.Month, day int) time.Time {
+		return time.Date(2026, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name                string
+		latitude, longitude float64
+		want                []PolarPeriod
+	}{
+		{"Longyearbyen", 78.22, 15.65, []PolarPeriod{
+			{PolarNight, date(time.January, 1), date(time.February, 17), true},
+			{PolarDay, date(time.April, 21), date(time.August, 21), false},
+			{PolarNight, date(time.October, 24), date(time.December, 31), true},
+		}},
+		{"McMurdo", -77.85, 166.67, []PolarPeriod{
+			{PolarDay, date(time.January, 1), date(time.February, 16), true},
+			{PolarNight, date(time.April, 22), date(time.August, 20), false},
+			{PolarDay, date(time.October, 25), date(time.December, 31), true},
+		}},
+		{"Kaohsiung", 22.63, 120.30, nil},
+	}
+	for _, tt := range tests {
+		got := PolarTransitions(tt.latitude, tt.longitude, 2026)
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: %d periods, want %d: %+v", tt.name, len(got), len(tt.want), got)
+			continue
+		}
+		for i, p := range got {
+			w := tt.want[i]
+			// the geometric horizon decides the boundary days, allow one
+			// day either way
+			if p.Type != w.Type || p.Clipped != w.Clipped ||
+				absDuration(p.Start.Sub(w.Start)) > 24*time.Hour || absDuration(p.End.Sub(w.End)) > 24*time.Hour {
+				t.Errorf("%s: period %d = %v %s–%s %v, want %v %s–%s %v", tt.name, i,
+					p.Type, p.Start.Format("01-02"), p.End.Format("01-02"), p.Clipped,
+					w.Type, w.Start.Format("01-02"), w.End.Format("01-02"), w.Clipped)
+			}
+		}
+	}
+}
+
+func TestDayTypeString(t *testing.T) {
+	for d, want := range map[DayType]string{NormalDay: "normal", PolarDay: "polar_day", PolarNight: "polar_night", DayType(9): "unknown"} {
+		if d.String() != want {
+			t.Errorf("%d = %q, want %q", int(d), d.String(), want)
+		}
+	}
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+func TestIsPolarDayAndNight(t *testing.T) {
+	// the predicates agree with the errors of SunRiseOn and SunSetOn every
+	// day of the year, both near the polar circle and well inside it; on
+	// the day of a transition one of the two still happens
+	for _, latitude := range []float64{69.65, 78.22, -77.85} {
+		for date := time.Date(2026, time.January, 1, 12, 0, 0, 0, time.UTC); date.Year() == 2026; date = date.AddDate(0, 0, 1) {
+			_, riseErr := SunRiseOn(date, latitude, 15)
+			_, setErr := SunSetOn(date, latitude, 15)
+			day, night := IsPolarDay(date, latitude, 15), IsPolarNight(date, latitude, 15)
+			polarDay := riseErr == ErrSunNeverSets || setErr == ErrSunNeverSets
+			polarNight := riseErr == ErrSunNeverRises || setErr == ErrSunNeverRises
+			if day != polarDay || night != polarNight {
+				t.Errorf("latitude %v on %s: IsPolarDay %v, IsPolarNight %v, errors %v, %v",
+					latitude, date.Format("2006-01-02"), day, night, riseErr, setErr)
+			}
+		}
+	}
+
+	if IsPolarDay(time.Date(2026, time.June, 21, 12, 0, 0, 0, time.UTC), 22.63, 120.30) {
+		t.Error("polar day in the tropics")
+	}
+}
+
+func TestNextSunriseDate(t *testing.T) {
+	oslo := time.FixedZone("CET", 3600)
+	const latitude, longitude = 78.22, 15.65
+
+	// the Sun returns to Longyearbyen in the middle of February
+	rise, err := NextSunriseDate(time.Date(2026, time.December, 1, 12, 0, 0, 0, oslo), latitude, longitude)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rise.Year() != 2027 || rise.Month() != time.February || rise.Day() < 14 || rise.Day() > 20 || rise.Location() != oslo {
+		t.Errorf("next sunrise = %s, want mid-February 2027", rise)
+	}
+	if IsPolarNight(rise, latitude, longitude) {
+		t.Errorf("next sunrise %s on a day of polar night", rise)
+	}
+
+	// and sets again at the end of August
+	set, err := NextSunsetDate(time.Date(2026, time.May, 1, 12, 0, 0, 0, oslo), latitude, longitude)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if set.Month() != time.August || set.Day() < 18 || set.Day() > 25 {
+		t.Errorf("next sunset = %s, want late August 2026", set)
+	}
+
+	// elsewhere it is the next sunrise, today or tomorrow
+	taipei := time.FixedZone("CST", 8*3600)
+	today := time.Date(2026, time.March, 5, 12, 0, 0, 0, taipei)
+	todayRise, _ := SunRiseOn(today, 22.63, 120.30)
+	tomorrowRise, _ := SunRiseOn(today.AddDate(0, 0, 1), 22.63, 120.30)
+	if got, _ := NextSunriseDate(todayRise.Add(-time.Minute), 22.63, 120.30); !got.Equal(todayRise) {
+		t.Errorf("next sunrise before dawn = %s, want %s", got, todayRise)
+	}
+	if got, _ := NextSunriseDate(todayRise, 22.63, 120.30); !got.Equal(tomorrowRise) {
+		t.Errorf("next sunrise at sunrise = %s, want %s", got, tomorrowRise)
+	}
+}
@@ -0,0 +1,152 @@
+package sunevent
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// ErrAzimuthNotReached is returned by TimeAtAzimuth when the Sun does not
+// pass the requested azimuth on the given date.
+var ErrAzimuthNotReached = errors.New("sunevent: the sun does not reach this azimuth on this date")
+
+// HourAngle returns the Sun's local hour angle in degrees at the given
+// instant, in the range [-180, 180). It is negative before and positive
+// after local apparent noon. The hour angle does not depend on latitude;
+// the parameter is accepted so the call matches the other position
+// functions.
+func HourAngle(t time.Time, latitude, longitude float64, opts ...Option) float64 {
+	o := newOptions(opts)
+	return o.hourAngle(t, o.longitude(longitude))
+}
+
+func hourAngle(t float64, instant time.Time, longitude float64) float64 {
+	_, RA, _, _ := sunCoordinates(t)
+	return localHourAngle(t, RA, instant, longitude)
+}
+
+// localHourAngle inverts step 8 of sunRiseSet: given the local mean time
+// T, the hour angle is H = T - RA + siderealOffset(t).
+func localHourAngle(t, RA float64, instant time.Time, longitude float64) float64 {
+	T := utHours(instant) + longitude/15.0
+
+	H := T - RA + siderealOffset(t)
+	return normalizeRange(H*15.0+180.0, 360) - 180.0
+}
+
+// Position is the location of the Sun in the sky as seen by an observer.
+// By default both angles are in degrees, azimuth is measured clockwise from
+// north and Altitude is the elevation above the horizon; see Options for
+// the alternatives. When the zenith convention is selected Altitude holds
+// the zenith angle.
+type Position struct {
+	Azimuth  float64
+	Altitude float64
+}
+
+// SunPosition returns the position of the Sun at the given instant.
+func SunPosition(t time.Time, latitude, longitude float64, opts ...Option) Position {
+	o := newOptions(opts)
+	longitude = o.longitude(longitude)
+	azimuth, elevation := o.sunAt(t, latitude, longitude)
+	return o.position(azimuth, elevation)
+}
+
+// Elevation returns the elevation of the Sun above the horizon at the
+// given instant. Pass WithRefraction to get the apparent rather than the
+// geometric elevation; the vertical angle and unit options also apply.
+func Elevation(t time.Time, latitude, longitude float64, opts ...Option) float64 {
+	return SunPosition(t, latitude, longitude, opts...).Altitude
+}
+
+// sunPosition returns the azimuth (north clockwise) and elevation of the
+// Sun in degrees.
+func sunPosition(t float64, instant time.Time, latitude, longitude float64) (azimuth, elevation float64) {
+	_, RA, sinDec, cosDec := sunCoordinates(t)
+	H := localHourAngle(t, RA, instant, longitude)
+	return horizontal(H, sinDec, cosDec, latitude)
+}
+
+// horizontal converts an hour angle and declination into azimuth (north
+// clockwise) and elevation for an observer at latitude.
+// sin(alt) = sinDec * sin(latitude) + cosDec * cos(latitude) * cos(H)
+// az = atan2(-sin(H) * cosDec, sinDec * cos(latitude) - cosDec * sin(latitude) * cos(H))
+func horizontal(H, sinDec, cosDec, latitude float64) (azimuth, elevation float64) {
+	sinAlt := sinDec*degreeSin(latitude) + cosDec*degreeCos(latitude)*degreeCos(H)
+	elevation = degreeAsin(math.Max(-1.0, math.Min(1.0, sinAlt)))
+
+	y := -degreeSin(H) * cosDec
+	x := sinDec*degreeCos(latitude) - cosDec*degreeSin(latitude)*degreeCos(H)
+	azimuth = normalizeRange(radianToDegree(math.Atan2(y, x)), 360)
+
+	return azimuth, elevation
+}
+
+// TimeAtAzimuth returns the first time on the civil date of date when the
+// Sun passes the given azimuth, in the time zone of date. The azimuth is
+// interpreted using the azimuth convention and units of opts. The Sun may
+// be below the horizon at the returned time; compare with SunPosition if
+// that matters. It returns ErrAzimuthNotReached if the Sun does not pass
+// the azimuth that day.
+func TimeAtAzimuth(date time.Time, latitude, longitude, azimuth float64, opts ...Option) (time.Time, error) {
+	o := newOptions(opts)
+	longitude = o.longitude(longitude)
+	target := o.azimuth(azimuth)
+
+	start, end := o.civilDay(date)
+	day := newDayCoordinates(start, end)
+
+	offset := func(t time.Time) float64 {
+		a, _ := day.position(t, latitude, longitude)
+		return angleDifference(a, target)
+	}
+
+	const step = 10 * time.Minute
+	a, da := start, offset(start)
+	for a.Before(end) {
+		b := a.Add(step)
+		if b.After(end) {
+			b = end
+		}
+		db := offset(b)
+
+		// a sign change far from 0 is the wrap on the opposite bearing
+		if (da < 0) != (db < 0) && math.Abs(da) < 90.0 && math.Abs(db) < 90.0 {
+			for b.Sub(a) > time.Second {
+				mid := a.Add(b.Sub(a) / 2)
+				dm := offset(mid)
+				if (da < 0) != (dm < 0) {
+					b = mid
+				} else {
+					a, da = mid, dm
+				}
+			}
+			return a.Round(time.Second), nil
+		}
+		a, da = b, db
+	}
+
+	return time.Time{}, ErrAzimuthNotReached
+}
+
+// SunriseAzimuth returns the azimuth at which the Sun rises on the civil
+// date of date, following the azimuth options. It returns the error of
+// SunRiseOn when there is no sunrise.
+func SunriseAzimuth(date time.Time, latitude, longitude float64, opts ...Option) (float64, error) {
+	rise, err := SunRiseOn(date, latitude, longitude, opts...)
+	if err != nil {
+		return 0, err
+	}
+	return SunPosition(rise, latitude, longitude, opts...).Azimuth, nil
+}
+
+// SunsetAzimuth returns the azimuth at which the Sun sets on the civil date
+// of date, following the azimuth options. It returns the error of SunSetOn
+// when there is no sunset.
+func SunsetAzimuth(date time.Time, latitude, longitude float64, opts ...Option) (float64, error) {
+	set, err := SunSetOn(date, latitude, longitude, opts...)
+	if err != nil {
+		return 0, err
+	}
+	return SunPosition(set, latitude, longitude, opts...).Azimuth, nil
+}
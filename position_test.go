@@ -0,0 +1,143 @@
+package sunevent
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// near reports whether got is within tolerance of want.
+func near(got, want, tolerance float64) bool {
+	return math.Abs(got-want) <= tolerance
+}
+
+func TestHourAngle(t *testing.T) {
+	const latitude, longitude = 22.63, 120.30
+	noon, err := SolarNoonOn(time.Date(2026, time.June, 21, 12, 0, 0, 0, time.UTC), latitude, longitude)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		t    time.Time
+		want float64
+	}{
+		{noon, 0},
+		{noon.Add(-6 * time.Hour), -90},
+		{noon.Add(2 * time.Hour), 30},
+		{noon.Add(12*time.Hour - time.Minute), 179.75},
+		{noon.Add(12*time.Hour + time.Minute), -179.75},
+	}
+	for _, tt := range tests {
+		if got := HourAngle(tt.t, latitude, longitude); !near(got, tt.want, 0.1) {
+			t.Errorf("HourAngle(noon%+v) = %.3f, want %.2f", tt.t.Sub(noon), got, tt.want)
+		}
+	}
+
+	// the hour angle grows by the difference of longitude and does not
+	// depend on latitude
+	at := HourAngle(noon, latitude, longitude)
+	if got := HourAngle(noon, -60, longitude+15) - at; !near(got, 15, 1e-9) {
+		t.Errorf("HourAngle 15° east is %.9f° ahead, want 15°", got)
+	}
+}
+
+func TestTimeAtAzimuth(t *testing.T) {
+	taipei := time.FixedZone("CST", 8*3600)
+	const latitude, longitude = 22.63, 120.30
+
+	december := time.Date(2026, time.December, 21, 12, 0, 0, 0, taipei)
+	noon, err := SolarNoonOn(december, latitude, longitude)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := TimeAtAzimuth(december, latitude, longitude, 180)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := got.Sub(noon); d > time.Minute || d < -time.Minute {
+		t.Errorf("Sun due south at %s, want solar noon %s", got, noon)
+	}
+	south, err := TimeAtAzimuth(december, latitude, longitude, 0, WithAzimuth(SouthClockwise))
+	if err != nil || !south.Equal(got) {
+		t.Errorf("azimuth 0 from south = %s, %v, want %s", south, err, got)
+	}
+
+	for _, azimuth := range []float64{120, 150, 210, 240} {
+		got, err := TimeAtAzimuth(december, latitude, longitude, azimuth)
+		if err != nil {
+			t.Errorf("TimeAtAzimuth(%v°): %v", azimuth, err)
+			continue
+		}
+		if a := SunPosition(got, latitude, longitude).Azimuth; !near(a, azimuth, 0.05) {
+			t.Errorf("TimeAtAzimuth(%v°) = %s, where the azimuth is %.3f°", azimuth, got, a)
+		}
+	}
+
+	// in June the Sun culminates north of the zenith, just inside the
+	// tropic, and never bears south
+	june := time.Date(2026, time.June, 21, 12, 0, 0, 0, taipei)
+	if _, err := TimeAtAzimuth(june, latitude, longitude, 180); err != ErrAzimuthNotReached {
+		t.Errorf("TimeAtAzimuth(180°) in June error = %v, want ErrAzimuthNotReached", err)
+	}
+}
+
+func TestElevation(t *testing.T) {
+	const latitude, longitude = 45.0, 7.0
+	at := time.Date(2026, time.March, 20, 7, 0, 0, 0, time.UTC)
+	geometric := Elevation(at, latitude, longitude)
+	if p := SunPosition(at, latitude, longitude); geometric != p.Altitude {
+		t.Errorf("Elevation = %v, want the altitude of SunPosition %v", geometric, p.Altitude)
+	}
+
+	// refraction lifts the Sun most near the horizon
+	apparent := Elevation(at, latitude, longitude, WithRefraction())
+	if lift := apparent - geometric; lift <= 0 || lift > 0.6 {
+		t.Errorf("refraction at %.2f° = %.3f°", geometric, lift)
+	}
+	noon, _ := SolarNoonOn(at, latitude, longitude)
+	if lift := Elevation(noon, latitude, longitude, WithRefraction()) - Elevation(noon, latitude, longitude); lift <= 0 || lift >= apparent-geometric {
+		t.Errorf("refraction at noon = %.4f°, want less than near the horizon", lift)
+	}
+
+	zenith := Elevation(at, latitude, longitude, WithVerticalAngle(ZenithAngle))
+	if !near(zenith, 90-geometric, 1e-9) {
+		t.Errorf("zenith angle = %v, want %v", zenith, 90-geometric)
+	}
+}
+
+func TestSunriseSunsetAzimuth(t *testing.T) {
+	const latitude, longitude = 45.0, 7.0
+	tests := []struct {
+		month     time.Month
+		day       int
+		rise, set float64
+	}{
+		// due east and west at the equinox
+		{time.March, 20, 90, 270},
+		// about 34° north of east and west at the June solstice
+		{time.June, 21, 56, 304},
+		{time.December, 21, 124, 236},
+	}
+	for _, tt := range tests {
+		date := time.Date(2026, tt.month, tt.day, 12, 0, 0, 0, time.UTC)
+		rise, err := SunriseAzimuth(date, latitude, longitude)
+		if err != nil {
+			t.Fatal(err)
+		}
+		set, err := SunsetAzimuth(date, latitude, longitude)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !near(rise, tt.rise, 1.5) || !near(set, tt.set, 1.5) {
+			t.Errorf("%s %d: rise %v, set %v, want %v, %v", tt.month, tt.day, rise, set, tt.rise, tt.set)
+		}
+		if !near(rise+set, 360, 0.5) {
+			t.Errorf("%s %d: rise %v and set %v are not symmetric about north", tt.month, tt.day, rise, set)
+		}
+	}
+
+	// no sunrise in the polar night
+	if _, err := SunriseAzimuth(time.Date(2026, time.December, 21, 12, 0, 0, 0, time.UTC), 78.22, 15.65); err == nil {
+		t.Error("sunrise azimuth in the polar night")
+	}
+}
@@ -0,0 +1,242 @@
+package sunevent
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Profile is a named bundle of settings for a use case: the events and
+// extra altitudes of interest, how times are rounded, the fields to
+// output and the calculation options. Profiles are plain data and can be
+// stored as JSON; Profiles lists the built-in ones.
+type Profile struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+
+	// Events are the standard events to compute.
+	Events []EventType `json:"events"`
+
+	// Angles are extra events at other altitudes of the Sun.
+	Angles []ProfileAngle `json:"angles,omitempty"`
+
+	// Round is the precision of the reported times in seconds; 0 leaves
+	// them unrounded.
+	Round int `json:"round_seconds,omitempty"`
+
+	// Fields selects the output of Record, in order; see ProfileFields.
+	Fields []string `json:"fields"`
+
+	// Refraction names the refraction model: "none", "standard" or
+	// "bennett". Empty keeps the package default.
+	Refraction string `json:"refraction,omitempty"`
+
+	// Algorithm names the algorithm, as reported by Algorithms. Empty
+	// keeps the package default.
+	Algorithm string `json:"algorithm,omitempty"`
+}
+
+// ProfileAngle is an event at which the centre of the Sun reaches
+// Altitude degrees, rising or setting.
+type ProfileAngle struct {
+	Name     string  `json:"name"`
+	Altitude float64 `json:"altitude"`
+	Rising   bool    `json:"rising"`
+}
+
+// ProfileTime is a moment computed for a profile, with the position of the
+// Sun at that moment.
+type ProfileTime struct {
+	Name      string
+	Time      time.Time
+	Azimuth   float64
+	Elevation float64
+}
+
+// ProfileFields lists the output fields a profile can select:
+//
+//	time       RFC 3339 time in the time zone of the date
+//	clock      wall-clock time, with seconds if rounded below a minute
+//	utc        UTC wall-clock time followed by Z, as used in aviation
+//	azimuth    azimuth of the Sun in degrees
+//	elevation  elevation of the Sun in degrees
+var ProfileFields = []string{"time", "clock", "utc", "azimuth", "elevation"}
+
+// Profiles lists the built-in profiles. Applications may append their own
+// or load them from JSON; ProfileByName searches this list.
+var Profiles = []Profile{
+	{
+		Name:        "photography",
+		Description: "Golden and blue hours around sunrise and sunset",
+		Events:      []EventType{CivilDawn, Sunrise, Sunset, CivilDusk},
+		Angles: []ProfileAngle{
+			{Name: "blue_hour_end", Altitude: -4, Rising: true},
+			{Name: "golden_hour_end", Altitude: 6, Rising: true},
+			{Name: "golden_hour_start", Altitude: 6, Rising: false},
+			{Name: "blue_hour_start", Altitude: -4, Rising: false},
+		},
+		Round:      60,
+		Fields:     []string{"clock", "azimuth"},
+		Refraction: "standard",
+	},
+	{
+		Name:        "aviation",
+		Description: "Sunrise, sunset and civil twilight in UTC",
+		Events:      []EventType{CivilDawn, Sunrise, Sunset, CivilDusk},
+		Round:       60,
+		Fields:      []string{"utc"},
+		Refraction:  "standard",
+		Algorithm:   "noaa",
+	},
+	{
+		Name:        "religious-mwl",
+		Description: "Prayer times of the Muslim World League: fajr at 18°, isha at 17°",
+		Events:      []EventType{Sunrise, SolarNoon, Sunset},
+		Angles: []ProfileAngle{
+			{Name: "fajr", Altitude: -18, Rising: true},
+			{Name: "isha", Altitude: -17, Rising: false},
+		},
+		Round:      60,
+		Fields:     []string{"clock"},
+		Refraction: "standard",
+		Algorithm:  "noaa",
+	},
+	{
+		Name:        "solar-engineering",
+		Description: "Sun path endpoints and transit with geometric positions",
+		Events:      []EventType{Sunrise, SolarNoon, Sunset},
+		Round:       1,
+		Fields:      []string{"time", "azimuth", "elevation"},
+		Refraction:  "none",
+		Algorithm:   "noaa",
+	},
+	{
+		Name:        "home-automation",
+		Description: "Civil twilight and sunrise and sunset for lights and blinds",
+		Events:      []EventType{CivilDawn, Sunrise, Sunset, CivilDusk},
+		Round:       60,
+		Fields:      []string{"clock"},
+		Refraction:  "standard",
+	},
+}
+
+// ProfileByName returns the profile in Profiles named name.
+func ProfileByName(name string) (Profile, bool) {
+	for _, p := range Profiles {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return Profile{}, false
+}
+
+// Validate reports an unknown field, refraction model or algorithm name.
+func (p Profile) Validate() error {
+	_, err := p.Options()
+	return err
+}
+
+// Options returns the calculation options of the profile.
+func (p Profile) Options() ([]Option, error) {
+	for _, f := range p.Fields {
+		if !containsString(ProfileFields, f) {
+			return nil, fmt.Errorf("sunevent: profile %q: unknown field %q", p.Name, f)
+		}
+	}
+
+	var opts []Option
+	switch p.Refraction {
+	case "":
+	case "none":
+		opts = append(opts, WithRefractionModel(NoRefraction{}))
+	case "standard":
+		opts = append(opts, WithRefractionModel(StandardRefraction{}))
+	case "bennett":
+		opts = append(opts, WithRefractionModel(BennettRefraction{}))
+	default:
+		return nil, fmt.Errorf("sunevent: profile %q: unknown refraction model %q", p.Name, p.Refraction)
+	}
+
+	if p.Algorithm != "" {
+		a, ok := algorithmByName(p.Algorithm)
+		if !ok {
+			return nil, fmt.Errorf("sunevent: profile %q: unknown algorithm %q", p.Name, p.Algorithm)
+		}
+		opts = append(opts, WithAlgorithm(a))
+	}
+	return opts, nil
+}
+
+// Day returns the events and angles of the profile on the civil date of
+// date, in the time zone of date, in chronological order. Events that do
+// not happen that day are left out. The options are applied after those
+// of the profile.
+func (p Profile) Day(date time.Time, latitude, longitude float64, opts ...Option) ([]ProfileTime, error) {
+	popts, err := p.Options()
+	if err != nil {
+		return nil, err
+	}
+	opts = append(popts, opts...)
+
+	var times []ProfileTime
+	add := func(name string, t time.Time) {
+		if p.Round > 0 {
+			t = t.Round(time.Duration(p.Round) * time.Second)
+		}
+		pos := SunPosition(t, latitude, longitude, opts...)
+		times = append(times, ProfileTime{Name: name, Time: t, Azimuth: pos.Azimuth, Elevation: pos.Altitude})
+	}
+
+	for _, e := range p.Events {
+		if t, err := e.On(date, latitude, longitude, opts...); err == nil {
+			add(e.String(), t)
+		}
+	}
+	for _, a := range p.Angles {
+		if t, err := TimeAtAltitude(date, latitude, longitude, a.Altitude, a.Rising, opts...); err == nil {
+			add(a.Name, t)
+		}
+	}
+
+	sort.SliceStable(times, func(i, j int) bool {
+		return times[i].Time.Before(times[j].Time)
+	})
+	return times, nil
+}
+
+// Record returns the name of t and the fields selected by the profile, in
+// a form ready for encoding as JSON.
+func (p Profile) Record(t ProfileTime) map[string]interface{} {
+	r := map[string]interface{}{"name": t.Name}
+	for _, f := range p.Fields {
+		switch f {
+		case "time":
+			r[f] = t.Time.Format(time.RFC3339)
+		case "clock":
+			r[f] = t.Time.Format(p.clockLayout())
+		case "utc":
+			r[f] = t.Time.UTC().Format(p.clockLayout()) + "Z"
+		case "azimuth":
+			r[f] = t.Azimuth
+		case "elevation":
+			r[f] = t.Elevation
+		}
+	}
+	return r
+}
+
+func (p Profile) clockLayout() string {
+	if p.Round > 0 && p.Round%60 == 0 {
+		return "15:04"
+	}
+	return "15:04:05"
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
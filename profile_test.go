@@ -0,0 +1,82 @@
+package sunevent
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestProfiles(t *testing.T) {
+	for _, p := range Profiles {
+		if err := p.Validate(); err != nil {
+			t.Errorf("built-in profile %s: %v", p.Name, err)
+		}
+		if got, ok := ProfileByName(p.Name); !ok || got.Name != p.Name {
+			t.Errorf("ProfileByName(%q) = %v, %v", p.Name, got.Name, ok)
+		}
+	}
+	if _, ok := ProfileByName("astrology"); ok {
+		t.Error("found an unknown profile")
+	}
+
+	for _, p := range []Profile{
+		{Name: "field", Fields: []string{"colour"}},
+		{Name: "refraction", Refraction: "heavy"},
+		{Name: "algorithm", Algorithm: "vsop"},
+	} {
+		if err := p.Validate(); err == nil {
+			t.Errorf("profile %s validated", p.Name)
+		}
+	}
+}
+
+func TestProfileDay(t *testing.T) {
+	taipei := time.FixedZone("CST", 8*3600)
+	date := time.Date(2026, time.March, 20, 12, 0, 0, 0, taipei)
+	p, _ := ProfileByName("photography")
+
+	times, err := p.Day(date, 22.63, 120.30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := []string{"civil_dawn", "blue_hour_end", "sunrise", "golden_hour_end", "golden_hour_start", "sunset", "blue_hour_start", "civil_dusk"}
+	if len(times) != len(names) {
+		t.Fatalf("%d times, want %d", len(times), len(names))
+	}
+	for i, pt := range times {
+		if pt.Name != names[i] {
+			t.Errorf("time %d = %s, want %s", i, pt.Name, names[i])
+		}
+		if pt.Time.Second() != 0 || pt.Time.Location() != taipei {
+			t.Errorf("%s at %s, want whole minutes in the time zone of the date", pt.Name, pt.Time)
+		}
+	}
+	if e := times[3].Elevation; !near(e, 6, 0.3) {
+		t.Errorf("golden hour ends at an elevation of %v", e)
+	}
+
+	r := p.Record(times[2])
+	if r["name"] != "sunrise" || r["clock"] != times[2].Time.Format("15:04") || r["azimuth"] != times[2].Azimuth || len(r) != 3 {
+		t.Errorf("Record = %v", r)
+	}
+
+	aviation, _ := ProfileByName("aviation")
+	if r := aviation.Record(times[2]); r["utc"] != times[2].Time.UTC().Format("15:04")+"Z" {
+		t.Errorf("aviation Record = %v", r)
+	}
+}
+
+func TestProfileJSON(t *testing.T) {
+	in, _ := ProfileByName("religious-mwl")
+	b, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out Profile
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Name != in.Name || len(out.Angles) != 2 || out.Angles[0] != in.Angles[0] || out.Algorithm != "noaa" || out.Round != 60 {
+		t.Errorf("round trip = %+v, want %+v", out, in)
+	}
+}
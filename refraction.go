@@ -0,0 +1,134 @@
+package sunevent
+
+// sunSemidiameter is the mean apparent radius of the Sun in degrees.
+const sunSemidiameter = 16.0 / 60.0
+
+// RefractionModel computes how far atmospheric refraction lifts the
+// apparent position of the Sun.
+//
+// Selecting a model with WithRefractionModel also redefines sunrise and
+// sunset as the moments the upper limb of the Sun appears on the horizon,
+// as almanacs do: the centre of the Sun is then below the horizon by the
+// refraction at the horizon plus its semidiameter of 16′, 50′ in all with
+// StandardRefraction.
+type RefractionModel interface {
+	// Refraction returns the refraction in degrees for a body at the
+	// geometric elevation h in degrees.
+	Refraction(h float64) float64
+}
+
+// NoRefraction ignores the atmosphere, for geometric calculations.
+type NoRefraction struct{}
+
+// Refraction returns 0.
+func (NoRefraction) Refraction(h float64) float64 {
+	return 0
+}
+
+// StandardRefraction uses Saemundsson's formula, which gives the
+// conventional 34′ at the horizon for standard conditions (10 °C,
+// 1010 hPa).
+//
+// Setting Pressure adjusts the refraction to the local air density, as
+// SPA does: refraction grows with pressure and falls with temperature, so
+// sunrise and sunset move by some 20 seconds in extreme climates, and by
+// minutes at high latitudes where the Sun rises at a shallow angle.
+// With Pressure 0 the standard conditions apply and Temperature is
+// ignored.
+type StandardRefraction struct {
+	Temperature float64 // °C
+	Pressure    float64 // hPa
+}
+
+// Refraction implements RefractionModel.
+func (m StandardRefraction) Refraction(h float64) float64 {
+	return refraction(h) * airDensity(m.Temperature, m.Pressure)
+}
+
+// BennettRefraction uses Bennett's formula, which is accurate to 0.07′
+// for standard conditions and gives 34.5′ at the horizon. It is stated for
+// the apparent elevation, so it is inverted by iteration. Temperature and
+// Pressure adjust it as for StandardRefraction.
+type BennettRefraction struct {
+	Temperature float64 // °C
+	Pressure    float64 // hPa
+}
+
+// Refraction implements RefractionModel.
+func (m BennettRefraction) Refraction(h float64) float64 {
+	if h < -1.0 {
+		return 0
+	}
+	k := airDensity(m.Temperature, m.Pressure)
+	r := 0.0
+	for i := 0; i < 4; i++ {
+		r = bennett(h+r) * k
+	}
+	return r
+}
+
+// WithRefractionModel selects the refraction model for apparent positions,
+// as WithRefraction does, and for sunrise and sunset; see RefractionModel.
+func WithRefractionModel(m RefractionModel) Option {
+	return func(o *Options) {
+		o.Refraction = true
+		o.RefractionModel = m
+	}
+}
+
+// airDensity returns the ratio of refraction at temperature (°C) and
+// pressure (hPa) to that at standard conditions, or 1 if pressure is 0
+// (Meeus, chapter 16).
+// k = P / 1010 * 283 / (273 + T)
+func airDensity(temperature, pressure float64) float64 {
+	if pressure == 0 {
+		return 1
+	}
+	return pressure / 1010.0 * 283.0 / (273.0 + temperature)
+}
+
+// refraction returns the refraction in degrees for a body at the geometric
+// elevation h, using Saemundsson's formula for standard conditions
+// (10 °C, 1010 hPa).
+// R = 1.02 / tan(h + 10.3 / (h + 5.11)) arcminutes
+func refraction(h float64) float64 {
+	if h < -1.0 {
+		// well below the horizon the formula diverges; the Sun is not
+		// visible anyway
+		return 0
+	}
+	return 1.02 / degreeTan(h+10.3/(h+5.11)) / 60.0
+}
+
+// bennett returns the refraction in degrees for a body at the apparent
+// elevation h0.
+// R = 1 / tan(h0 + 7.31 / (h0 + 4.4)) arcminutes
+func bennett(h0 float64) float64 {
+	return 1.0 / degreeTan(h0+7.31/(h0+4.4)) / 60.0
+}
+
+// refraction returns the refraction in degrees at the geometric elevation
+// h with the model of o.
+func (o Options) refraction(h float64) float64 {
+	if o.RefractionModel == nil {
+		return refraction(h)
+	}
+	return o.RefractionModel.Refraction(h)
+}
+
+// sunriseAltitude returns the altitude of the centre of the Sun at sunrise
+// and sunset: 0 without a refraction model, and otherwise the geometric
+// altitude at which the upper limb appears on the horizon. Both are
+// lowered by the dip of the horizon for an elevated observer.
+func (o Options) sunriseAltitude() float64 {
+	if o.RefractionModel == nil {
+		return -o.dip()
+	}
+
+	// solve h + R(h) = 0 for the geometric altitude h of the horizon
+	h := 0.0
+	for i := 0; i < 8; i++ {
+		h = -o.RefractionModel.Refraction(h)
+	}
+	return h - sunSemidiameter - o.dip()
+}
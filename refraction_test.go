@@ -0,0 +1,97 @@
+package sunevent
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRefractionModels(t *testing.T) {
+	if r := (NoRefraction{}).Refraction(0); r != 0 {
+		t.Errorf("NoRefraction = %v", r)
+	}
+
+	for _, m := range []RefractionModel{StandardRefraction{}, BennettRefraction{}} {
+		// about 34′ at the horizon, half a degree at 0.5°, under 0.01° at
+		// 45° and nothing at the zenith
+		o := Options{RefractionModel: m}
+		h := -0.56
+		if r := m.Refraction(h); !near(r, 0.57, 0.02) {
+			t.Errorf("%T at the horizon = %v′", m, r*60)
+		}
+		if r := m.Refraction(45); !near(r, 0.016, 0.002) {
+			t.Errorf("%T at 45° = %v′", m, r*60)
+		}
+		if r := m.Refraction(90); !near(r, 0, 1e-4) {
+			t.Errorf("%T at the zenith = %v′", m, r*60)
+		}
+		if r := m.Refraction(-5); r != 0 {
+			t.Errorf("%T well below the horizon = %v′", m, r*60)
+		}
+
+		// the upper limb appears at the official zenith of 90°50′
+		if a := o.sunriseAltitude(); !near(a, -50.0/60, 0.02) {
+			t.Errorf("%T sunrise altitude = %v′", m, a*60)
+		}
+	}
+
+	// Bennett's formula is stated for the apparent elevation
+	h := 10.0
+	r := BennettRefraction{}.Refraction(h)
+	if !near(bennett(h+r), r, 1e-9) {
+		t.Errorf("Bennett at %v° = %v, not the refraction of the apparent %v°", h, r, h+r)
+	}
+}
+
+func TestWithRefractionModel(t *testing.T) {
+	date := time.Date(2026, time.March, 20, 12, 0, 0, 0, time.UTC)
+	const latitude, longitude = 45.0, 7.0
+
+	// the upper limb rises some four minutes before the centre crosses
+	// the geometric horizon, and sets as much later
+	rise, _ := SunRiseOn(date, latitude, longitude)
+	set, _ := SunSetOn(date, latitude, longitude)
+	apparentRise, _ := SunRiseOn(date, latitude, longitude, WithRefractionModel(StandardRefraction{}))
+	apparentSet, _ := SunSetOn(date, latitude, longitude, WithRefractionModel(StandardRefraction{}))
+	if d := rise.Sub(apparentRise); d < 4*time.Minute || d > 6*time.Minute {
+		t.Errorf("apparent sunrise %v earlier", d)
+	}
+	if d := apparentSet.Sub(set); d < 4*time.Minute || d > 6*time.Minute {
+		t.Errorf("apparent sunset %v later", d)
+	}
+
+	// and lifts the apparent positions
+	at := rise.Add(time.Hour)
+	p := SunPosition(at, latitude, longitude)
+	apparent := SunPosition(at, latitude, longitude, WithRefractionModel(BennettRefraction{}))
+	if d := apparent.Altitude - p.Altitude; !near(d, BennettRefraction{}.Refraction(p.Altitude), 1e-9) {
+		t.Errorf("apparent altitude lifted by %v°", d)
+	}
+	if none, _ := SunRiseOn(date, latitude, longitude, WithRefractionModel(NoRefraction{})); !near(rise.Sub(none).Minutes(), 1.5, 0.2) {
+		t.Errorf("sunrise of the upper limb without refraction %v earlier", rise.Sub(none))
+	}
+}
+
+func TestRefractionConditions(t *testing.T) {
+	if k := airDensity(10, 1010); !near(k, 1, 1e-12) {
+		t.Errorf("density at standard conditions = %v", k)
+	}
+	if k := airDensity(35, 0); k != 1 {
+		t.Errorf("density without a pressure = %v, want the standard 1", k)
+	}
+
+	// cold dense air bends light more than hot thin air
+	standard := StandardRefraction{}.Refraction(1)
+	cold := StandardRefraction{Temperature: -30, Pressure: 1040}.Refraction(1)
+	hot := BennettRefraction{Temperature: 40, Pressure: 700}.Refraction(1)
+	if !(cold > standard*1.15) || !(hot < BennettRefraction{}.Refraction(1)*0.7) {
+		t.Errorf("refraction at 1°: standard %v′, cold %v′, hot and high %v′", standard*60, cold*60, hot*60)
+	}
+
+	// which moves sunrise by tens of seconds at mid latitudes
+	date := time.Date(2026, time.January, 15, 12, 0, 0, 0, time.UTC)
+	rise, _ := SunRiseOn(date, 45, 7, WithRefractionModel(StandardRefraction{}))
+	coldRise, _ := SunRiseOn(date, 45, 7, WithRefractionModel(StandardRefraction{Temperature: -30, Pressure: 1040}))
+	if d := rise.Sub(coldRise); d < 10*time.Second || d > time.Minute {
+		t.Errorf("sunrise in the cold %v earlier", d)
+	}
+}
@@ -0,0 +1,130 @@
+// Package report renders a yearly sun almanac for one location as Markdown
+// or HTML.
+//
+// An almanac holds monthly tables of the daily events with the phases of
+// the Moon, a chart of daylight and twilight through the year, and
+// highlights such as the solstices and equinoxes:
+//
+//	loc, _ := time.LoadLocation("Asia/Taipei")
+//	a := report.New("Kaohsiung", 22.63, 120.30, 2026, loc)
+//	a.WriteHTML(os.Stdout)
+//
+// The HTML is a single self-contained page with an inline SVG chart and
+// print styles, so a PDF can be produced with an external tool such as a
+// headless browser or pandoc.
+package report
+
+import (
+	"io"
+	"time"
+
+	"github.com/cfw011566/sunevent"
+	"github.com/cfw011566/sunevent/internal/format"
+)
+
+// Almanac is a year of sun events for one location.
+type Almanac struct {
+	Name      string
+	Latitude  float64
+	Longitude float64
+	Year      int
+	Location  *time.Location
+
+	// Days holds every day of the year in order.
+	Days []sunevent.SunDay
+
+	// Highlights are notable moments of the year in chronological order.
+	Highlights []Highlight
+
+	// MoonPhases are the principal phases of the Moon in the year, in
+	// chronological order and in Location.
+	MoonPhases []sunevent.MoonPhaseEvent
+
+	opts []sunevent.Option
+}
+
+// Highlight is a notable moment of the year.
+type Highlight struct {
+	Name string
+	Time time.Time
+}
+
+// New computes the almanac of year at the given location, with times in
+// loc. The options are passed on to the calculations.
+func New(name string, latitude, longitude float64, year int, loc *time.Location, opts ...sunevent.Option) *Almanac {
+	a := &Almanac{
+		Name:      name,
+		Latitude:  latitude,
+		Longitude: longitude,
+		Year:      year,
+		Location:  loc,
+		opts:      opts,
+	}
+
+	a.Days = sunevent.YearCalendar(year, latitude, longitude, loc, opts...)
+
+	a.Highlights = append(seasons(year, loc), a.dayLengthExtremes()...)
+	sortHighlights(a.Highlights)
+
+	// the phases of the civil year, which may differ from the UTC year
+	for y := year - 1; y <= year+1; y++ {
+		for _, p := range sunevent.MoonPhases(y) {
+			if t := p.Time.In(loc); t.Year() == year {
+				a.MoonPhases = append(a.MoonPhases, sunevent.MoonPhaseEvent{Phase: p.Phase, Time: t})
+			}
+		}
+	}
+	return a
+}
+
+// Month returns the days of month m.
+func (a *Almanac) Month(m time.Month) []sunevent.SunDay {
+	var days []sunevent.SunDay
+	for _, d := range a.Days {
+		if d.Date.Month() == m {
+			days = append(days, d)
+		}
+	}
+	return days
+}
+
+// WriteMarkdown writes the almanac as a Markdown document.
+func (a *Almanac) WriteMarkdown(w io.Writer) error {
+	return markdownTemplate.Execute(w, a.view())
+}
+
+// WriteHTML writes the almanac as a self-contained HTML page.
+func (a *Almanac) WriteHTML(w io.Writer) error {
+	return htmlTemplate.Execute(w, a.view())
+}
+
+// dayLengthExtremes returns the longest and shortest days of the year.
+// Polar days and nights are left out, as they tie over many days.
+func (a *Almanac) dayLengthExtremes() []Highlight {
+	var longest, shortest *sunevent.SunDay
+	for i := range a.Days {
+		d := &a.Days[i]
+		if d.Type != sunevent.NormalDay || d.DayLength == 0 {
+			continue
+		}
+		if longest == nil || d.DayLength > longest.DayLength {
+			longest = d
+		}
+		if shortest == nil || d.DayLength < shortest.DayLength {
+			shortest = d
+		}
+	}
+	if longest == nil {
+		return nil
+	}
+	return []Highlight{
+		{Name: "Longest day (" + format.Length(longest.DayLength) + ")", Time: longest.SolarNoon},
+		{Name: "Shortest day (" + format.Length(shortest.DayLength) + ")", Time: shortest.SolarNoon},
+	}
+}
+
+// formatClock formats t as a wall-clock time, or a dash if the event does
+// not happen.
+func formatClock(t time.Time) string {
+	return format.Clock(t, "—")
+}
@@ -0,0 +1,106 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cfw011566/sunevent"
+)
+
+func TestNew(t *testing.T) {
+	taipei := time.FixedZone("CST", 8*3600)
+	a := New("Kaohsiung", 22.63, 120.30, 2026, taipei)
+
+	if len(a.Days) != 365 || len(a.Month(time.February)) != 28 {
+		t.Fatalf("%d days, %d in February", len(a.Days), len(a.Month(time.February)))
+	}
+
+	var names []string
+	for i, h := range a.Highlights {
+		names = append(names, h.Name)
+		if i > 0 && h.Time.Before(a.Highlights[i-1].Time) {
+			t.Errorf("highlight %q out of order", h.Name)
+		}
+		if h.Time.Location() != taipei {
+			t.Errorf("highlight %q in %v", h.Name, h.Time.Location())
+		}
+	}
+	got := strings.Join(names, ", ")
+	for _, want := range []string{"March equinox", "June solstice", "September equinox", "December solstice", "Longest day (13h ", "Shortest day (10h "} {
+		if !strings.Contains(got, want) {
+			t.Errorf("highlights %s, want %s", got, want)
+		}
+	}
+
+	// the longest day is near the June solstice
+	for _, h := range a.Highlights {
+		if strings.HasPrefix(h.Name, "Longest day") && h.Time.Month() != time.June {
+			t.Errorf("longest day on %s", h.Time)
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	taipei := time.FixedZone("CST", 8*3600)
+	a := New("Kaohsiung", 22.63, 120.30, 2026, taipei)
+	rise, _ := sunevent.SunRiseOn(time.Date(2026, time.March, 20, 12, 0, 0, 0, taipei), 22.63, 120.30)
+
+	var md bytes.Buffer
+	if err := a.WriteMarkdown(&md); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"# Kaohsiung sun almanac 2026", "## March", "| " + rise.Format("15:04") + " |"} {
+		if !strings.Contains(md.String(), want) {
+			t.Errorf("Markdown lacks %q", want)
+		}
+	}
+
+	var html bytes.Buffer
+	if err := a.WriteHTML(&html); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"<title>Kaohsiung sun almanac 2026</title>", "<svg", "<h2>December</h2>"} {
+		if !strings.Contains(html.String(), want) {
+			t.Errorf("HTML lacks %q", want)
+		}
+	}
+}
+
+func TestWritePolar(t *testing.T) {
+	// the events that do not happen are shown as dashes
+	a := New("Longyearbyen", 78.22, 15.65, 2026, time.FixedZone("CET", 3600))
+	var md bytes.Buffer
+	if err := a.WriteMarkdown(&md); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(md.String(), "| — |") {
+		t.Error("Markdown of the polar night lacks dashes")
+	}
+}
+
+func TestMoonPhases(t *testing.T) {
+	// the full moon of 2026 January 3 at 10:03 UTC is at 18:03 in Taipei
+	taipei := time.FixedZone("CST", 8*3600)
+	a := New("Kaohsiung", 22.63, 120.30, 2026, taipei)
+	if len(a.MoonPhases) == 0 {
+		t.Fatal("no moon phases")
+	}
+	for _, p := range a.MoonPhases {
+		if p.Time.Year() != 2026 || p.Time.Location() != taipei {
+			t.Errorf("%v at %s outside the civil year", p.Phase, p.Time)
+		}
+	}
+	if p := a.MoonPhases[0]; p.Phase != sunevent.FullMoon || p.Time.Format("01-02 15:04") != "01-03 18:03" {
+		t.Errorf("first phase %v at %s, want the full moon of 3 January", p.Phase, p.Time)
+	}
+
+	var md bytes.Buffer
+	if err := a.WriteMarkdown(&md); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(md.String(), "○ full moon 18:03") {
+		t.Error("Markdown lacks the full moon of 3 January")
+	}
+}
@@ -0,0 +1,36 @@
+package report
+
+import (
+	"sort"
+	"time"
+
+	"github.com/cfw011566/sunevent"
+)
+
+// seasons returns the equinoxes and solstices of year in loc, to the
+// minute.
+func seasons(year int, loc *time.Location) []Highlight {
+	march, september := sunevent.Equinoxes(year)
+	june, december := sunevent.Solstices(year)
+
+	var highlights []Highlight
+	for _, s := range []struct {
+		name string
+		t    time.Time
+	}{
+		{"March equinox", march},
+		{"June solstice", june},
+		{"September equinox", september},
+		{"December solstice", december},
+	} {
+		highlights = append(highlights, Highlight{Name: s.name, Time: s.t.Round(time.Minute).In(loc)})
+	}
+	return highlights
+}
+
+// sortHighlights sorts highlights chronologically.
+func sortHighlights(h []Highlight) {
+	sort.Slice(h, func(i, j int) bool {
+		return h[i].Time.Before(h[j].Time)
+	})
+}
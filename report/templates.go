@@ -0,0 +1,91 @@
+package report
+
+import (
+	htmltemplate "html/template"
+	"text/template"
+	"time"
+)
+
+var funcs = map[string]interface{}{
+	"clock": formatClock,
+	"date": func(t time.Time) string {
+		return t.Format("Monday 2 January")
+	},
+}
+
+var markdownTemplate = template.Must(template.New("markdown").Funcs(funcs).Parse(`# {{.Name}} sun almanac {{.Year}}
+
+{{.Position}}. Times are in {{.Location}}.
+
+## Highlights
+
+| | Date | Time |
+|---|---|---|
+{{range .Highlights}}| {{.Name}} | {{date .Time}} | {{clock .Time}} |
+{{end}}
+## Day length
+
+| Month | Average | |
+|---|---|---|
+{{range .Months}}| {{.Name}} | {{.Average}} | {{.Bar}} |
+{{end}}{{range .Months}}
+## {{.Name}}
+
+| Date | Civil dawn | Sunrise | Solar noon | Sunset | Civil dusk | Day length | |
+|---|---|---|---|---|---|---|---|
+{{range .Days}}| {{.Date}} | {{.Dawn}} | {{.Sunrise}} | {{.Noon}} | {{.Sunset}} | {{.Dusk}} | {{.Len}} | {{.Note}} |
+{{end}}{{end}}`))
+
+var htmlTemplate = htmltemplate.Must(htmltemplate.New("html").Funcs(funcs).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Name}} sun almanac {{.Year}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em auto; max-width: 60em; color: #222; }
+table { border-collapse: collapse; margin: 1em 0; font-variant-numeric: tabular-nums; }
+th, td { padding: 0.2em 0.7em; text-align: right; border-bottom: 1px solid #ddd; }
+th:first-child, td:first-child { text-align: left; }
+td.note { color: #888; text-align: left; }
+.bar { font-family: monospace; color: #e8a317; }
+svg text { font-size: 9px; fill: #555; }
+@media print {
+	body { margin: 0; max-width: none; }
+	section.month { page-break-before: always; }
+}
+</style>
+</head>
+<body>
+<h1>{{.Name}} sun almanac {{.Year}}</h1>
+<p>{{.Position}}. Times are in {{.Location}}.</p>
+
+<h2>Highlights</h2>
+<table>
+<tr><th></th><th>Date</th><th>Time</th></tr>
+{{range .Highlights}}<tr><td>{{.Name}}</td><td>{{date .Time}}</td><td>{{clock .Time}}</td></tr>
+{{end}}</table>
+
+<h2>Daylight through the year</h2>
+{{with .Chart}}<svg xmlns="http://www.w3.org/2000/svg" width="{{.Width}}" height="{{.Height}}" viewBox="0 0 {{.Width}} {{.Height}}">
+<rect x="{{.Left}}" y="{{.Top}}" width="{{.PlotWidth}}" height="{{.PlotHeight}}" fill="#1d2951"/>
+{{range .Columns}}{{$x := .X}}{{range .Twilight}}<rect x="{{$x}}" y="{{printf "%.1f" .Y}}" width="{{$.Chart.DayWidth}}" height="{{printf "%.1f" .Height}}" fill="#6c7fb8"/>{{end}}{{range .Daylight}}<rect x="{{$x}}" y="{{printf "%.1f" .Y}}" width="{{$.Chart.DayWidth}}" height="{{printf "%.1f" .Height}}" fill="#f5c542"/>{{end}}
+{{end}}{{range .Hours}}<line x1="{{$.Chart.Left}}" x2="{{$.Chart.Width}}" y1="{{.Pos}}" y2="{{.Pos}}" stroke="#fff" stroke-opacity="0.3"/><text x="2" y="{{.Pos}}" dy="3">{{.Label}}</text>
+{{end}}{{range .Months}}<text x="{{.Pos}}" y="{{$.Chart.Height}}" dy="-5">{{.Label}}</text>
+{{end}}</svg>{{end}}
+
+<h2>Day length</h2>
+<table>
+<tr><th>Month</th><th>Average</th><th></th></tr>
+{{range .Months}}<tr><td>{{.Name}}</td><td>{{.Average}}</td><td class="bar">{{.Bar}}</td></tr>
+{{end}}</table>
+{{range .Months}}
+<section class="month">
+<h2>{{.Name}}</h2>
+<table>
+<tr><th>Date</th><th>Civil dawn</th><th>Sunrise</th><th>Solar noon</th><th>Sunset</th><th>Civil dusk</th><th>Day length</th><th></th></tr>
+{{range .Days}}<tr><td>{{.Date}}</td><td>{{.Dawn}}</td><td>{{.Sunrise}}</td><td>{{.Noon}}</td><td>{{.Sunset}}</td><td>{{.Dusk}}</td><td>{{.Len}}</td><td class="note">{{.Note}}</td></tr>
+{{end}}</table>
+</section>
+{{end}}</body>
+</html>
+`))
@@ -0,0 +1,211 @@
+package report
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/cfw011566/sunevent"
+	"github.com/cfw011566/sunevent/internal/format"
+)
+
+// view is the almanac prepared for the templates.
+type view struct {
+	*Almanac
+	Position string
+	Months   []monthView
+	Chart    chart
+}
+
+type monthView struct {
+	Name    string
+	Average string
+	Bar     string
+	Days    []dayView
+}
+
+type dayView struct {
+	Date                                   string
+	Dawn, Sunrise, Noon, Sunset, Dusk, Len string
+	Note                                   string
+}
+
+// chart is a daylight chart: one column per day, with the hours of the day
+// running down.
+type chart struct {
+	Width, Height         float64
+	Left, Top             float64
+	PlotWidth, PlotHeight float64
+	DayWidth              float64
+	Columns               []column
+	Hours                 []gridLine
+	Months                []gridLine
+}
+
+type column struct {
+	X        float64
+	Twilight []segment
+	Daylight []segment
+}
+
+type segment struct {
+	Y, Height float64
+}
+
+type gridLine struct {
+	Pos   float64
+	Label string
+}
+
+const (
+	chartDayWidth   = 2.0
+	chartHourHeight = 10.0
+	chartLeft       = 40.0
+	chartTop        = 10.0
+	barWidth        = 24
+)
+
+func (a *Almanac) view() view {
+	v := view{Almanac: a, Position: formatPosition(a.Latitude, a.Longitude)}
+	for m := time.January; m <= time.December; m++ {
+		v.Months = append(v.Months, monthSummary(m, a.Month(m), a.MoonPhases))
+	}
+	v.Chart = a.chart()
+	return v
+}
+
+// moonSymbols mark the principal phases of the Moon in the day notes.
+var moonSymbols = map[sunevent.MoonPhaseName]string{
+	sunevent.NewMoon:      "● new moon",
+	sunevent.FirstQuarter: "◐ first quarter",
+	sunevent.FullMoon:     "○ full moon",
+	sunevent.LastQuarter:  "◑ last quarter",
+}
+
+func monthSummary(m time.Month, days []sunevent.SunDay, phases []sunevent.MoonPhaseEvent) monthView {
+	mv := monthView{Name: m.String()}
+	var total time.Duration
+	for _, d := range days {
+		total += d.DayLength
+		dv := dayView{
+			Date:    d.Date.Format("Mon 2"),
+			Dawn:    formatClock(d.CivilDawn),
+			Sunrise: formatClock(d.Sunrise),
+			Noon:    formatClock(d.SolarNoon),
+			Sunset:  formatClock(d.Sunset),
+			Dusk:    formatClock(d.CivilDusk),
+			Len:     format.Length(d.DayLength),
+		}
+		var notes []string
+		switch {
+		case d.Type == sunevent.PolarDay:
+			notes = append(notes, "midnight sun")
+		case d.Type == sunevent.PolarNight:
+			notes = append(notes, "polar night")
+		case d.Inverted:
+			notes = append(notes, "sunset before sunrise")
+		}
+		for _, p := range phases {
+			if sameDate(p.Time, d.Date) {
+				notes = append(notes, moonSymbols[p.Phase]+" "+formatClock(p.Time))
+			}
+		}
+		dv.Note = strings.Join(notes, ", ")
+		mv.Days = append(mv.Days, dv)
+	}
+	if len(days) > 0 {
+		average := total / time.Duration(len(days))
+		mv.Average = format.Length(average)
+		n := int(math.Round(float64(average) / float64(24*time.Hour) * barWidth))
+		mv.Bar = strings.Repeat("█", n) + strings.Repeat("░", barWidth-n)
+	}
+	return mv
+}
+
+func (a *Almanac) chart() chart {
+	c := chart{
+		Left:       chartLeft,
+		Top:        chartTop,
+		PlotWidth:  chartDayWidth * float64(len(a.Days)),
+		PlotHeight: 24 * chartHourHeight,
+		DayWidth:   chartDayWidth,
+	}
+	c.Width = c.Left + c.PlotWidth + 10
+	c.Height = c.Top + c.PlotHeight + 20
+	for h := 0; h <= 24; h += 3 {
+		c.Hours = append(c.Hours, gridLine{Pos: chartTop + float64(h)*chartHourHeight, Label: fmt.Sprintf("%02d:00", h)})
+	}
+
+	for i, d := range a.Days {
+		x := chartLeft + chartDayWidth*float64(i)
+		if d.Date.Day() == 1 {
+			c.Months = append(c.Months, gridLine{Pos: x, Label: d.Date.Format("Jan")})
+		}
+
+		col := column{X: x}
+		switch d.Type {
+		case sunevent.PolarDay:
+			col.Daylight = []segment{hourSegment(0, 24)}
+		case sunevent.NormalDay:
+			col.Daylight = a.spans(d.Sunrise, d.Sunset)
+		}
+		if d.CivilDawn.IsZero() && d.CivilDusk.IsZero() {
+			// no civil twilight boundary: the Sun stays either above or
+			// below -6° all day
+			if sunevent.Elevation(d.SolarNoon, a.Latitude, a.Longitude, a.opts...) > -6 {
+				col.Twilight = []segment{hourSegment(0, 24)}
+			}
+		} else {
+			col.Twilight = a.spans(d.CivilDawn, d.CivilDusk)
+		}
+		c.Columns = append(c.Columns, col)
+	}
+	return c
+}
+
+// spans returns the chart segments of the period from start to end within
+// one day. A missing start or end extends the period to the edge of the
+// day, and an end before the start wraps around midnight.
+func (a *Almanac) spans(start, end time.Time) []segment {
+	from, to := 0.0, 24.0
+	if !start.IsZero() {
+		from = a.hours(start)
+	}
+	if !end.IsZero() {
+		to = a.hours(end)
+	}
+	if to < from {
+		return []segment{hourSegment(0, to), hourSegment(from, 24)}
+	}
+	return []segment{hourSegment(from, to)}
+}
+
+// hours returns the wall-clock time of t in hours.
+func (a *Almanac) hours(t time.Time) float64 {
+	t = t.In(a.Location)
+	return float64(t.Hour()) + float64(t.Minute())/60
+}
+
+func hourSegment(from, to float64) segment {
+	return segment{Y: chartTop + from*chartHourHeight, Height: (to - from) * chartHourHeight}
+}
+
+// formatPosition formats a latitude and longitude with hemisphere letters.
+func formatPosition(latitude, longitude float64) string {
+	ns, ew := "N", "E"
+	if latitude < 0 {
+		ns = "S"
+	}
+	if longitude < 0 {
+		ew = "W"
+	}
+	return fmt.Sprintf("%.4f° %s, %.4f° %s", math.Abs(latitude), ns, math.Abs(longitude), ew)
+}
+
+// sameDate reports whether a and b fall on the same civil date in the time
+// zone of b.
+func sameDate(a, b time.Time) bool {
+	a = a.In(b.Location())
+	return a.Year() == b.Year() && a.YearDay() == b.YearDay()
+}
@@ -0,0 +1,554 @@
+package sunevent
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNoEvent is returned by TimerUntil when the event does not happen
+// within a year.
+var ErrNoEvent = errors.New("sunevent: the event does not happen within a year")
+
+// Scheduler calls functions at the solar events of a location, every day,
+// for home automation and similar services. Register the functions with
+// On or the shorthands such as OnSunset, with Do at a Trigger offset from
+// an event or with Cron, then call Start; the scheduler
+// works out the next occurrence of each event, sleeps until the earliest,
+// calls the functions that are due and schedules their next occurrence.
+// Each call runs in its own goroutine, so a slow function does not delay
+// the others.
+//
+// Events are computed in the local time zone. An event that does not
+// happen within a year, as some twilights near the poles, is not
+// scheduled again.
+//
+// Timers in Go follow the monotonic clock, which stands still while the
+// machine is suspended and ignores changes to the system clock, so a
+// timer set for sunset can go off hours late or fire at the wrong wall
+// time. The scheduler therefore sleeps at most schedulerPoll at a time,
+// compares the wall clock with the monotonic clock on each wake and, when
+// they disagree, as after a suspend, a manual change of the clock or an
+// NTP step, recomputes every occurrence from the new time. Occurrences
+// more than maxLateness in the past are skipped rather than fired late.
+// Daylight saving changes do not move the instants of events.
+//
+// To shut down, cancel the context given to StartContext or call Stop,
+// then call Wait to let the functions in progress finish. With WithStore
+// and WithCatchUp, an occurrence missed while the program was not running
+// can be fired when it starts again.
+//
+// One scheduler can serve many locations, such as a fleet of greenhouses
+// in different cities: For returns a view that registers functions for
+// another location, and every view shares the one goroutine and timer.
+//
+// The methods of a Scheduler are safe for concurrent use.
+type Scheduler struct {
+	sky    Sky
+	prefix string
+
+	*scheduler
+}
+
+// scheduler is the state shared by a Scheduler and its views from For.
+type scheduler struct {
+	store   SchedulerStore
+	catchUp time.Duration
+
+	// running counts the loop and the calls in progress
+	running sync.WaitGroup
+
+	mu      sync.Mutex
+	rules   ruleHeap
+	started bool
+	stop    chan struct{}
+	wake    chan struct{}
+}
+
+// rule is a registered function with the time it is next due and the
+// event it is due at, if any; at is zero when it is not due within a year.
+// after returns the first occurrence due after an instant. due is the
+// time of the occurrence and at the time the function is called, which
+// differ by a random amount of up to jitter. key identifies the rule in
+// the store, and missed marks an occurrence to catch up. index is the
+// position of the rule in the heap.
+type rule struct {
+	key    string
+	after  func(time.Time) (Event, time.Time)
+	fire   func(Event)
+	jitter time.Duration
+	next   Event
+	due    time.Time
+	at     time.Time
+	missed bool
+	index  int
+}
+
+// RuleOption configures a function registered with a Scheduler.
+type RuleOption func(*rule)
+
+// WithJitter calls the function at a random moment up to d before or
+// after each occurrence, so that the lights of a neighbourhood do not all
+// switch at the same second, as presence simulation requires. The moment
+// is drawn afresh for every occurrence.
+func WithJitter(d time.Duration) RuleOption {
+	return func(r *rule) {
+		if d > 0 {
+			r.jitter = d
+		}
+	}
+}
+
+// jitterRand is seeded from the clock so that every process, even of the
+// same program, draws different moments.
+var (
+	jitterMu   sync.Mutex
+	jitterRand = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+// randomJitter returns a random duration in [-d, d].
+func randomJitter(d time.Duration) time.Duration {
+	jitterMu.Lock()
+	defer jitterMu.Unlock()
+	return time.Duration(jitterRand.Int63n(2*int64(d)+1)) - d
+}
+
+// ruleHeap orders rules by the time they are next due, with the rules
+// that are not due at all last, so the loop only looks at the first.
+type ruleHeap []*rule
+
+func (h ruleHeap) Len() int { return len(h) }
+
+func (h ruleHeap) Less(i, j int) bool {
+	if h[i].at.IsZero() || h[j].at.IsZero() {
+		return !h[i].at.IsZero()
+	}
+	return h[i].at.Before(h[j].at)
+}
+
+func (h ruleHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *ruleHeap) Push(x interface{}) {
+	r := x.(*rule)
+	r.index = len(*h)
+	*h = append(*h, r)
+}
+
+func (h *ruleHeap) Pop() interface{} {
+	old := *h
+	r := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return r
+}
+
+// NewScheduler returns a scheduler for the events of sky, usually an
+// Observer, or nil for a scheduler used only through For. It does nothing
+// until Start is called.
+func NewScheduler(sky Sky, opts ...SchedulerOption) *Scheduler {
+	s := &Scheduler{
+		sky: sky,
+		scheduler: &scheduler{
+			stop: make(chan struct{}),
+			wake: make(chan struct{}, 1),
+		},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// For returns a view of the scheduler that registers functions for the
+// events of sky, another location. The view shares the loop, the store and
+// the options of s, so Start, Stop and Wait on either act on both. name
+// distinguishes the rules of the location in the store, as in
+// "greenhouse-2/sunset"; it should be unique among the views.
+func (s *Scheduler) For(name string, sky Sky) *Scheduler {
+	return &Scheduler{sky: sky, prefix: name + "/", scheduler: s.scheduler}
+}
+
+// On registers f to be called at every event of type e. It may be called
+// before or after Start.
+func (s *Scheduler) On(e EventType, f func(), opts ...RuleOption) {
+	s.Do(At(e, 0), f, opts...)
+}
+
+// Do registers f to be called at every occurrence of the trigger, as in
+// s.Do(At(Sunset, -30*time.Minute), f). It may be called before or after
+// Start. Options such as WithJitter apply to this function only.
+func (s *Scheduler) Do(t Trigger, f func(), opts ...RuleOption) {
+	s.add(&rule{key: t.String(), after: s.triggerAfter(t), fire: func(Event) { f() }}, opts)
+}
+
+// Cron registers f to be called at the times selected by the extended
+// cron expression spec, such as "@sunset-20m" or "30 @civil_dawn * * 1-5";
+// see CronSchedule. The date and clock fields follow the local time zone.
+func (s *Scheduler) Cron(spec string, f func(), opts ...RuleOption) error {
+	c, err := ParseCron(spec)
+	if err != nil {
+		return err
+	}
+	s.add(&rule{
+		key: c.String(),
+		after: func(after time.Time) (Event, time.Time) {
+			return c.next(after.In(time.Local), s.sky)
+		},
+		fire: func(Event) { f() },
+	}, opts)
+	return nil
+}
+
+// triggerAfter returns the rule function of the trigger t.
+func (s *Scheduler) triggerAfter(t Trigger) func(time.Time) (Event, time.Time) {
+	return func(after time.Time) (Event, time.Time) {
+		e := nextOccurrence(s.sky, t.Event, after.Add(-t.Offset))
+		if e.Time.IsZero() {
+			return Event{}, time.Time{}
+		}
+		return e, e.Time.Add(t.Offset)
+	}
+}
+
+// OnSunrise registers f to be called at every sunrise.
+func (s *Scheduler) OnSunrise(f func(), opts ...RuleOption) {
+	s.On(Sunrise, f, opts...)
+}
+
+// OnSunset registers f to be called at every sunset.
+func (s *Scheduler) OnSunset(f func(), opts ...RuleOption) {
+	s.On(Sunset, f, opts...)
+}
+
+// OnCivilDawn registers f to be called at every civil dawn.
+func (s *Scheduler) OnCivilDawn(f func(), opts ...RuleOption) {
+	s.On(CivilDawn, f, opts...)
+}
+
+// OnCivilDusk registers f to be called at every civil dusk.
+func (s *Scheduler) OnCivilDusk(f func(), opts ...RuleOption) {
+	s.On(CivilDusk, f, opts...)
+}
+
+// OnSolarNoon registers f to be called at every solar noon.
+func (s *Scheduler) OnSolarNoon(f func(), opts ...RuleOption) {
+	s.On(SolarNoon, f, opts...)
+}
+
+// Occurrence is an event delivered by Subscribe.
+type Occurrence struct {
+	Event
+
+	// Delivered is when the event was sent, normally a moment after
+	// Event.Time.
+	Delivered time.Time
+}
+
+// Subscribe delivers the events of the given types, or of every type if
+// none are given, on the returned channel as they happen at the location,
+// for programs that prefer a select loop to the callbacks of Scheduler.
+// Events are computed in the local time zone. The channel is closed when
+// ctx is done. A receiver that falls behind delays later events rather
+// than losing them.
+func Subscribe(ctx context.Context, latitude, longitude float64, types ...EventType) <-chan Occurrence {
+	if len(types) == 0 {
+		types = EventTypes
+	}
+	ch := make(chan Occurrence, 1)
+	s := NewScheduler(Observer{Latitude: latitude, Longitude: longitude})
+
+	// the lock keeps the deliveries in order
+	var mu sync.Mutex
+	deliver := func(e Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		select {
+		case ch <- Occurrence{Event: e, Delivered: time.Now()}:
+		case <-ctx.Done():
+		}
+	}
+	for _, e := range types {
+		s.add(&rule{after: s.triggerAfter(At(e, 0)), fire: deliver}, nil)
+	}
+	s.StartContext(ctx)
+
+	go func() {
+		<-ctx.Done()
+		s.Wait()
+		close(ch)
+	}()
+	return ch
+}
+
+func (s *Scheduler) add(r *rule, opts []RuleOption) {
+	for _, opt := range opts {
+		opt(r)
+	}
+	now := time.Now()
+	s.schedule(r, now)
+
+	r.key = s.prefix + r.key
+	s.mu.Lock()
+	repeats := 1
+	for _, other := range s.rules {
+		if other.key == r.key || strings.HasPrefix(other.key, r.key+"#") {
+			repeats++
+		}
+	}
+	if repeats > 1 {
+		r.key += "#" + strconv.Itoa(repeats)
+	}
+	heap.Push(&s.rules, r)
+	s.mu.Unlock()
+
+	if s.store != nil && s.catchUp > 0 {
+		s.findMissed(r, now)
+	}
+
+	// let a running loop reconsider its timer
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// findMissed marks the latest occurrence of r in the catch-up window
+// before now as due if the store shows it was not fired.
+func (s *Scheduler) findMissed(r *rule, now time.Time) {
+	last, ok := s.store.LastFired(r.key)
+	if !ok {
+		return
+	}
+
+	var e Event
+	var at time.Time
+	for t := now.Add(-s.catchUp); ; {
+		ne, nt := r.after(t)
+		if nt.IsZero() || nt.After(now) {
+			break
+		}
+		e, at, t = ne, nt, nt
+	}
+	if at.IsZero() || !at.After(last) {
+		return
+	}
+
+	s.mu.Lock()
+	r.next, r.due, r.at, r.missed = e, at, at, true
+	heap.Fix(&s.rules, r.index)
+	s.mu.Unlock()
+}
+
+// Start starts calling the registered functions in a new goroutine. It
+// does nothing if the scheduler has already been started.
+func (s *Scheduler) Start() {
+	s.StartContext(context.Background())
+}
+
+// StartContext is like Start, and the scheduler stops when ctx is done as
+// if Stop were called.
+func (s *Scheduler) StartContext(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return
+	}
+	s.started = true
+	s.running.Add(1)
+	go s.run(ctx)
+}
+
+// Stop stops the scheduler: no function is called after Stop returns.
+// Functions already called run to completion; Wait waits for them. It
+// does nothing if the scheduler is stopped already.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	select {
+	case <-s.stop:
+	default:
+		close(s.stop)
+	}
+}
+
+// Wait waits until the scheduler has stopped, by Stop or the context of
+// StartContext, and the functions it called have returned. It returns at
+// once if the scheduler was never started.
+func (s *Scheduler) Wait() {
+	s.running.Wait()
+}
+
+const (
+	// schedulerPoll is the longest the scheduler sleeps before checking
+	// the wall clock.
+	schedulerPoll = time.Minute
+
+	// clockJump is the disagreement between the wall clock and the
+	// monotonic clock over one sleep that counts as a change of the
+	// system clock rather than a gradual NTP adjustment.
+	clockJump = 2 * time.Second
+
+	// maxLateness is how late an occurrence may still fire.
+	maxLateness = 2 * time.Minute
+)
+
+func (s *Scheduler) run(ctx context.Context) {
+	defer s.running.Done()
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+
+	last := time.Now()
+	for {
+		now := time.Now()
+		// the difference is in the wall clock, as Round(0) strips the
+		// monotonic reading
+		if drift := now.Round(0).Sub(last.Round(0)) - now.Sub(last); drift > clockJump || drift < -clockJump {
+			s.reschedule(now)
+		}
+		last = now
+		s.fireDue(now)
+
+		s.mu.Lock()
+		next := s.earliest()
+		s.mu.Unlock()
+
+		// next has no monotonic reading, so the wait follows the wall
+		// clock
+		wait := schedulerPoll
+		if !next.IsZero() && time.Until(next) < wait {
+			wait = time.Until(next)
+		}
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(wait)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stop:
+			return
+		case <-s.wake:
+		case <-timer.C:
+		}
+	}
+}
+
+// reschedule recomputes every occurrence after a change of the clock to
+// now.
+func (s *Scheduler) reschedule(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, r := range s.rules {
+		s.schedule(r, now.Add(-maxLateness))
+	}
+	heap.Init(&s.rules)
+}
+
+// earliest returns the time of the first pending occurrence, or the zero
+// time if there is none. s.mu must be held.
+func (s *Scheduler) earliest() time.Time {
+	if len(s.rules) == 0 {
+		return time.Time{}
+	}
+	return s.rules[0].at
+}
+
+// fireDue calls the functions whose occurrence is not after now and
+// schedules their next occurrence. Occurrences more than maxLateness ago
+// are skipped.
+func (s *Scheduler) fireDue(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	select {
+	case <-s.stop:
+		return
+	default:
+	}
+
+	for len(s.rules) > 0 {
+		r := s.rules[0]
+		if r.at.IsZero() || r.at.After(now) {
+			break
+		}
+		switch {
+		case now.Sub(r.at) > maxLateness && !r.missed:
+			s.schedule(r, now.Add(-maxLateness))
+		case r.missed:
+			s.call(r)
+			r.missed = false
+			s.schedule(r, now)
+		default:
+			s.call(r)
+			s.schedule(r, r.due)
+		}
+		heap.Fix(&s.rules, 0)
+	}
+}
+
+// call calls the function of r for its current occurrence in a new
+// goroutine and records the occurrence in the store. s.mu must be held.
+func (s *Scheduler) call(r *rule) {
+	s.running.Add(1)
+	go func(fire func(Event), e Event) {
+		defer s.running.Done()
+		fire(e)
+	}(r.fire, r.next)
+	if s.store != nil {
+		s.store.SetLastFired(r.key, r.due)
+	}
+}
+
+// schedule sets the next occurrence of r due after the instant after.
+// A jittered call is not moved before after, so that it is neither
+// skipped as late nor the occurrence chosen again.
+func (s *Scheduler) schedule(r *rule, after time.Time) {
+	r.next, r.due = r.after(after)
+	r.at = r.due
+	if r.jitter > 0 && !r.due.IsZero() {
+		r.at = r.due.Add(randomJitter(r.jitter))
+		if r.at.Before(after) {
+			r.at = after
+		}
+	}
+}
+
+// TimerUntil returns a timer that fires at the next event of type e at the
+// location, and the time of the event, for programs with their own select
+// loop that do not need a Scheduler. The timer follows the monotonic clock
+// like any time.Timer, so after a suspend it fires late; see Scheduler.
+// It returns ErrNoEvent if the event does not happen within a year.
+func TimerUntil(e EventType, latitude, longitude float64, opts ...Option) (*time.Timer, time.Time, error) {
+	next := nextOccurrence(Observer{Latitude: latitude, Longitude: longitude, Options: opts}, e, time.Now())
+	if next.Time.IsZero() {
+		return nil, time.Time{}, ErrNoEvent
+	}
+	return time.NewTimer(time.Until(next.Time)), next.Time, nil
+}
+
+// nextOccurrence returns the first event of type e on sky after the
+// instant after, looking a day at a time up to a year ahead.
+func nextOccurrence(sky Sky, e EventType, after time.Time) Event {
+	from := after.In(time.Local)
+	for from.Sub(after) < trackerHorizon {
+		to := from.Add(24 * time.Hour)
+		for _, ev := range sky.EventsBetween(from, to, e) {
+			if ev.Time.After(after) {
+				return ev
+			}
+		}
+		from = to
+	}
+	return Event{}
+}
@@ -0,0 +1,297 @@
+package sunevent
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// scriptedSky is a Sky with the events listed, for testing the scheduler
+// without waiting for the Sun.
+type scriptedSky []Event
+
+func (s scriptedSky) EventOn(e EventType, date time.Time) (Event, error) {
+	y, m, d := date.Date()
+	for _, ev := range s {
+		if t := ev.Time.In(date.Location()); ev.Type == e && t.Year() == y && t.Month() == m && t.Day() == d {
+			return ev, nil
+		}
+	}
+	return Event{}, ErrSunNeverRises
+}
+
+func (s scriptedSky) EventsBetween(from, to time.Time, types ...EventType) []Event {
+	var events []Event
+	for _, ev := range s {
+		if ev.Time.Before(from) || !ev.Time.Before(to) {
+			continue
+		}
+		for _, e := range types {
+			if e == ev.Type {
+				events = append(events, ev)
+				break
+			}
+		}
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].Time.Before(events[j].Time) })
+	return events
+}
+
+func (s scriptedSky) PhaseAt(t time.Time) PhaseKind {
+	return PhaseDay
+}
+
+// receive waits for n values from ch and returns them, or fails the test.
+func receive(t *testing.T, ch <-chan EventType, n int) []EventType {
+	t.Helper()
+	var got []EventType
+	for len(got) < n {
+		select {
+		case e := <-ch:
+			got = append(got, e)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("received %v, want %d calls", got, n)
+		}
+	}
+	return got
+}
+
+func TestScheduler(t *testing.T) {
+	now := time.Now()
+	sky := scriptedSky{
+		{Type: Sunset, Time: now.Add(50 * time.Millisecond)},
+		{Type: Sunrise, Time: now.Add(100 * time.Millisecond)},
+		{Type: Sunset, Time: now.Add(150 * time.Millisecond)},
+		{Type: SolarNoon, Time: now.Add(-time.Second)},
+	}
+	s := NewScheduler(sky)
+	calls := make(chan EventType, 10)
+	s.OnSunset(func() { calls <- Sunset })
+	s.OnSunrise(func() { calls <- Sunrise })
+	s.OnSolarNoon(func() { calls <- SolarNoon })
+	s.Start()
+	s.Start() // no second loop
+
+	// in order, each sunset in turn, and never the noon already past
+	got := receive(t, calls, 3)
+	if got[0] != Sunset || got[1] != Sunrise || got[2] != Sunset {
+		t.Errorf("calls %v, want sunset, sunrise, sunset", got)
+	}
+	s.Stop()
+	s.Wait()
+	select {
+	case e := <-calls:
+		t.Errorf("called for %v after the script", e)
+	default:
+	}
+}
+
+// noonLongitude returns the longitude on the equator where the Sun
+// transits at t, for tests that wait for a real event.
+func noonLongitude(t time.Time) float64 {
+	t = t.UTC()
+	longitude := 0.0
+	for i := 0; i < 4; i++ {
+		noon, _ := SolarNoonOn(t, 0, longitude)
+		longitude += noon.Sub(t).Hours() * 15.0
+	}
+	return longitude
+}
+
+func TestSubscribe(t *testing.T) {
+	at := time.Now().Add(2 * time.Second)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := Subscribe(ctx, 0, noonLongitude(at), SolarNoon)
+
+	select {
+	case o := <-ch:
+		if o.Type != SolarNoon || absDuration(o.Time.Sub(at)) > time.Second {
+			t.Errorf("%v at %s, want solar noon at %s", o.Type, o.Time, at)
+		}
+		if o.Delivered.Before(o.Time) || o.Delivered.Sub(o.Time) > time.Second {
+			t.Errorf("%v at %s delivered at %s", o.Type, o.Time, o.Delivered)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no solar noon delivered")
+	}
+
+	cancel()
+	select {
+	case o, ok := <-ch:
+		if ok {
+			t.Errorf("%v delivered after cancel", o.Type)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("channel not closed after cancel")
+	}
+}
+
+func TestSchedulerClockChange(t *testing.T) {
+	now := time.Now()
+	sunset := now.Add(time.Hour).Round(0)
+	sky := scriptedSky{
+		{Type: Sunset, Time: sunset},
+		{Type: Sunset, Time: sunset.Add(24 * time.Hour)},
+	}
+	var mu sync.Mutex
+	calls := 0
+	s := NewScheduler(sky)
+	s.OnSunset(func() {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+	})
+
+	// the clock jumps past the sunset: too late to switch on the lights
+	s.fireDue(sunset.Add(maxLateness + time.Minute))
+	if at := s.earliest(); !at.Equal(sunset.Add(24 * time.Hour)) {
+		t.Errorf("after a late sunset, next at %s, want the next day", at)
+	}
+
+	// the clock is set back: the sunset is due again
+	s.reschedule(now)
+	if at := s.earliest(); !at.Equal(sunset) {
+		t.Errorf("after setting the clock back, next at %s, want %s", at, sunset)
+	}
+
+	// a minute late still fires
+	s.fireDue(sunset.Add(time.Minute))
+	s.Wait()
+	if calls != 1 {
+		t.Errorf("%d calls, want 1", calls)
+	}
+}
+
+func TestTimerUntil(t *testing.T) {
+	at := time.Now().Add(2 * time.Second)
+	timer, noon, err := TimerUntil(SolarNoon, 0, noonLongitude(at))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if absDuration(noon.Sub(at)) > time.Second {
+		t.Errorf("solar noon at %s, want %s", noon, at)
+	}
+	select {
+	case fired := <-timer.C:
+		if fired.Before(noon) {
+			t.Errorf("timer fired at %s, before solar noon at %s", fired, noon)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("timer did not fire")
+	}
+
+	// the horizon has no hour angle at the pole, so no sunrise is found
+	if _, _, err := TimerUntil(Sunrise, 90, 0); err != ErrNoEvent {
+		t.Errorf("sunrise at the North Pole: %v, want ErrNoEvent", err)
+	}
+}
+
+func TestSchedulerWait(t *testing.T) {
+	// Wait returns at once for a scheduler never started
+	NewScheduler(scriptedSky{}).Wait()
+
+	now := time.Now()
+	sky := scriptedSky{
+		{Type: Sunset, Time: now.Add(20 * time.Millisecond)},
+		{Type: Sunset, Time: now.Add(time.Second)},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s := NewScheduler(sky)
+	started := make(chan struct{})
+	var mu sync.Mutex
+	calls, done := 0, false
+	s.OnSunset(func() {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		close(started)
+		time.Sleep(100 * time.Millisecond)
+		mu.Lock()
+		done = true
+		mu.Unlock()
+	})
+	s.StartContext(ctx)
+
+	<-started
+	cancel()
+	s.Wait()
+	mu.Lock()
+	if !done {
+		t.Error("Wait returned before the function")
+	}
+	mu.Unlock()
+
+	// stopped by the context, the scheduler misses the second sunset
+	s.Stop()
+	s.Stop()
+	time.Sleep(time.Until(now.Add(1100 * time.Millisecond)))
+	s.Wait()
+	mu.Lock()
+	defer mu.Unlock()
+	if calls != 1 {
+		t.Errorf("%d calls, want 1", calls)
+	}
+}
+
+func TestSchedulerFor(t *testing.T) {
+	now := time.Now().Round(0)
+	home := scriptedSky{{Type: Sunset, Time: now.Add(50 * time.Millisecond)}}
+	greenhouse := scriptedSky{{Type: Sunset, Time: now.Add(100 * time.Millisecond)}}
+	store := memoryStore{}
+	s := NewScheduler(home, WithStore(store))
+	calls := make(chan EventType, 4)
+	s.OnSunset(func() { calls <- Sunset })
+	s.OnSunset(func() { calls <- Sunset })
+	s.For("greenhouse", greenhouse).OnSunset(func() { calls <- Sunset })
+	s.Start()
+
+	receive(t, calls, 3)
+	s.Stop()
+	s.Wait()
+	want := map[string]time.Time{
+		"sunset":            home[0].Time,
+		"sunset#2":          home[0].Time,
+		"greenhouse/sunset": greenhouse[0].Time,
+	}
+	for key, at := range want {
+		if got, ok := store[key]; !ok || !got.Equal(at) {
+			t.Errorf("store[%q] = %s, want %s", key, got, at)
+		}
+	}
+	if len(store) != len(want) {
+		t.Errorf("store has %d keys, want %d", len(store), len(want))
+	}
+}
+
+func TestWithJitter(t *testing.T) {
+	d := 10 * time.Minute
+	for i := 0; i < 1000; i++ {
+		if j := randomJitter(d); j < -d || j > d {
+			t.Fatalf("randomJitter(%s) = %s", d, j)
+		}
+	}
+
+	now := time.Now().Round(0)
+	sunset := now.Add(time.Minute)
+	s := NewScheduler(scriptedSky{{Type: Sunset, Time: sunset}})
+	moved := false
+	for i := 0; i < 100; i++ {
+		r := &rule{after: s.triggerAfter(At(Sunset, 0))}
+		WithJitter(d)(r)
+		s.schedule(r, now)
+		// never before now, where the sunset would be skipped as late
+		if r.at.Before(now) || r.at.After(sunset.Add(d)) {
+			t.Fatalf("jittered sunset at %s, want between %s and %s", r.at, now, sunset.Add(d))
+		}
+		if !r.due.Equal(sunset) {
+			t.Errorf("due at %s, want %s", r.due, sunset)
+		}
+		moved = moved || !r.at.Equal(sunset)
+	}
+	if !moved {
+		t.Error("the jitter never moved the sunset")
+	}
+}
@@ -0,0 +1,115 @@
+package sunevent
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// SchedulerStore records when each rule of a Scheduler last fired, so
+// that after a restart the scheduler can tell which occurrences it missed.
+// Rules are identified by keys derived from their trigger or cron
+// expression, with "#2", "#3" and so on appended to repeats in the order
+// of registration. The keys of the rules of a view from Scheduler.For
+// start with its name and a slash.
+type SchedulerStore interface {
+	// LastFired returns the time of the occurrence the rule key last
+	// fired for, and false if it is not recorded.
+	LastFired(key string) (time.Time, bool)
+
+	// SetLastFired records that the rule key fired for the occurrence at
+	// t.
+	SetLastFired(key string, t time.Time) error
+}
+
+// SchedulerOption configures a Scheduler.
+type SchedulerOption func(*Scheduler)
+
+// WithStore records the occurrences the scheduler fires in store. Errors
+// returned by the store are ignored, as a failed write should not stop
+// the lights; a store that needs to report them can log them itself.
+func WithStore(store SchedulerStore) SchedulerOption {
+	return func(s *Scheduler) {
+		s.store = store
+	}
+}
+
+// WithCatchUp makes the scheduler fire, once, the latest occurrence of a
+// rule that was due in the window before it started and that the store
+// of WithStore shows was not fired, as when a "sunset" rule missed the
+// evening because of a reboot. Rules the store has no record of are not
+// caught up. The default window of zero never catches up.
+func WithCatchUp(window time.Duration) SchedulerOption {
+	return func(s *Scheduler) {
+		s.catchUp = window
+	}
+}
+
+// FileStore is a SchedulerStore kept in a small JSON file. It is safe
+// for concurrent use; each change rewrites the file.
+type FileStore struct {
+	path string
+
+	mu     sync.Mutex
+	loaded bool
+	fired  map[string]time.Time
+}
+
+// NewFileStore returns a store kept in the file at path, which is
+// created when the first occurrence is recorded.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// LastFired implements SchedulerStore. A missing or unreadable file
+// counts as empty.
+func (f *FileStore) LastFired(key string) (time.Time, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.load()
+	t, ok := f.fired[key]
+	return t, ok
+}
+
+// SetLastFired implements SchedulerStore. The file is replaced by a
+// rename, so a crash cannot leave it half written.
+func (f *FileStore) SetLastFired(key string, t time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.load()
+	f.fired[key] = t
+
+	data, err := json.MarshalIndent(f.fired, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(f.path), filepath.Base(f.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), f.path)
+}
+
+// load reads the file once. f.mu must be held.
+func (f *FileStore) load() {
+	if f.loaded {
+		return
+	}
+	f.loaded = true
+	f.fired = make(map[string]time.Time)
+	if data, err := ioutil.ReadFile(f.path); err == nil {
+		json.Unmarshal(data, &f.fired)
+	}
+}
@@ -0,0 +1,83 @@
+package sunevent
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sunevent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "fired.json")
+
+	store := NewFileStore(path)
+	if _, ok := store.LastFired("sunset"); ok {
+		t.Error("a new store has a record")
+	}
+	sunset := time.Date(2026, time.June, 21, 19, 8, 0, 0, time.UTC)
+	if err := store.SetLastFired("sunset", sunset); err != nil {
+		t.Fatal(err)
+	}
+
+	// the record survives a restart
+	if got, ok := NewFileStore(path).LastFired("sunset"); !ok || !got.Equal(sunset) {
+		t.Errorf("LastFired after a restart = %s, %v, want %s", got, ok, sunset)
+	}
+
+	if err := NewFileStore(filepath.Join(dir, "missing", "fired.json")).SetLastFired("sunset", sunset); err == nil {
+		t.Error("SetLastFired in a missing directory did not fail")
+	}
+}
+
+// memoryStore is a SchedulerStore in a map.
+type memoryStore map[string]time.Time
+
+func (m memoryStore) LastFired(key string) (time.Time, bool) {
+	t, ok := m[key]
+	return t, ok
+}
+
+func (m memoryStore) SetLastFired(key string, t time.Time) error {
+	m[key] = t
+	return nil
+}
+
+func TestCatchUp(t *testing.T) {
+	now := time.Now().Round(0)
+	missed := now.Add(-time.Hour)
+	sky := scriptedSky{
+		{Type: Sunrise, Time: now.Add(-2 * time.Hour)},
+		{Type: Sunset, Time: now.Add(-25 * time.Hour)},
+		{Type: Sunset, Time: missed},
+		{Type: Sunset, Time: now.Add(23 * time.Hour)},
+	}
+	// the sunset of yesterday fired, today's was missed while down, and
+	// sunrise was never recorded
+	store := memoryStore{"sunset": now.Add(-25 * time.Hour)}
+	s := NewScheduler(sky, WithStore(store), WithCatchUp(3*time.Hour))
+	calls := make(chan EventType, 4)
+	s.OnSunset(func() { calls <- Sunset })
+	s.OnSunrise(func() { calls <- Sunrise })
+	s.Start()
+
+	if got := receive(t, calls, 1); got[0] != Sunset {
+		t.Errorf("caught up %v, want sunset", got[0])
+	}
+	time.Sleep(50 * time.Millisecond)
+	s.Stop()
+	s.Wait()
+	select {
+	case e := <-calls:
+		t.Errorf("also caught up %v", e)
+	default:
+	}
+	if got := store["sunset"]; !got.Equal(missed) {
+		t.Errorf("store has sunset at %s, want %s", got, missed)
+	}
+}
@@ -0,0 +1,199 @@
+package sunevent
+
+import (
+	"time"
+)
+
+// Equinoxes returns the March and September equinoxes of year in UTC: the
+// instants when the apparent longitude of the Sun is 0° and 180°, and it
+// crosses the celestial equator. They follow Meeus, Astronomical
+// Algorithms, chapter 27, and are accurate to about a minute for years
+// -1000 to 3000.
+func Equinoxes(year int) (march, september time.Time) {
+	return season(year, 0), season(year, 2)
+}
+
+// Solstices returns the June and December solstices of year in UTC, when
+// the apparent longitude of the Sun is 90° and 270° and its declination
+// is furthest north and south.
+func Solstices(year int) (june, december time.Time) {
+	return season(year, 1), season(year, 3)
+}
+
+// Hemisphere selects the northern or southern half of the Earth, whose
+// seasons are opposite.
+type Hemisphere int
+
+const (
+	Northern Hemisphere = iota
+	Southern
+)
+
+// HemisphereOf returns the hemisphere of latitude; the equator counts as
+// northern.
+func HemisphereOf(latitude float64) Hemisphere {
+	if latitude < 0 {
+		return Southern
+	}
+	return Northern
+}
+
+func (h Hemisphere) String() string {
+	switch h {
+	case Northern:
+		return "northern"
+	case Southern:
+		return "southern"
+	}
+	return "unknown"
+}
+
+// Season is an astronomical season, bounded by the equinoxes and
+// solstices.
+type Season int
+
+const (
+	Spring Season = iota
+	Summer
+	Autumn
+	Winter
+)
+
+func (s Season) String() string {
+	switch s {
+	case Spring:
+		return "spring"
+	case Summer:
+		return "summer"
+	case Autumn:
+		return "autumn"
+	case Winter:
+		return "winter"
+	}
+	return "unknown"
+}
+
+// SeasonAt returns the astronomical season at the instant t in the
+// hemisphere: in the northern hemisphere spring runs from the March
+// equinox to the June solstice, and so on; the southern seasons are
+// opposite. Meteorological seasons, which start on the first of March,
+// June, September and December, are not covered.
+func SeasonAt(t time.Time, hemisphere Hemisphere) Season {
+	// the northern seasons start at the boundaries in order
+	s := Winter
+	for n := 0; n < 4; n++ {
+		if !t.Before(season(t.Year(), n)) {
+			s = Season(n)
+		}
+	}
+	if hemisphere == Southern {
+		s = (s + 2) % 4
+	}
+	return s
+}
+
+// meanSeasons holds the coefficients of the mean March equinox, June
+// solstice, September equinox and December solstice in Julian Ephemeris
+// Days, as polynomials in Y = year / 1000 for years -1000 to 1000 (Meeus,
+// table 27.A) and Y = (year - 2000) / 1000 for years 1000 to 3000 (table
+// 27.B).
+var meanSeasons = [2][4][5]float64{
+	{
+		{1721139.29189, 365242.13740, 0.06134, 0.00111, -0.00071},
+		{1721233.25401, 365241.72562, -0.05323, 0.00907, 0.00025},
+		{1721325.70455, 365242.49558, -0.11677, -0.00297, 0.00074},
+		{1721414.39987, 365242.88257, -0.00769, -0.00933, -0.00006},
+	},
+	{
+		{2451623.80984, 365242.37404, 0.05169, -0.00411, -0.00057},
+		{2451716.56767, 365241.62603, 0.00325, 0.00888, -0.00030},
+		{2451810.21715, 365242.01767, -0.11575, 0.00337, 0.00078},
+		{2451900.05952, 365242.74049, -0.06223, -0.00823, 0.00032},
+	},
+}
+
+// seasonTerms are the periodic terms A cos(B + C T) of Meeus, table 27.C.
+var seasonTerms = [][3]float64{
+	{485, 324.96, 1934.136},
+	{203, 337.23, 32964.467},
+	{199, 342.08, 20.186},
+	{182, 27.85, 445267.112},
+	{156, 73.14, 45036.886},
+	{136, 171.52, 22518.443},
+	{77, 222.54, 65928.934},
+	{74, 296.72, 3034.906},
+	{70, 243.58, 9037.513},
+	{58, 119.81, 33718.147},
+	{52, 297.17, 150.678},
+	{50, 21.02, 2281.226},
+	{45, 247.54, 29929.562},
+	{44, 325.15, 31555.956},
+	{29, 60.93, 4443.417},
+	{18, 155.12, 67555.328},
+	{17, 288.79, 4562.452},
+	{16, 198.04, 62894.029},
+	{14, 199.76, 31436.921},
+	{12, 95.39, 14577.848},
+	{12, 287.11, 31931.756},
+	{12, 320.81, 34777.259},
+	{9, 227.73, 1222.114},
+	{8, 15.45, 16859.074},
+}
+
+// season returns the instant of the March equinox, June solstice,
+// September equinox or December solstice of year for n from 0 to 3.
+// JDE = JDE0 + 0.00001 * S / Δλ
+func season(year, n int) time.Time {
+	table, y := 1, float64(year-2000)/1000.0
+	if year < 1000 {
+		table, y = 0, float64(year)/1000.0
+	}
+	c := meanSeasons[table][n]
+	jde0 := c[0] + y*(c[1]+y*(c[2]+y*(c[3]+y*c[4])))
+
+	T := (jde0 - 2451545.0) / 36525.0
+	W := 35999.373*T - 2.47
+	dlambda := 1 + 0.0334*degreeCos(W) + 0.0007*degreeCos(2*W)
+	var S float64
+	for _, term := range seasonTerms {
+		S += term[0] * degreeCos(term[1]+term[2]*T)
+	}
+	jde := jde0 + 0.00001*S/dlambda
+
+	// JDE counts terrestrial time
+	tt := time.Unix(0, 0).UTC().Add(time.Duration((jde - 2440587.5) * 86400 * float64(time.Second)))
+	return tt.Add(-DeltaT(tt)).Round(time.Second)
+}
+
+// CrossQuarterDays returns the four cross-quarter days of year in UTC,
+// midway between the solstices and equinoxes: the instants when the
+// apparent longitude of the Sun is 315°, 45°, 135° and 225°, near the
+// Gaelic festivals of Imbolc, Beltane, Lughnasadh and Samhain. They use
+// the low-accuracy solar theory of the event calculations and are within
+// a quarter of an hour of the VSOP87 theory from 1950 to 2100; the
+// festivals themselves are often kept on fixed dates instead.
+func CrossQuarterDays(year int) (february, may, august, november time.Time) {
+	return solarLongitudeTime(year, time.February, 4, 315),
+		solarLongitudeTime(year, time.May, 5, 45),
+		solarLongitudeTime(year, time.August, 7, 135),
+		solarLongitudeTime(year, time.November, 7, 225)
+}
+
+// solarLongitudeTime returns the instant near the given date when the
+// apparent longitude of the Sun is target degrees. The longitude grows by
+// about 1° a day, so Newton's method from there converges in a few steps.
+func solarLongitudeTime(year int, month time.Month, day int, target float64) time.Time {
+	const daysPerDegree = 365.2422 / 360.0
+	o := Options{}
+
+	t := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 10; i++ {
+		L, _, _, _ := sunCoordinates(o.julianCentury(t))
+		step := time.Duration((normalizeRange(target-L+180.0, 360) - 180.0) * daysPerDegree * float64(24*time.Hour))
+		t = t.Add(step)
+		if step < time.Second && step > -time.Second {
+			break
+		}
+	}
+	return t.Round(time.Second)
+}
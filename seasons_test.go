@@ -0,0 +1,111 @@
+package sunevent
+
+import (
+	"testing"
+	"time"
+)
+
+// within reports whether got is within tolerance of want.
+func within(got, want time.Time, tolerance time.Duration) bool {
+	d := got.Sub(want)
+	return d <= tolerance && d >= -tolerance
+}
+
+func TestEquinoxesAndSolstices(t *testing.T) {
+	utc := func(year int, month time.Month, day, hour, min int) time.Time {
+		return time.Date(year, month, day, hour, min, 0, 0, time.UTC)
+	}
+	// the instants published by the US Naval Observatory, to the minute
+	tests := []struct {
+		year                             int
+		march, june, september, december time.Time
+	}{
+		{2000, utc(2000, time.March, 20, 7, 35), utc(2000, time.June, 21, 1, 48), utc(2000, time.September, 22, 17, 28), utc(2000, time.December, 21, 13, 37)},
+		{2024, utc(2024, time.March, 20, 3, 6), utc(2024, time.June, 20, 20, 51), utc(2024, time.September, 22, 12, 44), utc(2024, time.December, 21, 9, 21)},
+		{2026, utc(2026, time.March, 20, 14, 46), utc(2026, time.June, 21, 8, 24), utc(2026, time.September, 23, 0, 5), utc(2026, time.December, 21, 20, 50)},
+	}
+	const tolerance = 2 * time.Minute
+	for _, tt := range tests {
+		march, september := Equinoxes(tt.year)
+		june, december := Solstices(tt.year)
+		for _, c := range []struct {
+			name      string
+			got, want time.Time
+		}{
+			{"March equinox", march, tt.march},
+			{"June solstice", june, tt.june},
+			{"September equinox", september, tt.september},
+			{"December solstice", december, tt.december},
+		} {
+			if !within(c.got, c.want, tolerance) {
+				t.Errorf("%d %s = %s, want %s", tt.year, c.name, c.got, c.want)
+			}
+		}
+	}
+}
+
+func TestSeasonAt(t *testing.T) {
+	march, _ := Equinoxes(2026)
+	tests := []struct {
+		t          time.Time
+		hemisphere Hemisphere
+		want       Season
+	}{
+		{march.Add(-time.Second), Northern, Winter},
+		{march, Northern, Spring},
+		{march, Southern, Autumn},
+		{time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC), Northern, Winter},
+		{time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC), Southern, Summer},
+		{time.Date(2026, time.August, 1, 0, 0, 0, 0, time.UTC), Northern, Summer},
+		{time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC), Northern, Autumn},
+		{time.Date(2026, time.December, 31, 0, 0, 0, 0, time.UTC), Southern, Summer},
+	}
+	for _, tt := range tests {
+		if got := SeasonAt(tt.t, tt.hemisphere); got != tt.want {
+			t.Errorf("SeasonAt(%s, %v) = %v, want %v", tt.t, tt.hemisphere, got, tt.want)
+		}
+	}
+}
+
+func TestHemisphereOf(t *testing.T) {
+	if h := HemisphereOf(-33.9); h != Southern || h.String() != "southern" {
+		t.Errorf("HemisphereOf(-33.9) = %v", h)
+	}
+	// the equator counts as northern
+	if h := HemisphereOf(0); h != Northern || h.String() != "northern" {
+		t.Errorf("HemisphereOf(0) = %v", h)
+	}
+	if s := Autumn.String(); s != "autumn" {
+		t.Errorf("Autumn.String() = %q", s)
+	}
+}
+
+func TestCrossQuarterDays(t *testing.T) {
+	february, may, august, november := CrossQuarterDays(2026)
+	tests := []struct {
+		got       time.Time
+		date      string
+		longitude float64
+	}{
+		{february, "Feb 3", 315},
+		{may, "May 5", 45},
+		{august, "Aug 7", 135},
+		{november, "Nov 7", 225},
+	}
+	o := Options{}
+	for _, tt := range tests {
+		if d := tt.got.Format("Jan 2"); d != tt.date || tt.got.Location() != time.UTC {
+			t.Errorf("cross-quarter day at %s, want on %s", tt.got, tt.date)
+		}
+		if L, _, _, _ := sunCoordinates(o.julianCentury(tt.got)); !near(L, tt.longitude, 0.0001) {
+			t.Errorf("%s: solar longitude %v°, want %v°", tt.got, L, tt.longitude)
+		}
+	}
+
+	// about midway between the neighbouring equinox and solstice
+	march, _ := Equinoxes(2026)
+	june, _ := Solstices(2026)
+	if mid := march.Add(june.Sub(march) / 2); absDuration(may.Sub(mid)) > 2*24*time.Hour {
+		t.Errorf("May cross-quarter day %s, midway %s", may, mid)
+	}
+}
@@ -0,0 +1,139 @@
+package sunevent
+
+import (
+	"math"
+	"time"
+)
+
+// Sample is the position of the Sun at one instant of a series.
+type Sample struct {
+	Time time.Time
+	Position
+}
+
+// ElevationSeries returns the position of the Sun every step across the
+// civil date of date, starting at local midnight in the time zone of date.
+// It returns nil if step is not positive.
+func ElevationSeries(date time.Time, latitude, longitude float64, step time.Duration, opts ...Option) []Sample {
+	if step <= 0 {
+		return nil
+	}
+	o := newOptions(opts)
+	longitude = o.longitude(longitude)
+
+	start, end := o.civilDay(date)
+	day := newDayCoordinates(start, end)
+
+	samples := make([]Sample, 0, int(end.Sub(start)/step)+1)
+	for t := start; t.Before(end); t = t.Add(step) {
+		azimuth, elevation := day.position(t, latitude, longitude)
+		samples = append(samples, Sample{Time: t, Position: o.position(azimuth, elevation)})
+	}
+	return samples
+}
+
+// AdaptiveElevationSeries returns the position of the Sun across the
+// civil date of date, sampled only as densely as needed for straight lines
+// between consecutive samples to stay within tolerance degrees of the true
+// path in both altitude and azimuth. Intervals containing sunrise or sunset
+// are always refined down to a minute, so the horizon crossings are
+// sharp. The series includes both the starting and the ending midnight.
+// It returns nil if tolerance is not positive.
+func AdaptiveElevationSeries(date time.Time, latitude, longitude, tolerance float64, opts ...Option) []Sample {
+	if tolerance <= 0 {
+		return nil
+	}
+	o := newOptions(opts)
+	longitude = o.longitude(longitude)
+
+	start, end := o.civilDay(date)
+	day := newDayCoordinates(start, end)
+
+	sample := func(t time.Time) Sample {
+		azimuth, elevation := day.position(t, latitude, longitude)
+		return Sample{Time: t, Position: Position{Azimuth: azimuth, Altitude: elevation}}
+	}
+
+	var refine func(a, b Sample) []Sample
+	refine = func(a, b Sample) []Sample {
+		span := b.Time.Sub(a.Time)
+		if span <= time.Minute {
+			return []Sample{a}
+		}
+		mid := sample(a.Time.Add(span / 2))
+
+		crossesHorizon := (a.Altitude < 0) != (b.Altitude < 0)
+		altitudeError := math.Abs(mid.Altitude - (a.Altitude+b.Altitude)/2.0)
+		azimuthError := math.Abs(angleDifference(mid.Azimuth, a.Azimuth+angleDifference(b.Azimuth, a.Azimuth)/2.0))
+		if !crossesHorizon && altitudeError <= tolerance && azimuthError <= tolerance {
+			return []Sample{a}
+		}
+		return append(refine(a, mid), refine(mid, b)...)
+	}
+
+	var samples []Sample
+	prev := sample(start)
+	for t := start.Add(time.Hour); ; t = t.Add(time.Hour) {
+		if t.After(end) {
+			t = end
+		}
+		next := sample(t)
+		samples = append(samples, refine(prev, next)...)
+		prev = next
+		if !t.Before(end) {
+			break
+		}
+	}
+	samples = append(samples, prev)
+
+	for i := range samples {
+		samples[i].Position = o.position(samples[i].Azimuth, samples[i].Altitude)
+	}
+	return samples
+}
+
+// angleDifference returns a - b in degrees, wrapped into [-180, 180).
+func angleDifference(a, b float64) float64 {
+	return normalizeRange(a-b+180.0, 360) - 180.0
+}
+
+// dayCoordinates holds the Sun's right ascension and declination at both
+// ends of an interval, so positions inside it only need an interpolation
+// and the hour angle instead of the full steps 3 to 6.
+type dayCoordinates struct {
+	start, end time.Time
+	t0, t1     float64
+	RA0, RA1   float64
+	dec0, dec1 float64
+}
+
+func newDayCoordinates(start, end time.Time) dayCoordinates {
+	d := dayCoordinates{start: start, end: end}
+	d.t0 = julianCentury(start)
+	d.t1 = julianCentury(end)
+
+	var sinDec float64
+	_, d.RA0, sinDec, _ = sunCoordinates(d.t0)
+	d.dec0 = degreeAsin(sinDec)
+	_, d.RA1, sinDec, _ = sunCoordinates(d.t1)
+	d.dec1 = degreeAsin(sinDec)
+
+	// keep RA continuous across 0h/24h
+	if d.RA1-d.RA0 > 12.0 {
+		d.RA1 -= 24.0
+	} else if d.RA0-d.RA1 > 12.0 {
+		d.RA1 += 24.0
+	}
+
+	return d
+}
+
+func (d dayCoordinates) position(instant time.Time, latitude, longitude float64) (azimuth, elevation float64) {
+	f := float64(instant.Sub(d.start)) / float64(d.end.Sub(d.start))
+	t := d.t0 + f*(d.t1-d.t0)
+	RA := d.RA0 + f*(d.RA1-d.RA0)
+	dec := d.dec0 + f*(d.dec1-d.dec0)
+
+	H := localHourAngle(t, RA, instant, longitude)
+	return horizontal(H, degreeSin(dec), degreeCos(dec), latitude)
+}
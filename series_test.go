@@ -0,0 +1,80 @@
+package sunevent
+
+import (
+	"testing"
+	"time"
+)
+
+func TestElevationSeries(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone America/New_York: %v", err)
+	}
+	const latitude, longitude = 40.71, -74.01
+	tests := []struct {
+		date time.Time
+		want int
+	}{
+		{time.Date(2026, time.June, 21, 12, 0, 0, 0, ny), 144},
+		// the day of the spring change lasts 23 hours
+		{time.Date(2026, time.March, 8, 12, 0, 0, 0, ny), 138},
+		{time.Date(2026, time.November, 1, 12, 0, 0, 0, ny), 150},
+	}
+	for _, tt := range tests {
+		samples := ElevationSeries(tt.date, latitude, longitude, 10*time.Minute)
+		if len(samples) != tt.want {
+			t.Errorf("%s: %d samples, want %d", tt.date.Format("2006-01-02"), len(samples), tt.want)
+			continue
+		}
+		if got := samples[0].Time.In(ny).Format("2006-01-02 15:04"); got != tt.date.Format("2006-01-02")+" 00:00" {
+			t.Errorf("%s: first sample at %s, want midnight", tt.date.Format("2006-01-02"), got)
+		}
+		for _, s := range samples {
+			p := SunPosition(s.Time, latitude, longitude)
+			if !near(s.Azimuth, p.Azimuth, 0.01) || !near(s.Altitude, p.Altitude, 0.01) {
+				t.Errorf("sample at %s = %+v, SunPosition = %+v", s.Time, s.Position, p)
+				break
+			}
+		}
+	}
+
+	if s := ElevationSeries(tests[0].date, latitude, longitude, 0); s != nil {
+		t.Errorf("ElevationSeries with a step of 0 = %d samples, want nil", len(s))
+	}
+}
+
+func TestAdaptiveElevationSeries(t *testing.T) {
+	taipei := time.FixedZone("CST", 8*3600)
+	date := time.Date(2026, time.June, 21, 12, 0, 0, 0, taipei)
+	const latitude, longitude, tolerance = 22.63, 120.30, 0.5
+
+	samples := AdaptiveElevationSeries(date, latitude, longitude, tolerance)
+	if len(samples) < 24 || len(samples) > 24*60/4 {
+		t.Fatalf("%d samples, want far fewer than one a minute", len(samples))
+	}
+	start := time.Date(2026, time.June, 21, 0, 0, 0, 0, taipei)
+	if first, last := samples[0].Time, samples[len(samples)-1].Time; !first.Equal(start) || !last.Equal(start.AddDate(0, 0, 1)) {
+		t.Errorf("series from %s to %s, want both midnights", first, last)
+	}
+
+	for i := 1; i < len(samples); i++ {
+		a, b := samples[i-1], samples[i]
+		if !a.Time.Before(b.Time) {
+			t.Fatalf("samples %d and %d out of order", i-1, i)
+		}
+		// the horizon crossings are sharp
+		if (a.Altitude < 0) != (b.Altitude < 0) && b.Time.Sub(a.Time) > time.Minute {
+			t.Errorf("horizon crossed between %s and %s", a.Time, b.Time)
+		}
+		// a straight line between the samples stays near the path
+		mid := a.Time.Add(b.Time.Sub(a.Time) / 2)
+		p := SunPosition(mid, latitude, longitude)
+		if !near((a.Altitude+b.Altitude)/2, p.Altitude, tolerance+0.05) {
+			t.Errorf("altitude between %s and %s off by %.3f°", a.Time, b.Time, (a.Altitude+b.Altitude)/2-p.Altitude)
+		}
+	}
+
+	if s := AdaptiveElevationSeries(date, latitude, longitude, 0); s != nil {
+		t.Errorf("AdaptiveElevationSeries with a tolerance of 0 = %d samples, want nil", len(s))
+	}
+}
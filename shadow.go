@@ -0,0 +1,36 @@
+package sunevent
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrSunBelowHorizon is returned by calculations that need the Sun to be
+// up.
+var ErrSunBelowHorizon = errors.New("sunevent: the sun is below the horizon")
+
+// Shadow returns the length and bearing of the shadow cast on level ground
+// by a vertical object of height objectHeight at the given instant. The
+// length is in the unit of objectHeight; the bearing points from the
+// object to the tip of the shadow and follows the azimuth options. It
+// returns ErrSunBelowHorizon when there is no shadow.
+func Shadow(t time.Time, latitude, longitude, objectHeight float64, opts ...Option) (length float64, bearing float64, err error) {
+	o := newOptions(opts)
+	longitude = o.longitude(longitude)
+
+	azimuth, elevation := o.sunAt(t, latitude, longitude)
+	if o.Refraction {
+		elevation += o.refraction(elevation)
+	}
+	if elevation <= 0 {
+		return 0, 0, ErrSunBelowHorizon
+	}
+
+	length = objectHeight / degreeTan(elevation)
+
+	// the shadow points away from the Sun
+	o.Refraction = false
+	bearing = o.position(normalizeRange(azimuth+180.0, 360), 0).Azimuth
+
+	return length, bearing, nil
+}
@@ -0,0 +1,44 @@
+package sunevent
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestShadow(t *testing.T) {
+	const latitude, longitude = 45.0, 7.0
+	noon, err := SolarNoonOn(time.Date(2026, time.March, 20, 12, 0, 0, 0, time.UTC), latitude, longitude)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// near the equinox the Sun culminates at about 90° minus the latitude,
+	// so the shadow is about as long as the object and points north
+	length, bearing, err := Shadow(noon, latitude, longitude, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	elevation := SunPosition(noon, latitude, longitude).Altitude
+	if !near(length, 2/math.Tan(elevation*math.Pi/180), 1e-9) || !near(length, 2, 0.05) {
+		t.Errorf("length = %v at an elevation of %v", length, elevation)
+	}
+	if !near(bearing, 0, 0.5) && !near(bearing, 360, 0.5) {
+		t.Errorf("bearing = %v, want north", bearing)
+	}
+
+	_, bearing, _ = Shadow(noon, latitude, longitude, 2, WithAzimuth(SouthClockwise))
+	if !near(bearing, 180, 0.5) {
+		t.Errorf("bearing from the south = %v, want 180", bearing)
+	}
+
+	// in the morning the shadow points west of north
+	_, bearing, _ = Shadow(noon.Add(-3*time.Hour), latitude, longitude, 2)
+	if bearing < 270 || bearing > 315 {
+		t.Errorf("morning bearing = %v, want north-west", bearing)
+	}
+
+	if _, _, err := Shadow(noon.Add(12*time.Hour), latitude, longitude, 2); err != ErrSunBelowHorizon {
+		t.Errorf("shadow at midnight: %v, want ErrSunBelowHorizon", err)
+	}
+}
@@ -0,0 +1,123 @@
+package sunevent
+
+import (
+	"math"
+	"time"
+)
+
+// Bounds is an axis-aligned box in the observer's ENU frame (x east,
+// y north, z up), in any length unit.
+type Bounds struct {
+	Min, Max [3]float64
+}
+
+// LightProjection describes a directional light camera for rendering a
+// shadow map of a scene lit by the Sun. All vectors are in the ENU frame
+// of Bounds; the extents are in the light camera's view space and can be
+// passed directly to an orthographic projection such as glOrtho.
+type LightProjection struct {
+	// Direction is the unit vector the light travels along, from the Sun
+	// towards the scene.
+	Direction [3]float64
+	// Eye, Target and Up define the light camera's view (look-at) matrix.
+	Eye    [3]float64
+	Target [3]float64
+	Up     [3]float64
+
+	Left, Right, Bottom, Top, Near, Far float64
+
+	// BelowHorizon reports that the Sun is below the horizon; the
+	// projection is still valid but the scene should not be sun-lit.
+	BelowHorizon bool
+}
+
+// ShadowProjection returns a light camera that tightly encloses scene as
+// lit by the Sun at the given instant and location.
+func ShadowProjection(t time.Time, latitude, longitude float64, scene Bounds, opts ...Option) LightProjection {
+	// the scene is always in the local frame
+	opts = append(opts[:len(opts):len(opts)], WithFrame(ENU))
+
+	var sun [3]float64
+	sun[0], sun[1], sun[2] = SunVector(t, latitude, longitude, opts...)
+
+	forward := vecScale(sun, -1.0)
+
+	// any reference not parallel to the light works; use north when the
+	// Sun is close to the zenith or nadir
+	ref := [3]float64{0, 0, 1}
+	if math.Abs(forward[2]) > 0.99 {
+		ref = [3]float64{0, 1, 0}
+	}
+	right := vecNormalize(vecCross(forward, ref))
+	up := vecCross(right, forward)
+
+	var center [3]float64
+	for i := range center {
+		center[i] = (scene.Min[i] + scene.Max[i]) / 2.0
+	}
+	radius := vecLength(vecSub(scene.Max, scene.Min)) / 2.0
+	eye := vecAdd(center, vecScale(sun, radius))
+
+	p := LightProjection{
+		Direction:    forward,
+		Eye:          eye,
+		Target:       center,
+		Up:           up,
+		Left:         math.Inf(1),
+		Right:        math.Inf(-1),
+		Bottom:       math.Inf(1),
+		Top:          math.Inf(-1),
+		Near:         math.Inf(1),
+		Far:          math.Inf(-1),
+		BelowHorizon: sun[2] < 0,
+	}
+
+	// extents of the eight corners in light view space
+	for i := 0; i < 8; i++ {
+		corner := scene.Min
+		for axis := 0; axis < 3; axis++ {
+			if i&(1<<uint(axis)) != 0 {
+				corner[axis] = scene.Max[axis]
+			}
+		}
+		v := vecSub(corner, eye)
+		x, y, z := vecDot(v, right), vecDot(v, up), vecDot(v, forward)
+		p.Left, p.Right = math.Min(p.Left, x), math.Max(p.Right, x)
+		p.Bottom, p.Top = math.Min(p.Bottom, y), math.Max(p.Top, y)
+		p.Near, p.Far = math.Min(p.Near, z), math.Max(p.Far, z)
+	}
+
+	return p
+}
+
+func vecAdd(a, b [3]float64) [3]float64 {
+	return [3]float64{a[0] + b[0], a[1] + b[1], a[2] + b[2]}
+}
+
+func vecSub(a, b [3]float64) [3]float64 {
+	return [3]float64{a[0] - b[0], a[1] - b[1], a[2] - b[2]}
+}
+
+func vecScale(a [3]float64, s float64) [3]float64 {
+	return [3]float64{a[0] * s, a[1] * s, a[2] * s}
+}
+
+func vecDot(a, b [3]float64) float64 {
+	return a[0]*b[0] + a[1]*b[1] + a[2]*b[2]
+}
+
+func vecCross(a, b [3]float64) [3]float64 {
+	return [3]float64{
+		a[1]*b[2] - a[2]*b[1],
+		a[2]*b[0] - a[0]*b[2],
+		a[0]*b[1] - a[1]*b[0],
+	}
+}
+
+func vecLength(a [3]float64) float64 {
+	return math.Sqrt(vecDot(a, a))
+}
+
+func vecNormalize(a [3]float64) [3]float64 {
+	return vecScale(a, 1.0/vecLength(a))
+}
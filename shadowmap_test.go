@@ -0,0 +1,50 @@
+package sunevent
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestShadowProjection(t *testing.T) {
+	scene := Bounds{Min: [3]float64{-10, -20, 0}, Max: [3]float64{10, 20, 15}}
+	const latitude, longitude = 22.63, 120.30
+
+	day := time.Date(2026, time.June, 21, 3, 0, 0, 0, time.UTC)
+	p := ShadowProjection(day, latitude, longitude, scene)
+	if p.BelowHorizon {
+		t.Fatal("the Sun is below the horizon at 11:00 in Kaohsiung")
+	}
+
+	var sun [3]float64
+	sun[0], sun[1], sun[2] = SunVector(day, latitude, longitude)
+	for i := range sun {
+		if !near(p.Direction[i], -sun[i], 1e-12) {
+			t.Fatalf("Direction = %v, want the opposite of %v", p.Direction, sun)
+		}
+	}
+	if vecDot(p.Up, p.Direction) > 1e-12 || !near(vecLength(p.Up), 1, 1e-12) {
+		t.Errorf("Up %v is not a unit vector across the light", p.Up)
+	}
+	if p.Target != [3]float64{0, 0, 7.5} {
+		t.Errorf("Target = %v, want the centre of the scene", p.Target)
+	}
+
+	// the box encloses the scene: its extents span the diagonal at most
+	// and the near plane is in front of the eye
+	diagonal := vecLength(vecSub(scene.Max, scene.Min))
+	if p.Near < -1e-9 || p.Far-p.Near > diagonal+1e-9 || p.Right-p.Left > diagonal+1e-9 || p.Top-p.Bottom > diagonal+1e-9 {
+		t.Errorf("extents %+v do not fit a scene of diagonal %v", p, diagonal)
+	}
+
+	// a projection from a WithFrame(ECEF) caller is still in ENU
+	q := ShadowProjection(day, latitude, longitude, scene, WithFrame(ECEF))
+	if q != p {
+		t.Errorf("ECEF option changed the projection: %+v", q)
+	}
+
+	night := ShadowProjection(time.Date(2026, time.June, 21, 15, 0, 0, 0, time.UTC), latitude, longitude, scene)
+	if !night.BelowHorizon || math.IsInf(night.Far, 0) {
+		t.Errorf("night projection = %+v, want a finite one below the horizon", night)
+	}
+}
@@ -0,0 +1,58 @@
+package sunevent
+
+import (
+	"time"
+)
+
+// Sky answers the questions an automation asks about the Sun at one place.
+// Code that depends on a Sky rather than on the package functions can be
+// tested with the scripted fake of package suneventtest.
+type Sky interface {
+	// EventOn returns the event of type e on the civil date of date, in
+	// the time zone of date.
+	EventOn(e EventType, date time.Time) (Event, error)
+
+	// EventsBetween returns the events of the given types, or of every
+	// type if none are given, in [from, to) in chronological order.
+	EventsBetween(from, to time.Time, types ...EventType) []Event
+
+	// PhaseAt returns the phase of daylight at t.
+	PhaseAt(t time.Time) PhaseKind
+}
+
+// Observer is a location on the Earth with the options for calculations
+// there. It implements Sky with the package functions.
+type Observer struct {
+	Latitude  float64
+	Longitude float64
+
+	// Elevation is the height above sea level in metres; see
+	// WithObserverElevation.
+	Elevation float64
+
+	Options []Option
+}
+
+// EventOn implements Sky using EventOn.
+func (o Observer) EventOn(e EventType, date time.Time) (Event, error) {
+	return EventOn(e, date, o.Latitude, o.Longitude, o.options()...)
+}
+
+// EventsBetween implements Sky like EventsBetween, applying the options.
+func (o Observer) EventsBetween(from, to time.Time, types ...EventType) []Event {
+	return eventsBetween(o.Latitude, o.Longitude, from, to, types, o.options())
+}
+
+// PhaseAt implements Sky using PhaseAt.
+func (o Observer) PhaseAt(t time.Time) PhaseKind {
+	return PhaseAt(t, o.Latitude, o.Longitude, o.options()...)
+}
+
+// options returns the options of o, after WithObserverElevation if o is
+// elevated.
+func (o Observer) options() []Option {
+	if o.Elevation == 0 {
+		return o.Options
+	}
+	return append([]Option{WithObserverElevation(o.Elevation)}, o.Options...)
+}
@@ -0,0 +1,29 @@
+package sunevent
+
+import (
+	"testing"
+	"time"
+)
+
+func TestObserver(t *testing.T) {
+	taipei := time.FixedZone("CST", 8*3600)
+	date := time.Date(2026, time.March, 20, 12, 0, 0, 0, taipei)
+	var sky Sky = Observer{Latitude: 22.63, Longitude: -120.30, Options: []Option{WithLongitudeConvention(WestPositive)}}
+
+	ev, err := sky.EventOn(Sunrise, date)
+	rise, _ := SunRiseOn(date, 22.63, 120.30)
+	if err != nil || !ev.Time.Equal(rise) {
+		t.Errorf("EventOn = %+v, %v, want %s", ev, err, rise)
+	}
+
+	from := time.Date(2026, time.March, 20, 0, 0, 0, 0, taipei)
+	got := sky.EventsBetween(from, from.AddDate(0, 0, 1), Sunrise, Sunset)
+	want := EventsBetween(22.63, 120.30, from, from.AddDate(0, 0, 1), Sunrise, Sunset)
+	if len(got) != len(want) || !got[0].Time.Equal(want[0].Time) || !got[1].Time.Equal(want[1].Time) {
+		t.Errorf("EventsBetween = %+v, want %+v", got, want)
+	}
+
+	if p := sky.PhaseAt(date); p != PhaseDay {
+		t.Errorf("PhaseAt noon = %v", p)
+	}
+}
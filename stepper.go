@@ -0,0 +1,229 @@
+package sunevent
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// StepperAxis describes one stepper-driven axis of a tracker mount. Angles
+// are in degrees: azimuth clockwise from north, elevation above the
+// horizon.
+type StepperAxis struct {
+	// StepsPerRev is the number of motor steps per motor revolution,
+	// including microstepping.
+	StepsPerRev float64
+	// GearRatio is the number of motor revolutions per revolution of the
+	// axis. Zero means a direct drive.
+	GearRatio float64
+
+	// Min and Max are the travel limits of the axis. An azimuth axis may
+	// span more than a full turn, such as -270 to 270 for a cable wrap.
+	// If Min is not below Max the axis is unlimited.
+	Min, Max float64
+
+	// Park is the angle the axis rests at while the mount is parked.
+	Park float64
+
+	// Backlash is the play in the gear train. It is taken up with extra
+	// steps whenever the axis reverses.
+	Backlash float64
+}
+
+// StepperDrive converts positions of the Sun into motor step targets for
+// a two-axis (azimuth and elevation) tracker or heliostat. It remembers the
+// last target of each axis to choose the short way round and compensate
+// backlash, so a StepperDrive must not be copied after first use or shared
+// between goroutines.
+//
+// Each axis starts at its Park angle, having last moved forward.
+type StepperDrive struct {
+	Azimuth, Elevation StepperAxis
+
+	// ParkBelow is the elevation of the Sun in degrees below which the
+	// mount goes to its park angles, such as 0 to park at sunset or a few
+	// degrees above to stow before the Sun reaches obstructions.
+	ParkBelow float64
+
+	// Heliostat makes the mount aim a mirror that reflects the Sun towards
+	// Receiver, the direction of the receiver as seen from the mirror, in
+	// degrees clockwise from north and above the horizon. The mirror
+	// normal bisects the directions to the Sun and to the receiver.
+	Heliostat bool
+	Receiver  Position
+
+	az, el axisState
+}
+
+// StepperTarget is a command for a StepperDrive: the angle each axis
+// should point at and the absolute motor step count that reaches it.
+type StepperTarget struct {
+	Azimuth, Elevation float64
+
+	AzimuthSteps, ElevationSteps int64
+
+	// Parked is set when the Sun is below ParkBelow and the axes were
+	// sent to their park angles.
+	Parked bool
+
+	// Limited is set when an axis could not reach the wanted angle and
+	// was stopped at a travel limit.
+	Limited bool
+}
+
+// axisState is the last target of an axis.
+type axisState struct {
+	started bool
+	angle   float64
+	reverse bool
+}
+
+// Target returns the step targets that point the mount at the Sun at
+// position p, given in the package defaults: degrees, azimuth clockwise
+// from north and elevation above the horizon.
+func (d *StepperDrive) Target(p Position) StepperTarget {
+	if p.Altitude < d.ParkBelow {
+		return StepperTarget{
+			Azimuth:        d.Azimuth.Park,
+			Elevation:      d.Elevation.Park,
+			AzimuthSteps:   d.Azimuth.move(&d.az, d.Azimuth.Park),
+			ElevationSteps: d.Elevation.move(&d.el, d.Elevation.Park),
+			Parked:         true,
+		}
+	}
+
+	aim := p
+	if d.Heliostat {
+		aim = bisector(p, d.Receiver)
+	}
+
+	az, azLimited := d.Azimuth.azimuth(&d.az, aim.Azimuth)
+	el, elLimited := d.Elevation.clamp(aim.Altitude)
+	return StepperTarget{
+		Azimuth:        az,
+		Elevation:      el,
+		AzimuthSteps:   d.Azimuth.move(&d.az, az),
+		ElevationSteps: d.Elevation.move(&d.el, el),
+		Limited:        azLimited || elLimited,
+	}
+}
+
+// TickSteps streams step targets for the mount at the given location every
+// interval until ctx is cancelled, on top of TickPosition. The drive must
+// not be used elsewhere while the stream runs.
+func TickSteps(ctx context.Context, latitude, longitude float64, interval time.Duration, d *StepperDrive, opts ...Option) <-chan StepperTarget {
+	// the drive works in the default conventions whatever the caller's
+	// options for positions
+	opts = append(opts[:len(opts):len(opts)], WithAzimuth(NorthClockwise), WithVerticalAngle(ElevationAngle), WithUnits(Degrees))
+	positions := TickPosition(ctx, latitude, longitude, interval, opts...)
+
+	ch := make(chan StepperTarget, 1)
+	go func() {
+		defer close(ch)
+		for p := range positions {
+			select {
+			case ch <- d.Target(p):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// stepsPerDegree returns the motor steps per degree of axis rotation.
+func (a StepperAxis) stepsPerDegree() float64 {
+	ratio := a.GearRatio
+	if ratio == 0 {
+		ratio = 1
+	}
+	return a.StepsPerRev * ratio / 360.0
+}
+
+// limited reports whether the axis has travel limits.
+func (a StepperAxis) limited() bool {
+	return a.Min < a.Max
+}
+
+// clamp limits angle to the travel of the axis.
+func (a StepperAxis) clamp(angle float64) (float64, bool) {
+	if !a.limited() {
+		return angle, false
+	}
+	if angle < a.Min {
+		return a.Min, true
+	}
+	if angle > a.Max {
+		return a.Max, true
+	}
+	return angle, false
+}
+
+// azimuth returns the turn of azimuth, that is azimuth plus a multiple of
+// 360°, within the limits and closest to the last target of the axis. If
+// no turn is within the limits it returns the closest limit.
+func (a StepperAxis) azimuth(s *axisState, azimuth float64) (float64, bool) {
+	current := a.Park
+	if s.started {
+		current = s.angle
+	}
+	nearest := current + wrapDifference(azimuth, current, 360)
+	if !a.limited() {
+		return nearest, false
+	}
+
+	best, bestLimited := 0.0, true
+	bestCost := math.Inf(1)
+	for turn := math.Floor((a.Min-nearest)/360) - 1; nearest+turn*360 <= a.Max+360; turn++ {
+		candidate, limited := a.clamp(nearest + turn*360)
+		// reaching the Sun beats stopping at a limit, then the shortest move
+		cost := math.Abs(candidate - current)
+		if limited {
+			cost += 1e6
+		}
+		if cost < bestCost {
+			best, bestLimited, bestCost = candidate, limited, cost
+		}
+	}
+	return best, bestLimited
+}
+
+// move records angle as the new target of the axis and returns the
+// absolute motor step count for it. While the axis moves backwards the
+// gear train rests on the other side of its play, so the count is offset
+// by the backlash; reversing direction thus adds the backlash to the move.
+func (a StepperAxis) move(s *axisState, angle float64) int64 {
+	if !s.started {
+		s.started, s.angle = true, a.Park
+	}
+	if angle > s.angle {
+		s.reverse = false
+	} else if angle < s.angle {
+		s.reverse = true
+	}
+	s.angle = angle
+
+	if s.reverse {
+		angle -= a.Backlash
+	}
+	return int64(math.Round(angle * a.stepsPerDegree()))
+}
+
+// bisector returns the direction halfway between the directions sun and
+// target, in degrees clockwise from north and above the horizon.
+func bisector(sun, target Position) Position {
+	n := vecNormalize(vecAdd(enuDirection(sun), enuDirection(target)))
+	return Position{
+		Azimuth:  normalizeRange(radianToDegree(math.Atan2(n[0], n[1])), 360),
+		Altitude: degreeAsin(n[2]),
+	}
+}
+
+// enuDirection returns the unit vector in the ENU frame pointing at p.
+func enuDirection(p Position) [3]float64 {
+	return [3]float64{
+		degreeCos(p.Altitude) * degreeSin(p.Azimuth),
+		degreeCos(p.Altitude) * degreeCos(p.Azimuth),
+		degreeSin(p.Altitude),
+	}
+}
@@ -0,0 +1,71 @@
+package sunevent
+
+import (
+	"testing"
+)
+
+func TestStepperDriveTarget(t *testing.T) {
+	d := StepperDrive{
+		// 200 steps with 16 microsteps through a 100:1 gear: 888.9 steps a degree
+		Azimuth:   StepperAxis{StepsPerRev: 3200, GearRatio: 100, Min: -270, Max: 270, Park: 180},
+		Elevation: StepperAxis{StepsPerRev: 3200, GearRatio: 100, Min: 0, Max: 90, Park: 90, Backlash: 0.1},
+	}
+
+	// the first target is reached from the park angles
+	got := d.Target(Position{Azimuth: 120, Altitude: 10})
+	if got.Azimuth != 120 || got.Elevation != 10 || got.AzimuthSteps != 106667 || got.Parked || got.Limited {
+		t.Errorf("first target = %+v", got)
+	}
+	// moving down from the park angle takes up the backlash
+	if got.ElevationSteps != 8800 {
+		t.Errorf("elevation steps = %d, want 8800", got.ElevationSteps)
+	}
+
+	// the azimuth goes the short way round through north, beyond 360 if
+	// the cable wrap allows it, and stops at the limit otherwise
+	d.Target(Position{Azimuth: 250, Altitude: 30})
+	if got := d.Target(Position{Azimuth: 10, Altitude: 30}); got.Azimuth != 10 {
+		t.Errorf("250° then 10° = %v, want 10", got.Azimuth)
+	}
+	d.Target(Position{Azimuth: 200, Altitude: 30})
+	d.Target(Position{Azimuth: 260, Altitude: 30})
+	if got := d.Target(Position{Azimuth: 300, Altitude: 30}); got.Azimuth != -60 {
+		t.Errorf("260° then 300° = %v, want -60 within the wrap", got.Azimuth)
+	}
+
+	// rising after moving down releases the backlash again
+	if got := d.Target(Position{Azimuth: 300, Altitude: 40}); got.ElevationSteps != 35556 {
+		t.Errorf("elevation steps rising = %d, want 35556", got.ElevationSteps)
+	}
+
+	// beyond the travel the axis stops at its limit
+	if got := d.Target(Position{Azimuth: 300, Altitude: 95}); !got.Limited || got.Elevation != 90 {
+		t.Errorf("elevation beyond the limit = %+v", got)
+	}
+
+	// below ParkBelow the mount parks
+	if got := d.Target(Position{Azimuth: 300, Altitude: -1}); !got.Parked || got.Azimuth != 180 || got.Elevation != 90 {
+		t.Errorf("parked target = %+v", got)
+	}
+}
+
+func TestStepperDriveHeliostat(t *testing.T) {
+	d := StepperDrive{
+		Azimuth:   StepperAxis{StepsPerRev: 360},
+		Elevation: StepperAxis{StepsPerRev: 360},
+		Heliostat: true,
+		Receiver:  Position{Azimuth: 0, Altitude: 0},
+	}
+
+	// to send a Sun due south at 60° north along the horizon the mirror
+	// normal points north and 60° up, halfway between the two directions
+	got := d.Target(Position{Azimuth: 180, Altitude: 60})
+	if !near(got.Azimuth, 0, 1e-9) || !near(got.Elevation, 60, 1e-9) {
+		t.Errorf("mirror normal = %v, %v, want 0, 60", got.Azimuth, got.Elevation)
+	}
+
+	got = d.Target(Position{Azimuth: 90, Altitude: 0})
+	if !near(got.Azimuth, 45, 1e-9) || !near(got.Elevation, 0, 1e-9) {
+		t.Errorf("mirror normal = %v, %v, want 45, 0", got.Azimuth, got.Elevation)
+	}
+}
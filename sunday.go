@@ -0,0 +1,199 @@
+package sunevent
+
+import (
+	"time"
+)
+
+// SunDay holds the solar events of one civil date. Events that do not
+// happen that day are zero; Type tells whether the missing sunrise and
+// sunset are due to midnight sun or polar night.
+type SunDay struct {
+	Date time.Time
+	Type DayType
+
+	AstronomicalDawn time.Time
+	NauticalDawn     time.Time
+	CivilDawn        time.Time
+	Sunrise          time.Time
+	SolarNoon        time.Time
+	Sunset           time.Time
+	CivilDusk        time.Time
+	NauticalDusk     time.Time
+	AstronomicalDusk time.Time
+
+	// DayLength is the time between sunrise and sunset of the daylight
+	// period around SolarNoon, as returned by DaylightOn: 24 hours on a
+	// polar day and zero during polar night.
+	DayLength time.Duration
+
+	// Inverted is set when Sunset comes before Sunrise, because the civil
+	// date cuts through a daylight period; see DaylightOn.
+	Inverted bool
+
+	// Warning is set when the date or location lies outside the validated
+	// envelope of the algorithm; see CheckEnvelope.
+	Warning error
+}
+
+// SunDayOn returns the solar events on the civil date of date, in the time
+// zone of date. It never fails: at polar latitudes the missing events are
+// left zero and Type reports PolarDay or PolarNight.
+func SunDayOn(date time.Time, latitude, longitude float64, opts ...Option) SunDay {
+	d := SunDay{
+		Date:    newOptions(opts).midnight(date),
+		Type:    dayType(date, latitude, longitude, opts),
+		Warning: CheckEnvelope(date, latitude, opts...),
+	}
+
+	fields := map[EventType]*time.Time{
+		AstronomicalDawn: &d.AstronomicalDawn,
+		NauticalDawn:     &d.NauticalDawn,
+		CivilDawn:        &d.CivilDawn,
+		Sunrise:          &d.Sunrise,
+		SolarNoon:        &d.SolarNoon,
+		Sunset:           &d.Sunset,
+		CivilDusk:        &d.CivilDusk,
+		NauticalDusk:     &d.NauticalDusk,
+		AstronomicalDusk: &d.AstronomicalDusk,
+	}
+	for e, field := range fields {
+		if t, err := e.On(date, latitude, longitude, opts...); err == nil {
+			*field = t
+		}
+	}
+
+	switch d.Type {
+	case NormalDay:
+		if l, err := DaylightOn(date, latitude, longitude, opts...); err == nil {
+			d.DayLength = l.Sunset.Sub(l.Sunrise)
+			d.Inverted = l.Inverted
+		}
+	case PolarDay:
+		d.DayLength = 24 * time.Hour
+	}
+
+	return d
+}
+
+// YearCalendar returns the SunDay of every date of year in loc, in order:
+// the backbone of printed almanacs and of exports to other formats. Days
+// of midnight sun and polar night are included with their Type set and the
+// missing events zero.
+func YearCalendar(year int, latitude, longitude float64, loc *time.Location, opts ...Option) []SunDay {
+	var days []SunDay
+	// noon keeps the date away from daylight saving transitions
+	for date := time.Date(year, time.January, 1, 12, 0, 0, 0, loc); date.Year() == year; date = date.AddDate(0, 0, 1) {
+		days = append(days, SunDayOn(date, latitude, longitude, opts...))
+	}
+	return days
+}
+
+// DaylightDelta returns how much longer the day is on the civil date of
+// date than on the day before, negative when the days are shortening, as
+// in "today is 2m14s longer than yesterday". Day lengths are those of
+// SunDay: 24 hours on a polar day and zero during polar night.
+func DaylightDelta(date time.Time, latitude, longitude float64, opts ...Option) time.Duration {
+	yesterday := time.Date(date.Year(), date.Month(), date.Day()-1, 12, 0, 0, 0, date.Location())
+	return dayLength(date, latitude, longitude, opts) - dayLength(yesterday, latitude, longitude, opts)
+}
+
+// dayLength returns the DayLength of SunDayOn without the other events.
+func dayLength(date time.Time, latitude, longitude float64, opts []Option) time.Duration {
+	switch dayType(date, latitude, longitude, opts) {
+	case PolarDay:
+		return 24 * time.Hour
+	case PolarNight:
+		return 0
+	}
+	l, err := DaylightOn(date, latitude, longitude, opts...)
+	if err != nil {
+		return 0
+	}
+	return l.Sunset.Sub(l.Sunrise)
+}
+
+// DayLengths returns the length of daylight of every date of year, at
+// midnight UTC, for charts and crop models. Rather than finding each
+// sunrise and sunset it evaluates the position of the Sun once a day, at
+// local noon, and takes the day length from the hour angle of the horizon,
+// twice H over 15° an hour. This takes about a tenth of the time, and the
+// results are within 20 seconds of SunDayOn outside the polar circles.
+// Beyond them, in the weeks before and after polar day or night, where
+// the change of the Sun's declination between noon and the horizon
+// crossings matters most, they may differ by up to 45 minutes. Polar
+// days count 24 hours and polar nights zero. Custom algorithms are not
+// consulted.
+func DayLengths(year int, latitude, longitude float64, opts ...Option) []time.Duration {
+	o := newOptions(opts)
+	longitude = o.longitude(longitude)
+	zenith := 90.0 - o.sunriseAltitude()
+
+	var lengths []time.Duration
+	for date := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC); date.Year() == year; date = date.AddDate(0, 0, 1) {
+		noon := localMeanTime(date, longitude, 12.0)
+		_, _, cosH := horizonCosH(noon, latitude, zenith)
+		switch {
+		case cosH > 1.0:
+			lengths = append(lengths, 0)
+		case cosH < -1.0:
+			lengths = append(lengths, 24*time.Hour)
+		default:
+			hours := 2.0 * degreeAcos(cosH) / 15.0
+			lengths = append(lengths, time.Duration(hours*float64(time.Hour)).Round(time.Second))
+		}
+	}
+	return lengths
+}
+
+// Daylight is a period when the Sun is above the horizon, from a sunrise to
+// the following sunset.
+type Daylight struct {
+	Sunrise time.Time
+	Sunset  time.Time
+
+	// Inverted is set when the sunset on the civil date came before its
+	// sunrise, so one of the pair was taken from a neighbouring date.
+	Inverted bool
+}
+
+// DaylightOn returns the daylight period around solar noon on the civil
+// date of date, in the time zone of date. The sunrise and sunset on a
+// civil date usually form such a pair, but where the time zone is far from
+// the local mean time, as near the date line, or at high latitudes where
+// the Sun sets after midnight, the date can hold the sunset of one period
+// and the sunrise of the next. DaylightOn then takes the sunrise from the
+// day before or the sunset from the day after, so that Sunrise always
+// comes before Sunset, and sets Inverted.
+//
+// It returns ErrSunNeverRises or ErrSunNeverSets if the Sun does not cross
+// the horizon on a date it needs.
+func DaylightOn(date time.Time, latitude, longitude float64, opts ...Option) (Daylight, error) {
+	noon, _ := SolarNoonOn(date, latitude, longitude, opts...)
+
+	sunrise, err := SunRiseOn(date, latitude, longitude, opts...)
+	if err != nil {
+		return Daylight{}, err
+	}
+	sunset, err := SunSetOn(date, latitude, longitude, opts...)
+	if err != nil {
+		return Daylight{}, err
+	}
+	d := Daylight{Sunrise: sunrise, Sunset: sunset}
+
+	loc := date.Location()
+	if sunrise.After(noon) {
+		before := time.Date(date.Year(), date.Month(), date.Day()-1, 12, 0, 0, 0, loc)
+		if d.Sunrise, err = SunRiseOn(before, latitude, longitude, opts...); err != nil {
+			return Daylight{}, err
+		}
+		d.Inverted = true
+	}
+	if sunset.Before(noon) {
+		after := time.Date(date.Year(), date.Month(), date.Day()+1, 12, 0, 0, 0, loc)
+		if d.Sunset, err = SunSetOn(after, latitude, longitude, opts...); err != nil {
+			return Daylight{}, err
+		}
+		d.Inverted = true
+	}
+	return d, nil
+}
@@ -0,0 +1,190 @@
+package sunevent
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSunDayOn(t *testing.T) {
+	oslo := time.FixedZone("CET", 3600)
+	tests := []struct {
+		name      string
+		date      time.Time
+		latitude  float64
+		longitude float64
+		kind      DayType
+		length    time.Duration
+	}{
+		{"Oslo in March", time.Date(2026, time.March, 20, 12, 0, 0, 0, oslo), 59.91, 10.75, NormalDay, 0},
+		{"Longyearbyen in June", time.Date(2026, time.June, 21, 12, 0, 0, 0, oslo), 78.22, 15.65, PolarDay, 24 * time.Hour},
+		{"Longyearbyen in December", time.Date(2026, time.December, 21, 12, 0, 0, 0, oslo), 78.22, 15.65, PolarNight, 0},
+	}
+	for _, tt := range tests {
+		d := SunDayOn(tt.date, tt.latitude, tt.longitude)
+		if d.Type != tt.kind {
+			t.Errorf("%s: type %v, want %v", tt.name, d.Type, tt.kind)
+		}
+		if !d.Date.Equal(time.Date(tt.date.Year(), tt.date.Month(), tt.date.Day(), 0, 0, 0, 0, oslo)) {
+			t.Errorf("%s: date %s, want the start of the day", tt.name, d.Date)
+		}
+		if d.SolarNoon.IsZero() {
+			t.Errorf("%s: no solar noon", tt.name)
+		}
+
+		switch tt.kind {
+		case NormalDay:
+			rise, _ := SunRiseOn(tt.date, tt.latitude, tt.longitude)
+			set, _ := SunSetOn(tt.date, tt.latitude, tt.longitude)
+			if !d.Sunrise.Equal(rise) || !d.Sunset.Equal(set) || d.DayLength != set.Sub(rise) {
+				t.Errorf("%s: %s–%s (%v), want %s–%s", tt.name, d.Sunrise, d.Sunset, d.DayLength, rise, set)
+			}
+			if !d.AstronomicalDawn.Before(d.CivilDawn) || !d.CivilDusk.Before(d.AstronomicalDusk) {
+				t.Errorf("%s: twilights out of order: %+v", tt.name, d)
+			}
+		default:
+			if !d.Sunrise.IsZero() || !d.Sunset.IsZero() || d.DayLength != tt.length {
+				t.Errorf("%s: sunrise %s, sunset %s, length %v, want none and %v", tt.name, d.Sunrise, d.Sunset, d.DayLength, tt.length)
+			}
+		}
+	}
+}
+
+func TestDaylightOn(t *testing.T) {
+	// an ordinary date holds its own sunrise and sunset
+	taipei := time.FixedZone("CST", 8*3600)
+	date := time.Date(2026, time.March, 20, 12, 0, 0, 0, taipei)
+	d, err := DaylightOn(date, 22.63, 120.30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rise, _ := SunRiseOn(date, 22.63, 120.30)
+	set, _ := SunSetOn(date, 22.63, 120.30)
+	if d.Inverted || !d.Sunrise.Equal(rise) || !d.Sunset.Equal(set) {
+		t.Errorf("Taipei: %+v, want %s–%s", d, rise, set)
+	}
+
+	// eleven hours ahead of the local mean time solar noon comes at 23:00,
+	// and the sunset of the date, at about 05:00, ends the daylight of the
+	// day before
+	far := time.FixedZone("UTC+11", 11*3600)
+	date = time.Date(2026, time.March, 20, 12, 0, 0, 0, far)
+	d, err = DaylightOn(date, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	noon, _ := SolarNoonOn(date, 0, 0)
+	next, _ := SunSetOn(date.AddDate(0, 0, 1), 0, 0)
+	if !d.Inverted || !d.Sunrise.Before(noon) || !noon.Before(d.Sunset) || !d.Sunset.Equal(next) {
+		t.Errorf("UTC+11: %+v around noon %s, want the sunset %s of the next date", d, noon, next)
+	}
+	if l := d.Sunset.Sub(d.Sunrise); !near(l.Hours(), 12, 0.1) {
+		t.Errorf("UTC+11: daylight lasts %v at the equator at the equinox", l)
+	}
+
+	s := SunDayOn(date, 0, 0)
+	if !s.Inverted || s.DayLength != d.Sunset.Sub(d.Sunrise) || !s.Sunset.Before(s.Sunrise) {
+		t.Errorf("UTC+11: SunDay %+v, want inverted with the daylight of DaylightOn", s)
+	}
+}
+
+func TestDaylightDelta(t *testing.T) {
+	tests := []struct {
+		date     time.Time
+		min, max time.Duration
+	}{
+		// days lengthen fastest at the equinoxes, by 2m40s a day at 40°N
+		{time.Date(2026, time.March, 20, 12, 0, 0, 0, time.UTC), 2*time.Minute + 30*time.Second, 2*time.Minute + 50*time.Second},
+		{time.Date(2026, time.September, 23, 12, 0, 0, 0, time.UTC), -2*time.Minute - 50*time.Second, -2*time.Minute - 30*time.Second},
+		{time.Date(2026, time.June, 21, 12, 0, 0, 0, time.UTC), -10 * time.Second, 10 * time.Second},
+	}
+	for _, tt := range tests {
+		if d := DaylightDelta(tt.date, 40.7, -74); d < tt.min || d > tt.max {
+			t.Errorf("DaylightDelta(%s) = %s, want %s to %s", tt.date.Format("Jan 2"), d, tt.min, tt.max)
+		}
+	}
+
+	// the first day of midnight sun counts 24 hours
+	for date := time.Date(2026, time.April, 1, 12, 0, 0, 0, time.UTC); date.Month() == time.April; date = date.AddDate(0, 0, 1) {
+		if SunDayOn(date, 78.22, 15.65).Type != PolarDay {
+			continue
+		}
+		yesterday := SunDayOn(date.AddDate(0, 0, -1), 78.22, 15.65)
+		if d := DaylightDelta(date, 78.22, 15.65); d != 24*time.Hour-yesterday.DayLength {
+			t.Errorf("DaylightDelta(%s) = %s after a day of %s", date.Format("Jan 2"), d, yesterday.DayLength)
+		}
+		return
+	}
+	t.Error("no midnight sun in April at 78°N")
+}
+
+func TestDayLengths(t *testing.T) {
+	tests := []struct {
+		latitude  float64
+		tolerance time.Duration
+	}{
+		{0, 20 * time.Second},
+		{40.7, 20 * time.Second},
+		{65, 20 * time.Second},
+		{-45, 20 * time.Second},
+		{78.22, 45 * time.Minute},
+		{-78, 45 * time.Minute},
+	}
+	for _, tt := range tests {
+		lengths := DayLengths(2026, tt.latitude, 15)
+		if len(lengths) != 365 {
+			t.Fatalf("latitude %v: %d day lengths", tt.latitude, len(lengths))
+		}
+		for i, l := range lengths {
+			date := time.Date(2026, time.January, 1+i, 12, 0, 0, 0, time.UTC)
+			if want := SunDayOn(date, tt.latitude, 15).DayLength; absDuration(l-want) > tt.tolerance {
+				t.Errorf("latitude %v, %s: %s, SunDayOn %s", tt.latitude, date.Format("Jan 2"), l, want)
+			}
+		}
+	}
+
+	// midnight sun and polar night at Longyearbyen
+	lengths := DayLengths(2028, 78.22, 15.65)
+	if len(lengths) != 366 {
+		t.Fatalf("%d day lengths in 2028", len(lengths))
+	}
+	if june, december := lengths[172], lengths[355]; june != 24*time.Hour || december != 0 {
+		t.Errorf("21 June %s, 21 December %s", june, december)
+	}
+}
+
+func TestYearCalendar(t *testing.T) {
+	longyearbyen := time.FixedZone("CET", 3600)
+	days := YearCalendar(2028, 78.22, 15.65, longyearbyen)
+	if len(days) != 366 {
+		t.Fatalf("%d days in 2028", len(days))
+	}
+
+	var polarDays, polarNights int
+	for i, d := range days {
+		if want := time.Date(2028, time.January, 1+i, 0, 0, 0, 0, longyearbyen); !d.Date.Equal(want) {
+			t.Fatalf("day %d: Date %s, want %s", i, d.Date, want)
+		}
+		switch d.Type {
+		case PolarDay:
+			polarDays++
+			if d.DayLength != 24*time.Hour || d.SolarNoon.IsZero() {
+				t.Errorf("%s: midnight sun with noon %s, day of %s", d.Date.Format("Jan 2"), d.SolarNoon, d.DayLength)
+			}
+		case PolarNight:
+			polarNights++
+			if d.DayLength != 0 {
+				t.Errorf("%s: polar night with a day of %s", d.Date.Format("Jan 2"), d.DayLength)
+			}
+		}
+	}
+	// the midnight sun and the polar night last about four months each
+	if polarDays < 115 || polarDays > 130 || polarNights < 110 || polarNights > 125 {
+		t.Errorf("%d days of midnight sun, %d of polar night", polarDays, polarNights)
+	}
+
+	// each entry is the SunDay of its date
+	want := SunDayOn(time.Date(2028, time.March, 1, 8, 0, 0, 0, longyearbyen), 78.22, 15.65)
+	if d := days[60]; !d.Sunrise.Equal(want.Sunrise) || !d.Sunset.Equal(want.Sunset) || d.DayLength != want.DayLength {
+		t.Errorf("1 March: %+v, want %+v", d, want)
+	}
+}
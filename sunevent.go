@@ -1,6 +1,7 @@
 package sunevent
 
 import (
+	"errors"
 	"math"
 	"time"
 )
@@ -8,23 +9,167 @@ import (
 // Reference
 // https://github.com/BigZaphod/CLLocation-SunriseSunset/blob/master/CLLocation%2BSunriseSunset.m
 
+var (
+	ErrSunNeverRises = errors.New("sunevent: the sun never rises on this date")
+	ErrSunNeverSets  = errors.New("sunevent: the sun never sets on this date")
+)
+
+// SunRise returns today's sunrise at the location, in the host time zone.
+// It returns the zero time where the Sun does not rise today or the
+// coordinates are invalid; use SunRiseOn or SunDayOn to tell the cases
+// apart.
 func SunRise(latitude, longitude float64) time.Time {
-	return sunRiseSet(true, latitude, longitude, 90.0)
+	return today(SunRiseOn, latitude, longitude)
 }
 
+// SunSet returns today's sunset at the location, in the host time zone.
+// Like SunRise it returns the zero time when there is no answer.
 func SunSet(latitude, longitude float64) time.Time {
-	return sunRiseSet(false, latitude, longitude, 90.0)
+	return today(SunSetOn, latitude, longitude)
 }
 
+// Dawn returns today's dawn at the location, in the host time zone. Like
+// SunRise it returns the zero time when there is no answer.
 func Dawn(latitude, longitude float64) time.Time {
-	return sunRiseSet(true, latitude, longitude, 83.0)
+	return today(DawnOn, latitude, longitude)
 }
 
+// Dusk returns today's dusk at the location, in the host time zone. Like
+// SunRise it returns the zero time when there is no answer.
 func Dusk(latitude, longitude float64) time.Time {
-	return sunRiseSet(false, latitude, longitude, 83.0)
+	return today(DuskOn, latitude, longitude)
+}
+
+// SunRiseOn returns the sunrise on the civil date of date, in the time
+// zone of date.
+func SunRiseOn(date time.Time, latitude, longitude float64, opts ...Option) (time.Time, error) {
+	o := newOptions(opts)
+	return TimeAtAltitude(date, latitude, longitude, o.sunriseAltitude(), true, opts...)
+}
+
+// SunSetOn returns the sunset on the civil date of date, in the time zone
+// of date.
+func SunSetOn(date time.Time, latitude, longitude float64, opts ...Option) (time.Time, error) {
+	o := newOptions(opts)
+	return TimeAtAltitude(date, latitude, longitude, o.sunriseAltitude(), false, opts...)
+}
+
+// DawnOn returns the dawn on the civil date of date, in the time zone of
+// date.
+func DawnOn(date time.Time, latitude, longitude float64, opts ...Option) (time.Time, error) {
+	return TimeAtAltitude(date, latitude, longitude, 90.0-83.0, true, opts...)
+}
+
+// DuskOn returns the dusk on the civil date of date, in the time zone of
+// date.
+func DuskOn(date time.Time, latitude, longitude float64, opts ...Option) (time.Time, error) {
+	return TimeAtAltitude(date, latitude, longitude, 90.0-83.0, false, opts...)
+}
+
+// TimeAtAltitude returns the time on the civil date of date when the Sun
+// passes the given altitude in degrees above the horizon, rising in the
+// morning or setting in the evening. The result is in the time zone of
+// date. It returns ErrSunNeverRises if the Sun stays below the altitude
+// all day and ErrSunNeverSets if it stays above.
+func TimeAtAltitude(date time.Time, latitude, longitude, altitude float64, rising bool, opts ...Option) (time.Time, error) {
+	o := newOptions(opts)
+	longitude = o.longitude(longitude)
+	zenith := 90.0 - altitude
+
+	// the built-in algorithms also follow the precision and ΔT options
+	switch a := o.algorithm(); a {
+	case Almanac:
+		return sunRiseSet(date, rising, latitude, longitude, zenith, o.Precision == High)
+	case NOAA:
+		return o.noaaRiseSet(date, rising, latitude, longitude, zenith)
+	default:
+		rise, set, err := a.SunTimes(date, latitude, longitude, zenith)
+		t := set
+		if rising {
+			t = rise
+		}
+		if t.IsZero() {
+			if err == nil {
+				err = o.polarError(date, latitude, longitude, altitude)
+			}
+			return time.Time{}, err
+		}
+		return t, nil
+	}
+}
+
+// polarError returns the error for a crossing of altitude that does not
+// happen on the civil date of date: ErrSunNeverRises if the Sun stays
+// below the altitude even at solar noon, and ErrSunNeverSets if it stays
+// above.
+func (o Options) polarError(date time.Time, latitude, longitude, altitude float64) error {
+	noon, _ := SolarNoonOn(date, latitude, longitude)
+	if _, elevation := o.sunAt(noon, latitude, longitude); elevation < altitude {
+		return ErrSunNeverRises
+	}
+	return ErrSunNeverSets
+}
+
+// today returns the event computed by on for the current date at the
+// location. The date is taken in local mean time at the longitude rather
+// than in the host time zone, so a program gets the same instant wherever
+// it runs. Invalid coordinates, missing events and panics all yield the
+// zero time.
+func today(on func(time.Time, float64, float64, ...Option) (time.Time, error), latitude, longitude float64) (t time.Time) {
+	if !(latitude >= -90 && latitude <= 90) || math.IsNaN(longitude) || math.IsInf(longitude, 0) {
+		return time.Time{}
+	}
+	defer func() {
+		if recover() != nil {
+			t = time.Time{}
+		}
+	}()
+
+	longitude = Options{}.longitude(longitude)
+	lmt := time.FixedZone("LMT", int(math.Round(longitude*240.0)))
+	t, err := on(time.Now().In(lmt), latitude, longitude)
+	if err != nil {
+		return time.Time{}
+	}
+	return t.Local()
 }
 
-func sunRiseSet(sunrise bool, latitude, longitude, zenith float64) time.Time {
+// EquationOfTime returns the difference between apparent and mean solar
+// time at the given instant. A positive value means a sundial is ahead of
+// a clock keeping local mean time.
+func EquationOfTime(date time.Time) time.Duration {
+	t := julianCentury(date)
+	_, RA, _, _ := sunCoordinates(t)
+
+	// mean longitude of the Sun, i.e. the right ascension of a fictitious
+	// Sun moving uniformly along the equator, less the aberration
+	// EoT = L0 - 0.0057183 - RA (in degrees, 4 minutes of time per degree)
+
+	Lmean := meanLongitude(t) - 0.0057183
+	eot := normalizeRange(Lmean-RA*15.0+180.0, 360) - 180.0
+
+	return time.Duration(eot * 4.0 * float64(time.Minute))
+}
+
+// SolarDeclination returns the Sun's declination in degrees at the given
+// instant, positive north of the celestial equator.
+func SolarDeclination(date time.Time) float64 {
+	_, _, sinDec, _ := sunCoordinates(julianCentury(date))
+	return degreeAsin(sinDec)
+}
+
+// SunEquatorial returns the Sun's right ascension in hours [0, 24) and
+// declination in degrees at the given instant.
+func SunEquatorial(t time.Time) (ra, dec float64) {
+	_, RA, sinDec, _ := sunCoordinates(julianCentury(t))
+	return normalizeRange(RA, 24.0), degreeAsin(sinDec)
+}
+
+// sunRiseSet computes the time on the civil date of today when the Sun
+// passes zenith with the 1990 Almanac for Computers. With refine the
+// solar parameters are recomputed at the estimated time of the event
+// until it settles, instead of once at 6h or 18h local mean time.
+func sunRiseSet(today time.Time, sunrise bool, latitude, longitude, zenith float64, refine bool) (time.Time, error) {
 
 	//zenith := 90.0
 	sunset := sunrise != true
@@ -39,71 +184,137 @@ func sunRiseSet(sunrise bool, latitude, longitude, zenith float64) time.Time {
 	// nautical     = 102 degrees
 	// astronomical = 108 degrees
 
-	// 1. first calculate the day of the year
-	// N1 = floor(275 * month / 9)
-	// N2 = floor((month + 9) / 12)
-	// N3 = (1 + floor((year - 4 * floor(year / 4) + 2) / 3))
-	// N = N1 - (N2 * N3) + day - 30
+	// 1. - 2. calculate an approximate time
+	// The reference counts the day of the year N in UT, which is a
+	// different date from the requested civil date wherever the time zone
+	// is far from the longitude (near the antimeridian). Instead take the
+	// instant within the civil date when local mean time is 6h or 18h.
+
+	lngHour := longitude / 15
+	guess := approximateTime(today, sunrise, longitude)
+
+	event, err := riseSetNear(guess, sunset, latitude, lngHour, zenith)
+	if err != nil {
+		return time.Time{}, err
+	}
+	for i := 0; refine && i < 8; i++ {
+		next, err := riseSetNear(event, sunset, latitude, lngHour, zenith)
+		if err != nil {
+			return time.Time{}, err
+		}
+		settled := next.Sub(event) < 100*time.Millisecond && event.Sub(next) < 100*time.Millisecond
+		event = next
+		if settled {
+			break
+		}
+	}
 
-	today := time.Now()
-	name, offset := today.Zone()
-	loc := time.FixedZone(name, offset)
-	localOffset := float64(offset) / 3600.0
-	N := float64(today.YearDay())
+	// 10. convert UT value to local time zone of latitude/longitude
+	// The event is presented in the location of today rather than at
+	// today's offset, so events after a daylight saving change carry the
+	// offset in effect.
 
-	// 2. convert the longitude to hour value and calculate an approximate time
-	// lngHour = longitude / 15
+	return event.In(today.Location()).Truncate(time.Second), nil
+}
 
-	// if rising time is desired:
-	// t = N + ((6 - lngHour) / 24)
+// riseSetNear runs steps 3 to 9 of sunRiseSet with the solar parameters
+// at guess. It returns the event at the UT time of day found that is
+// closest to guess.
+func riseSetNear(guess time.Time, sunset bool, latitude, lngHour, zenith float64) (time.Time, error) {
+	// 3. - 7a. calculate the Sun's local hour angle
+
+	t, RA, cosH := horizonCosH(guess, latitude, zenith)
+	if cosH > 1.0 {
+		return time.Time{}, ErrSunNeverRises
+	}
+	if cosH < -1.0 {
+		return time.Time{}, ErrSunNeverSets
+	}
+
+	// 7b. finish calculating H and convert into hours
+	// if if rising time is desired:
+	// H = 360 - acos(cosH)
 	// if setting time is desired:
-	// t = N + ((18 - lngHour) / 24)
+	// H = acos(cosH)
+	// H = H / 15
 
-	lngHour := longitude / 15
-	t := N + ((6 - lngHour) / 24)
+	H := 360 - degreeAcos(cosH)
 	if sunset {
-		t = N + ((18 - lngHour) / 24)
+		H = degreeAcos(cosH)
 	}
+	H = H / 15.0
+
+	// 8. calculate local mean time of rising/setting
+	// T = H + RA - (0.06571 * t) - 6.622
+	// The reference approximates the difference between sidereal time and
+	// UT from the day of the year; it is taken from the Julian centuries t
+	// instead, see siderealOffset.
 
-	// 3. calculate the Sun's mean anomaly
-	// M = (0.9856 * t) - 3.289
+	T := H + RA - siderealOffset(t)
 
-	M := (0.9856 * t) - 3.289
+	// 9. adjust back to UTC
+	// UT = T - lngHour
+	// NOTE: UT potentially needs to be adjusted into the range [0,24) by adding/subtracting 24
 
-	// 4. calculate the Sun's true longitude
-	// L = M + (1.916 * sin(M)) + (0.020 * sin(2 * M)) + 282.634
+	UT := normalizeRange(T-lngHour, 24.0)
 
-	L := M + (1.916 * degreeSin(M)) + (0.020 * degreeSin(2*M)) + 282.634
-	// NOTE: L potentially needs to be adjusted into the range [0,360) by adding/subtracting 360
-	L = normalizeRange(L, 360)
+	return nearestUT(guess, UT), nil
+}
 
-	// 5a. calculate the Sun's right ascension
-	// RA = atan(0.91764 * tan(L))
-	// NOTE: RA potentially needs to be adjusted into the range [0,360) by adding/subtracting 360
+// approximateTime returns the instant within the civil date of date, in
+// the time zone of date, when local mean time at longitude is 6h for a
+// rising or 18h for a setting event.
+func approximateTime(date time.Time, sunrise bool, longitude float64) time.Time {
+	hours := 6.0
+	if !sunrise {
+		hours = 18.0
+	}
+	return localMeanTime(date, longitude, hours)
+}
 
-	RA := degreeAtan(0.91764 * degreeTan(L))
-	RA = normalizeRange(RA, 360)
+// localMeanTime returns the instant within the civil date of date when
+// local mean time at longitude is hours. On days shortened by a time zone
+// change the instant may fall just outside the date.
+func localMeanTime(date time.Time, longitude, hours float64) time.Time {
+	midnight, next := Options{}.civilDay(date)
 
-	// 5b. right ascension value needs to be in the same quadrant as L
-	// Lquadrant  = (floor( L/90)) * 90
-	// RAquadrant = (floor(RA/90)) * 90
-	// RA = RA + (Lquadrant - RAquadrant)
+	u := midnight.UTC()
+	utMidnight := time.Date(u.Year(), u.Month(), u.Day(), 0, 0, 0, 0, time.UTC)
+	ut := time.Duration((hours - longitude/15.0) * float64(time.Hour))
 
-	Lquadrant := math.Floor(L/90.0) * 90.0
-	RAquadrant := math.Floor(RA/90.0) * 90.0
-	RA = RA + (Lquadrant - RAquadrant)
+	candidate := utMidnight.Add(ut)
+	for candidate.Before(midnight) {
+		candidate = candidate.Add(24 * time.Hour)
+	}
+	for !candidate.Before(next) {
+		candidate = candidate.Add(-24 * time.Hour)
+	}
+	return candidate
+}
 
-	// 5c. right ascension value needs to be converted into hours
-	// RA = RA / 15
+// nearestUT returns the instant whose UT time of day is UT hours, within
+// 12 hours of near.
+func nearestUT(near time.Time, UT float64) time.Time {
+	u := near.UTC()
+	t := time.Date(u.Year(), u.Month(), u.Day(), 0, 0, 0, 0, time.UTC).Add(time.Duration(UT * float64(time.Hour)))
+	if d := t.Sub(near); d > 12*time.Hour {
+		t = t.Add(-24 * time.Hour)
+	} else if d < -12*time.Hour {
+		t = t.Add(24 * time.Hour)
+	}
+	return t
+}
 
-	RA = RA / 15.0
+// horizonCosH runs steps 3 to 7a of sunRiseSet: it returns the time t in
+// Julian centuries of the approximate instant guess, the Sun's right ascension at t
+// and the cosine of the Sun's hour angle when it crosses zenith. cosH
+// outside [-1, 1] means the crossing does not happen.
+func horizonCosH(guess time.Time, latitude, zenith float64) (t, RA, cosH float64) {
+	t = julianCentury(guess)
 
-	// 6. calculate the Sun's declination
-	// sinDec = 0.39782 * sin(L)
-	// cosDec = cos(asin(sinDec))
+	// 3. - 6. calculate the Sun's coordinates at the approximate time
 
-	sinDec := 0.39782 * degreeSin(L)
-	cosDec := degreeCos(degreeAsin(sinDec))
+	_, RA, sinDec, cosDec := sunCoordinates(t)
 
 	// 7a. calculate the Sun's local hour angle
 	// cosH = (cos(zenith) - (sinDec * sin(latitude))) / (cosDec * cos(latitude))
@@ -112,44 +323,76 @@ func sunRiseSet(sunrise bool, latitude, longitude, zenith float64) time.Time {
 	// if (cosH < -1)
 	// the sun never sets on this location (on the specified date)
 
-	cosH := (degreeCos(zenith) - (sinDec * degreeSin(latitude))) / (cosDec * degreeCos(latitude))
-	if cosH > 1.0 || cosH < -1.0 {
-		panic("no answer")
-	}
+	cosH = (degreeCos(zenith) - (sinDec * degreeSin(latitude))) / (cosDec * degreeCos(latitude))
 
-	// 7b. finish calculating H and convert into hours
-	// if if rising time is desired:
-	// H = 360 - acos(cosH)
-	// if setting time is desired:
-	// H = acos(cosH)
-	// H = H / 15
+	return t, RA, cosH
+}
 
-	H := 360 - degreeAcos(cosH)
-	if sunset {
-		H = degreeAcos(cosH)
-	}
-	H = H / 15.0
+// utHours returns the time of day of date in UT, in hours.
+func utHours(date time.Time) float64 {
+	utc := date.UTC()
+	return float64(utc.Hour()) + float64(utc.Minute())/60.0 + (float64(utc.Second())+float64(utc.Nanosecond())/1e9)/3600.0
+}
 
-	// 8. calculate local mean time of rising/setting
-	// T = H + RA - (0.06571 * t) - 6.622
+// The reference computes the Sun's coordinates from the day of the year,
+// with constants fitted to the years around 1990, so the results drift
+// away from that epoch and jump at each new year. The functions below
+// take the time t in Julian centuries since J2000.0, see julianCentury,
+// and follow the low-accuracy solar theory of Meeus, Astronomical
+// Algorithms, chapter 25, which stays within 0.01° for centuries.
+
+// meanAnomaly returns the Sun's mean anomaly in degrees.
+// M = 357.52911 + 35999.05029 * t - 0.0001537 * t^2
+func meanAnomaly(t float64) float64 {
+	return 357.52911 + t*(35999.05029-0.0001537*t)
+}
 
-	T := H + RA - (0.06571 * t) - 6.622
+// meanLongitude returns the Sun's geometric mean longitude in degrees,
+// referred to the mean equinox of the date.
+// L0 = 280.46646 + 36000.76983 * t + 0.0003032 * t^2
+func meanLongitude(t float64) float64 {
+	return normalizeRange(280.46646+t*(36000.76983+0.0003032*t), 360)
+}
 
-	// 9. adjust back to UTC
-	// UT = T - lngHour
-	// NOTE: UT potentially needs to be adjusted into the range [0,24) by adding/subtracting 24
+// sunCoordinates returns the Sun's apparent longitude (degrees), right
+// ascension (hours), and the sine and cosine of its declination at t.
+func sunCoordinates(t float64) (L, RA, sinDec, cosDec float64) {
+	M := meanAnomaly(t)
 
-	UT := normalizeRange(T-lngHour, 24.0)
+	// 4. calculate the Sun's true longitude from the equation of the
+	// centre, then correct it for nutation and aberration
+	// C = (1.914602 - 0.004817 * t - 0.000014 * t^2) * sin(M)
+	//   + (0.019993 - 0.000101 * t) * sin(2 * M) + 0.000289 * sin(3 * M)
+	// L = L0 + C - 0.00569 - 0.00478 * sin(omega)
 
-	// 10. convert UT value to local time zone of latitude/longitude
-	// localT = UT + localOffset
+	C := (1.914602-t*(0.004817+0.000014*t))*degreeSin(M) +
+		(0.019993-0.000101*t)*degreeSin(2*M) +
+		0.000289*degreeSin(3*M)
+	omega := 125.04 - 1934.136*t
+	L = normalizeRange(meanLongitude(t)+C-0.00569-0.00478*degreeSin(omega), 360)
+
+	// 5. calculate the Sun's right ascension in hours, in the quadrant of L
+	// epsilon = 23.439291 - 0.0130042 * t + 0.00256 * cos(omega)
+	// RA = atan2(cos(epsilon) * sin(L), cos(L))
 
-	localT := normalizeRange(UT+localOffset, 24.0)
-	hour := math.Floor(localT)
-	minute := math.Floor((localT - hour) * 60.0)
-	second := math.Floor(((localT-hour)*60.0 - minute) * 60.0)
+	epsilon := 23.439291 - 0.0130042*t + 0.00256*degreeCos(omega)
+	RA = normalizeRange(radianToDegree(math.Atan2(degreeCos(epsilon)*degreeSin(L), degreeCos(L))), 360) / 15.0
+
+	// 6. calculate the Sun's declination
+	// sinDec = sin(epsilon) * sin(L)
+
+	sinDec = degreeSin(epsilon) * degreeSin(L)
+	cosDec = math.Sqrt(1 - sinDec*sinDec)
+
+	return L, RA, sinDec, cosDec
+}
 
-	return time.Date(today.Year(), today.Month(), today.Day(), int(hour), int(minute), int(second), 0, loc)
+// siderealOffset returns Greenwich mean sidereal time less UT in hours at
+// t, which links local mean time and the hour angle in steps 8 of
+// sunRiseSet and localHourAngle.
+// GMST - UT = 6.697374558 + 2400.051336 * t + 0.000025862 * t^2
+func siderealOffset(t float64) float64 {
+	return 6.697374558 + t*(2400.051336+0.000025862*t)
 }
 
 func degreeToRadian(x float64) float64 {
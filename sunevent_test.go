@@ -0,0 +1,238 @@
+package sunevent
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestEquationOfTime(t *testing.T) {
+	// extremes of the equation of time, from the Astronomical Almanac
+	tests := []struct {
+		date time.Time
+		want time.Duration
+	}{
+		{time.Date(2026, time.February, 11, 12, 0, 0, 0, time.UTC), -(14*time.Minute + 13*time.Second)},
+		{time.Date(2026, time.May, 14, 12, 0, 0, 0, time.UTC), 3*time.Minute + 39*time.Second},
+		{time.Date(2026, time.July, 26, 12, 0, 0, 0, time.UTC), -(6*time.Minute + 32*time.Second)},
+		{time.Date(2026, time.November, 3, 12, 0, 0, 0, time.UTC), 16*time.Minute + 25*time.Second},
+	}
+	for _, tt := range tests {
+		got := EquationOfTime(tt.date)
+		if d := got - tt.want; d > 15*time.Second || d < -15*time.Second {
+			t.Errorf("EquationOfTime(%s) = %v, want %v", tt.date.Format("2006-01-02"), got, tt.want)
+		}
+	}
+}
+
+func TestEquationOfTimeCrossesZero(t *testing.T) {
+	// the equation of time vanishes about 15 April, 13 June, 1 September
+	// and 25 December
+	for _, date := range []time.Time{
+		time.Date(2026, time.April, 15, 12, 0, 0, 0, time.UTC),
+		time.Date(2026, time.June, 13, 12, 0, 0, 0, time.UTC),
+		time.Date(2026, time.September, 1, 12, 0, 0, 0, time.UTC),
+		time.Date(2026, time.December, 25, 12, 0, 0, 0, time.UTC),
+	} {
+		if got := EquationOfTime(date); got > 40*time.Second || got < -40*time.Second {
+			t.Errorf("EquationOfTime(%s) = %v, want about 0", date.Format("2006-01-02"), got)
+		}
+	}
+}
+
+func TestSolarDeclination(t *testing.T) {
+	march, september := Equinoxes(2026)
+	june, december := Solstices(2026)
+	tests := []struct {
+		t    time.Time
+		want float64
+	}{
+		{march, 0},
+		{june, 23.436},
+		{september, 0},
+		{december, -23.436},
+	}
+	for _, tt := range tests {
+		if got := SolarDeclination(tt.t); got < tt.want-0.01 || got > tt.want+0.01 {
+			t.Errorf("SolarDeclination(%s) = %.4f, want %.3f", tt.t, got, tt.want)
+		}
+	}
+}
+
+func TestTimeAtAltitude(t *testing.T) {
+	taipei := time.FixedZone("CST", 8*3600)
+	date := time.Date(2026, time.April, 10, 12, 0, 0, 0, taipei)
+	const latitude, longitude = 22.63, 120.30
+	noon, err := SolarNoonOn(date, latitude, longitude)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, altitude := range []float64{-18, -6, 0, 10, 45} {
+		for _, rising := range []bool{true, false} {
+			got, err := TimeAtAltitude(date, latitude, longitude, altitude, rising)
+			if err != nil {
+				t.Errorf("TimeAtAltitude(%v°, rising %v): %v", altitude, rising, err)
+				continue
+			}
+			if rising != got.Before(noon) {
+				t.Errorf("TimeAtAltitude(%v°, rising %v) = %s, on the wrong side of noon %s", altitude, rising, got, noon)
+			}
+			if e := SunPosition(got, latitude, longitude).Altitude; !near(e, altitude, 0.15) {
+				t.Errorf("TimeAtAltitude(%v°, rising %v) = %s, where the Sun is at %.3f°", altitude, rising, got, e)
+			}
+		}
+	}
+
+	// the Sun culminates near 75° that day
+	if _, err := TimeAtAltitude(date, latitude, longitude, 80, true); err != ErrSunNeverRises {
+		t.Errorf("TimeAtAltitude(80°) error = %v, want ErrSunNeverRises", err)
+	}
+}
+
+func TestTimeAtAltitudePolar(t *testing.T) {
+	tests := []struct {
+		date time.Time
+		want error
+	}{
+		{time.Date(2026, time.June, 21, 12, 0, 0, 0, time.UTC), ErrSunNeverSets},
+		{time.Date(2026, time.December, 21, 12, 0, 0, 0, time.UTC), ErrSunNeverRises},
+	}
+	for _, tt := range tests {
+		for _, rising := range []bool{true, false} {
+			if _, err := TimeAtAltitude(tt.date, 78.22, 15.65, 0, rising); err != tt.want {
+				t.Errorf("TimeAtAltitude on %s at 78°N = %v, want %v", tt.date.Format("2006-01-02"), err, tt.want)
+			}
+		}
+	}
+}
+
+func TestSunEquatorial(t *testing.T) {
+	tests := []struct {
+		at      time.Time
+		ra, dec float64
+	}{
+		// the equinoxes and solstices of 2026
+		{time.Date(2026, time.March, 20, 14, 46, 0, 0, time.UTC), 0, 0},
+		{time.Date(2026, time.June, 21, 8, 24, 0, 0, time.UTC), 6, 23.44},
+		{time.Date(2026, time.September, 23, 0, 5, 0, 0, time.UTC), 12, 0},
+		{time.Date(2026, time.December, 21, 20, 50, 0, 0, time.UTC), 18, -23.44},
+	}
+	for _, tt := range tests {
+		ra, dec := SunEquatorial(tt.at)
+		if ra > 23.9 {
+			ra -= 24
+		}
+		if !near(ra, tt.ra, 0.01) || !near(dec, tt.dec, 0.01) {
+			t.Errorf("%s: RA %vh, dec %v°, want %vh, %v°", tt.at, ra, dec, tt.ra, tt.dec)
+		}
+	}
+}
+
+func TestSunCoordinatesJulianCentury(t *testing.T) {
+	// Meeus, Astronomical Algorithms, examples 25.a and 28.a: 1992 October 13
+	at := time.Date(1992, time.October, 13, 0, 0, 0, 0, time.UTC)
+	if ra, dec := SunEquatorial(at); !near(ra, 13.225389, 0.0003) || !near(dec, -7.78507, 0.001) {
+		t.Errorf("SunEquatorial(%s) = %vh, %v°, want 13.225389h, -7.78507°", at, ra, dec)
+	}
+	if eot := EquationOfTime(at); absDuration(eot-(13*time.Minute+42600*time.Millisecond)) > 2*time.Second {
+		t.Errorf("EquationOfTime(%s) = %s, want 13m42.6s", at, eot)
+	}
+
+	// the declination at the June solstice follows the obliquity of the
+	// ecliptic, which shrinks by about 0.013° a century
+	for _, tt := range []struct {
+		year int
+		want float64
+	}{{1800, 23.4676}, {2000, 23.4381}, {2200, 23.4110}} {
+		highest := -90.0
+		for at := time.Date(tt.year, time.June, 15, 0, 0, 0, 0, time.UTC); at.Month() == time.June; at = at.Add(time.Hour) {
+			if dec := SolarDeclination(at); dec > highest {
+				highest = dec
+			}
+		}
+		if !near(highest, tt.want, 0.001) {
+			t.Errorf("%d: highest declination %v°, want %v°", tt.year, highest, tt.want)
+		}
+	}
+
+	// no jump at the turn of the year
+	before := SolarDeclination(time.Date(2025, time.December, 31, 23, 59, 59, 0, time.UTC))
+	after := SolarDeclination(time.Date(2026, time.January, 1, 0, 0, 1, 0, time.UTC))
+	if !near(after, before, 1e-5) {
+		t.Errorf("declination %v° before new year, %v° after", before, after)
+	}
+}
+
+func TestEventsOnCivilDateAcrossDateLine(t *testing.T) {
+	tests := []struct {
+		name                string
+		latitude, longitude float64
+		zone                string
+	}{
+		{"Fiji", -18.14, 178.44, "Pacific/Fiji"},
+		{"Samoa", -13.83, -171.76, "Pacific/Apia"},
+	}
+	for _, tt := range tests {
+		loc, err := time.LoadLocation(tt.zone)
+		if err != nil {
+			t.Skipf("time zone %s: %v", tt.zone, err)
+		}
+		for _, date := range []time.Time{
+			time.Date(2026, time.January, 15, 0, 0, 0, 0, loc),
+			time.Date(2026, time.April, 15, 12, 0, 0, 0, loc),
+			time.Date(2026, time.July, 15, 23, 59, 0, 0, loc),
+			time.Date(2026, time.October, 15, 6, 0, 0, 0, loc),
+		} {
+			events := []struct {
+				name string
+				f    func(time.Time, float64, float64, ...Option) (time.Time, error)
+			}{
+				{"sunrise", SunRiseOn},
+				{"sunset", SunSetOn},
+				{"solar noon", SolarNoonOn},
+			}
+			for _, e := range events {
+				got, err := e.f(date, tt.latitude, tt.longitude)
+				if err != nil {
+					t.Errorf("%s %s on %s: %v", tt.name, e.name, date.Format("2006-01-02"), err)
+					continue
+				}
+				got = got.In(loc)
+				if y, m, d := got.Date(); y != date.Year() || m != date.Month() || d != date.Day() {
+					t.Errorf("%s %s on %s = %s, on another date", tt.name, e.name, date.Format("2006-01-02"), got)
+				}
+			}
+		}
+	}
+}
+
+func TestSunRiseToday(t *testing.T) {
+	local := time.Local
+	defer func() { time.Local = local }()
+
+	// the same instants whatever the host time zone
+	time.Local = time.UTC
+	rise, set := SunRise(22.63, 120.30), SunSet(22.63, 120.30)
+	dawn, dusk := Dawn(22.63, 120.30), Dusk(22.63, 120.30)
+	time.Local = time.FixedZone("EST", -5*3600)
+	if got := SunRise(22.63, 120.30); !got.Equal(rise) || got.Location() != time.Local {
+		t.Errorf("SunRise in another host zone = %s, want %s", got, rise)
+	}
+	if got := Dusk(22.63, 120.30); !got.Equal(dusk) {
+		t.Errorf("Dusk in another host zone = %s, want %s", got, dusk)
+	}
+
+	// Dawn and Dusk are at a zenith of 83°, with the Sun 7° up
+	if rise.IsZero() || !rise.Before(dawn) || !dawn.Before(dusk) || !dusk.Before(set) {
+		t.Errorf("today: dawn %s, sunrise %s, sunset %s, dusk %s", dawn, rise, set, dusk)
+	}
+	if d := time.Since(rise); d < -24*time.Hour || d > 24*time.Hour {
+		t.Errorf("sunrise %s is not today", rise)
+	}
+
+	for _, c := range [][2]float64{{91, 0}, {math.NaN(), 0}, {0, math.Inf(1)}, {0, math.NaN()}} {
+		if got := SunRise(c[0], c[1]); !got.IsZero() {
+			t.Errorf("SunRise(%v, %v) = %s, want the zero time", c[0], c[1], got)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+// Package suneventtest provides a scripted sunevent.Sky for testing code
+// that reacts to the Sun, without real calculations or clock control.
+//
+// A script lists events and phase changes at exact times. Events change
+// the phase as they would in the sky, so a sunset at 18:00 followed by
+// polar night reads:
+//
+//	sky := suneventtest.NewSky(sunevent.PhaseDay)
+//	sky.Add(sunevent.Sunset, time.Date(2026, 11, 20, 18, 0, 0, 0, time.UTC))
+//	sky.SetPhase(time.Date(2026, 11, 20, 19, 0, 0, 0, time.UTC), sunevent.PhaseNight)
+//
+// After the last entry the phase stays as it is, and EventOn reports
+// sunevent.ErrSunNeverRises or ErrSunNeverSets for dates without the event.
+package suneventtest
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cfw011566/sunevent"
+)
+
+var _ sunevent.Sky = (*Sky)(nil)
+
+// Sky is a scripted sunevent.Sky. It is safe for concurrent use, so the
+// script may be extended while the code under test runs.
+type Sky struct {
+	mu      sync.Mutex
+	initial sunevent.PhaseKind
+	entries []entry
+}
+
+// entry is an event or a phase change of the script.
+type entry struct {
+	time  time.Time
+	event *sunevent.EventType
+	phase sunevent.PhaseKind
+	sets  bool // the entry sets the phase
+}
+
+// NewSky returns an empty script with the given phase before its first
+// entry.
+func NewSky(initial sunevent.PhaseKind) *Sky {
+	return &Sky{initial: initial}
+}
+
+// Add adds the event e at t and returns s. Dawns, dusks, sunrise and
+// sunset also set the phase they start; solar noon leaves it unchanged.
+func (s *Sky) Add(e sunevent.EventType, t time.Time) *Sky {
+	phase, sets := phaseAfter(e)
+	s.insert(entry{time: t, event: &e, phase: phase, sets: sets})
+	return s
+}
+
+// SetPhase sets the phase from t on, until the next entry that sets it,
+// and returns s.
+func (s *Sky) SetPhase(t time.Time, p sunevent.PhaseKind) *Sky {
+	s.insert(entry{time: t, phase: p, sets: true})
+	return s
+}
+
+func (s *Sky) insert(e entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// after any entry at the same time, so later calls take precedence
+	i := sort.Search(len(s.entries), func(i int) bool {
+		return s.entries[i].time.After(e.time)
+	})
+	s.entries = append(s.entries, entry{})
+	copy(s.entries[i+1:], s.entries[i:])
+	s.entries[i] = e
+}
+
+// EventOn implements sunevent.Sky. It returns the first scripted event of
+// type e on the civil date of date, in the time zone of date.
+func (s *Sky) EventOn(e sunevent.EventType, date time.Time) (sunevent.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	loc := date.Location()
+	y, m, d := date.Date()
+	for _, en := range s.entries {
+		if en.event == nil || *en.event != e {
+			continue
+		}
+		t := en.time.In(loc)
+		if ty, tm, td := t.Date(); ty == y && tm == m && td == d {
+			return sunevent.Event{Type: e, Time: t}, nil
+		}
+	}
+
+	noon := time.Date(y, m, d, 12, 0, 0, 0, loc)
+	if s.phaseAt(noon) == sunevent.PhaseDay {
+		return sunevent.Event{}, sunevent.ErrSunNeverSets
+	}
+	return sunevent.Event{}, sunevent.ErrSunNeverRises
+}
+
+// EventsBetween implements sunevent.Sky.
+func (s *Sky) EventsBetween(from, to time.Time, types ...sunevent.EventType) []sunevent.Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var events []sunevent.Event
+	for _, en := range s.entries {
+		if en.event == nil || en.time.Before(from) || !en.time.Before(to) {
+			continue
+		}
+		if len(types) > 0 && !contains(types, *en.event) {
+			continue
+		}
+		events = append(events, sunevent.Event{Type: *en.event, Time: en.time.In(from.Location())})
+	}
+	return events
+}
+
+// PhaseAt implements sunevent.Sky. The phase set by an entry applies from
+// its time on.
+func (s *Sky) PhaseAt(t time.Time) sunevent.PhaseKind {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.phaseAt(t)
+}
+
+func (s *Sky) phaseAt(t time.Time) sunevent.PhaseKind {
+	phase := s.initial
+	for _, en := range s.entries {
+		if en.time.After(t) {
+			break
+		}
+		if en.sets {
+			phase = en.phase
+		}
+	}
+	return phase
+}
+
+// phaseAfter returns the phase that starts with the event e. It returns
+// false for SolarNoon, which does not change the phase.
+func phaseAfter(e sunevent.EventType) (sunevent.PhaseKind, bool) {
+	switch e {
+	case sunevent.Sunrise:
+		return sunevent.PhaseDay, true
+	case sunevent.Sunset, sunevent.CivilDawn:
+		return sunevent.PhaseCivilTwilight, true
+	case sunevent.CivilDusk, sunevent.NauticalDawn:
+		return sunevent.PhaseNauticalTwilight, true
+	case sunevent.NauticalDusk, sunevent.AstronomicalDawn:
+		return sunevent.PhaseAstronomicalTwilight, true
+	case sunevent.AstronomicalDusk:
+		return sunevent.PhaseNight, true
+	}
+	return 0, false
+}
+
+func contains(types []sunevent.EventType, e sunevent.EventType) bool {
+	for _, t := range types {
+		if t == e {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package suneventtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cfw011566/sunevent"
+)
+
+func TestSky(t *testing.T) {
+	at := func(day, hour int) time.Time {
+		return time.Date(2026, time.November, day, hour, 0, 0, 0, time.UTC)
+	}
+	sky := NewSky(sunevent.PhaseDay).
+		Add(sunevent.SolarNoon, at(20, 12)).
+		Add(sunevent.Sunset, at(20, 18)).
+		Add(sunevent.CivilDusk, at(20, 19))
+	sky.SetPhase(at(20, 21), sunevent.PhaseNight)
+
+	phases := []struct {
+		t    time.Time
+		want sunevent.PhaseKind
+	}{
+		{at(20, 10), sunevent.PhaseDay},
+		{at(20, 12), sunevent.PhaseDay},
+		{at(20, 18), sunevent.PhaseCivilTwilight},
+		{at(20, 20), sunevent.PhaseNauticalTwilight},
+		{at(21, 12), sunevent.PhaseNight},
+	}
+	for _, p := range phases {
+		if got := sky.PhaseAt(p.t); got != p.want {
+			t.Errorf("PhaseAt(%s) = %v, want %v", p.t, got, p.want)
+		}
+	}
+
+	taipei := time.FixedZone("CST", 8*3600)
+	ev, err := sky.EventOn(sunevent.Sunset, at(20, 0))
+	if err != nil || !ev.Time.Equal(at(20, 18)) {
+		t.Errorf("EventOn(Sunset) = %+v, %v", ev, err)
+	}
+	// 18:00 UTC is the next date in Taipei
+	if _, err := sky.EventOn(sunevent.Sunset, time.Date(2026, time.November, 20, 12, 0, 0, 0, taipei)); err != sunevent.ErrSunNeverSets {
+		t.Errorf("EventOn(Sunset) on 20 November in Taipei: %v, want ErrSunNeverSets", err)
+	}
+	if _, err := sky.EventOn(sunevent.Sunrise, at(22, 0)); err != sunevent.ErrSunNeverRises {
+		t.Errorf("EventOn(Sunrise) in the night: %v, want ErrSunNeverRises", err)
+	}
+
+	events := sky.EventsBetween(at(20, 12), at(20, 19), sunevent.Sunset, sunevent.CivilDusk, sunevent.SolarNoon)
+	if len(events) != 2 || events[0].Type != sunevent.SolarNoon || events[1].Type != sunevent.Sunset {
+		t.Errorf("EventsBetween = %+v", events)
+	}
+	if events := sky.EventsBetween(at(20, 0), at(21, 0)); len(events) != 3 {
+		t.Errorf("EventsBetween of every type = %+v", events)
+	}
+}
+
+func TestSkyOverride(t *testing.T) {
+	// a later entry at the same time takes precedence
+	at := time.Date(2026, time.November, 20, 18, 0, 0, 0, time.UTC)
+	sky := NewSky(sunevent.PhaseDay).Add(sunevent.Sunset, at).SetPhase(at, sunevent.PhaseNight)
+	if got := sky.PhaseAt(at); got != sunevent.PhaseNight {
+		t.Errorf("PhaseAt = %v, want night", got)
+	}
+}
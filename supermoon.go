@@ -0,0 +1,45 @@
+package sunevent
+
+import "time"
+
+// DefaultSupermoonDistance is the distance in km from the centre of the
+// Earth below which a full moon is a supermoon by default: within 90% of
+// the way from the mean apogee to the mean perigee, the definition
+// popularised by Richard Nolle and used by most calendars.
+const DefaultSupermoonDistance = 361524.0
+
+// WithSupermoonDistance sets the distance of the Moon in km below which
+// IsSupermoon counts a full moon, for publications that use a stricter or
+// looser definition.
+func WithSupermoonDistance(km float64) Option {
+	return func(o *Options) {
+		o.SupermoonDistance = km
+	}
+}
+
+// MoonDistance returns the distance between the centres of the Earth and
+// the Moon in km at the instant t, between about 356,400 km at perigee and
+// 406,700 km at apogee. MoonPosition gives the distance from an observer.
+func MoonDistance(t time.Time) float64 {
+	_, _, distance := moonEcliptic(Options{}.julianCentury(t))
+	return distance
+}
+
+// IsSupermoon reports whether t falls in the days of a full moon, as named
+// by MoonPhase, and the Moon at the instant of that full moon is closer
+// than the supermoon distance of opts.
+func IsSupermoon(t time.Time, opts ...Option) bool {
+	o := newOptions(opts)
+	threshold := o.SupermoonDistance
+	if threshold <= 0 {
+		threshold = DefaultSupermoonDistance
+	}
+
+	if _, name := MoonPhase(t); name != FullMoon {
+		return false
+	}
+	// the name FullMoon spans less than two days either side of the
+	// instant of full moon
+	full := nextMoonPhase(t.Add(-4*24*time.Hour), FullMoon)
+	return MoonDistance(full) < threshold
+}
@@ -0,0 +1,53 @@
+package sunevent
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMoonDistance(t *testing.T) {
+	// Meeus, Astronomical Algorithms, example 47.a: 1992 April 12, 0h TD
+	if d := MoonDistance(time.Date(1992, time.April, 12, 0, 0, 0, 0, time.UTC)); !near(d, 368409.7, 20) {
+		t.Errorf("MoonDistance = %v km, want 368409.7 km", d)
+	}
+
+	closest, farthest := 1e9, 0.0
+	for at := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC); at.Year() == 2026; at = at.Add(time.Hour) {
+		d := MoonDistance(at)
+		if d < closest {
+			closest = d
+		}
+		if d > farthest {
+			farthest = d
+		}
+	}
+	if closest < 356400 || closest > 357000 || farthest < 406000 || farthest > 406700 {
+		t.Errorf("the Moon between %v and %v km in 2026", closest, farthest)
+	}
+}
+
+func TestIsSupermoon(t *testing.T) {
+	// the full moons of November and December 2026 come within 361,524 km;
+	// that of January comes within 362,400 km
+	var supermoons []time.Month
+	for _, p := range MoonPhases(2026) {
+		if p.Phase == FullMoon && IsSupermoon(p.Time) {
+			supermoons = append(supermoons, p.Time.Month())
+		}
+	}
+	if len(supermoons) != 2 || supermoons[0] != time.November || supermoons[1] != time.December {
+		t.Errorf("supermoons in %v, want November and December", supermoons)
+	}
+
+	january := time.Date(2026, time.January, 3, 10, 3, 0, 0, time.UTC)
+	if !IsSupermoon(january, WithSupermoonDistance(362400)) {
+		t.Error("the full moon of January is not a supermoon within 362,400 km")
+	}
+	// the days around the full moon count, those of the quarters do not
+	if !IsSupermoon(time.Date(2026, time.December, 25, 12, 0, 0, 0, time.UTC)) {
+		t.Error("25 December is not in the days of the supermoon")
+	}
+	if IsSupermoon(time.Date(2026, time.December, 30, 12, 0, 0, 0, time.UTC)) {
+		t.Error("30 December is in the days of the supermoon")
+	}
+}
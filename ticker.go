@@ -0,0 +1,62 @@
+package sunevent
+
+import (
+	"context"
+	"time"
+)
+
+// TickPosition streams the position of the Sun every interval until ctx is
+// cancelled, when the channel is closed. Ticks are scheduled against the
+// start time rather than the previous tick, so they do not drift; if the
+// receiver falls behind, missed ticks are skipped and the channel holds
+// only the most recent position. The first position is sent immediately.
+// Like time.NewTicker it panics if interval is not positive.
+func TickPosition(ctx context.Context, latitude, longitude float64, interval time.Duration, opts ...Option) <-chan Position {
+	if interval <= 0 {
+		panic("sunevent: non-positive interval for TickPosition")
+	}
+	ch := make(chan Position, 1)
+
+	go func() {
+		defer close(ch)
+
+		start := time.Now()
+		timer := time.NewTimer(0)
+		defer timer.Stop()
+
+		for n := int64(1); ; n++ {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-timer.C:
+				sendLatest(ch, SunPosition(now, latitude, longitude, opts...))
+
+				// next tick on the start-aligned grid after now
+				next := start.Add(time.Duration(n) * interval)
+				if behind := time.Since(next); behind > 0 {
+					skip := int64(behind/interval) + 1
+					n += skip
+					next = next.Add(time.Duration(skip) * interval)
+				}
+				timer.Reset(time.Until(next))
+			}
+		}
+	}()
+
+	return ch
+}
+
+// sendLatest delivers p without blocking, replacing an unread older position.
+func sendLatest(ch chan Position, p Position) {
+	for {
+		select {
+		case ch <- p:
+			return
+		default:
+		}
+		select {
+		case <-ch:
+		default:
+		}
+	}
+}
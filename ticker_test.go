@@ -0,0 +1,60 @@
+package sunevent
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTickPosition(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	start := time.Now()
+	ch := TickPosition(ctx, 22.63, 120.30, 20*time.Millisecond)
+	for i := 0; i < 3; i++ {
+		select {
+		case p := <-ch:
+			if want := SunPosition(time.Now(), 22.63, 120.30); !near(p.Azimuth, want.Azimuth, 0.01) || !near(p.Altitude, want.Altitude, 0.01) {
+				t.Errorf("tick %d = %+v, want %+v", i, p, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("no tick %d", i)
+		}
+	}
+	// the first tick is immediate and the next two follow on the grid
+	if d := time.Since(start); d < 40*time.Millisecond {
+		t.Errorf("three ticks in %v", d)
+	}
+
+	cancel()
+	deadline := time.After(time.Second)
+	for {
+		select {
+		case _, ok := <-ch:
+			if !ok {
+				return
+			}
+		case <-deadline:
+			t.Fatal("channel not closed after cancel")
+		}
+	}
+}
+
+func TestTickPositionPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("no panic for a zero interval")
+		}
+	}()
+	TickPosition(context.Background(), 0, 0, 0)
+}
+
+func TestSendLatest(t *testing.T) {
+	ch := make(chan Position, 1)
+	sendLatest(ch, Position{Azimuth: 1})
+	sendLatest(ch, Position{Azimuth: 2})
+	if p := <-ch; p.Azimuth != 2 {
+		t.Errorf("received %+v, want the latest position", p)
+	}
+}
@@ -0,0 +1,77 @@
+// Package tmplfuncs provides sun event functions for html/template and
+// text/template.
+//
+// The map returned by FuncMap can be passed directly to Template.Funcs or
+// merged into an existing FuncMap:
+//
+//	t := template.New("page").Funcs(tmplfuncs.FuncMap())
+//
+//	Sunrise today: {{ (sunrise 22.63 120.30).Format "15:04" }}
+//	It is {{ phase 22.63 120.30 }}, sunset in {{ sunset 22.63 120.30 | countdown }}
+package tmplfuncs
+
+import (
+	"time"
+
+	"github.com/cfw011566/sunevent"
+)
+
+// moonPhaseNames are the display names of the phases of the Moon.
+var moonPhaseNames = [...]string{
+	sunevent.NewMoon:        "New Moon",
+	sunevent.WaxingCrescent: "Waxing Crescent",
+	sunevent.FirstQuarter:   "First Quarter",
+	sunevent.WaxingGibbous:  "Waxing Gibbous",
+	sunevent.FullMoon:       "Full Moon",
+	sunevent.WaningGibbous:  "Waning Gibbous",
+	sunevent.LastQuarter:    "Last Quarter",
+	sunevent.WaningCrescent: "Waning Crescent",
+}
+
+// FuncMap returns a new map holding the template functions:
+//
+//	sunrise LAT LON    today's sunrise as a time.Time
+//	sunset LAT LON     today's sunset as a time.Time
+//	phase LAT LON      "day" or "night" at the current time
+//	countdown TIME     time left until TIME, rounded to the second
+//	moonphase TIME     name of the moon phase at TIME
+//
+// sunrise and sunset return the zero time on latitudes where the sun does
+// not rise or set today.
+func FuncMap() map[string]interface{} {
+	return map[string]interface{}{
+		"sunrise":   sunevent.SunRise,
+		"sunset":    sunevent.SunSet,
+		"phase":     phase,
+		"countdown": countdown,
+		"moonphase": moonPhase,
+	}
+}
+
+func phase(latitude, longitude float64) string {
+	now := time.Now()
+	day := sunevent.SunDayOn(now, latitude, longitude)
+	switch day.Type {
+	case sunevent.PolarDay:
+		return "day"
+	case sunevent.PolarNight:
+		return "night"
+	}
+	if now.Before(day.Sunrise) || now.After(day.Sunset) {
+		return "night"
+	}
+	return "day"
+}
+
+func countdown(t time.Time) string {
+	d := time.Until(t)
+	if d < 0 {
+		d = 0
+	}
+	return d.Round(time.Second).String()
+}
+
+func moonPhase(t time.Time) string {
+	_, name := sunevent.MoonPhase(t)
+	return moonPhaseNames[name]
+}
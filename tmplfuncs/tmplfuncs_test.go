@@ -0,0 +1,79 @@
+package tmplfuncs
+
+import (
+	"bytes"
+	htmltemplate "html/template"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+)
+
+func execute(t *testing.T, text string, data interface{}) string {
+	t.Helper()
+	tmpl, err := template.New("test").Funcs(FuncMap()).Parse(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, data); err != nil {
+		t.Fatal(err)
+	}
+	return b.String()
+}
+
+func TestMoonPhase(t *testing.T) {
+	tests := []struct {
+		t    time.Time
+		want string
+	}{
+		// full moon of 3 March 2026 at 11:38 UTC
+		{time.Date(2026, time.March, 3, 11, 38, 0, 0, time.UTC), "Full Moon"},
+		// new moon of 17 February 2026 at 12:01 UTC
+		{time.Date(2026, time.February, 17, 12, 1, 0, 0, time.UTC), "New Moon"},
+		{time.Date(2026, time.February, 21, 0, 0, 0, 0, time.UTC), "Waxing Crescent"},
+		{time.Date(2026, time.March, 7, 0, 0, 0, 0, time.UTC), "Waning Gibbous"},
+	}
+	for _, tt := range tests {
+		if got := execute(t, `{{moonphase .}}`, tt.t); got != tt.want {
+			t.Errorf("moonphase %s = %q, want %q", tt.t, got, tt.want)
+		}
+	}
+}
+
+func TestCountdown(t *testing.T) {
+	if got := execute(t, `{{countdown .}}`, time.Now().Add(-time.Hour)); got != "0s" {
+		t.Errorf("countdown of a past time = %q, want 0s", got)
+	}
+	got := execute(t, `{{countdown .}}`, time.Now().Add(90*time.Minute))
+	if d, err := time.ParseDuration(got); err != nil || d < 89*time.Minute || d > 90*time.Minute {
+		t.Errorf("countdown of 90 minutes ahead = %q", got)
+	}
+}
+
+func TestSunFunctions(t *testing.T) {
+	got := execute(t, `{{phase 22.63 120.30}}`, nil)
+	if got != "day" && got != "night" {
+		t.Errorf("phase = %q, want day or night", got)
+	}
+
+	// the Sun rises and sets every day at the equator
+	got = execute(t, `{{(sunrise 0.0 0.0).IsZero}} {{(sunset 0.0 0.0).IsZero}}`, nil)
+	if got != "false false" {
+		t.Errorf("sunrise and sunset at the equator zero = %q, want false false", got)
+	}
+}
+
+func TestFuncMapHTML(t *testing.T) {
+	tmpl, err := htmltemplate.New("test").Funcs(FuncMap()).Parse(`<p>{{moonphase .}}</p>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, time.Date(2026, time.March, 3, 11, 38, 0, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+	if got := b.String(); got != "<p>Full Moon</p>" {
+		t.Errorf("html template = %q", got)
+	}
+}
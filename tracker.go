@@ -0,0 +1,99 @@
+package sunevent
+
+import (
+	"time"
+)
+
+// trackerHorizon bounds how far Tracker.Next looks for an event, so that
+// it returns during a polar day or night that outlasts any event.
+const trackerHorizon = 366 * 24 * time.Hour
+
+// Tracker follows the phase of daylight and the upcoming events at one
+// location as time advances, for programs that poll, such as game loops
+// and simulators, rather than wait on timers. Events are computed a day at
+// a time and cached, so between crossings Advance costs little more than
+// the phase, which is evaluated afresh from the Sun's elevation.
+//
+// A Tracker is not safe for concurrent use.
+type Tracker struct {
+	latitude  float64
+	longitude float64
+	opts      []Option
+
+	now     time.Time
+	phase   PhaseKind
+	filled  time.Time // events before filled are in pending
+	pending []Event
+}
+
+// NewTracker returns a tracker for the location, started at start. Event
+// times are in the time zone of start. The options apply to the phase and
+// to the events.
+func NewTracker(latitude, longitude float64, start time.Time, opts ...Option) *Tracker {
+	return &Tracker{
+		latitude:  latitude,
+		longitude: longitude,
+		opts:      opts,
+		now:       start,
+		phase:     PhaseAt(start, latitude, longitude, opts...),
+		filled:    start,
+	}
+}
+
+// Now returns the time of the last call to Advance, or the start time.
+func (tr *Tracker) Now() time.Time {
+	return tr.now
+}
+
+// Phase returns the phase of daylight at Now.
+func (tr *Tracker) Phase() PhaseKind {
+	return tr.phase
+}
+
+// Next returns the first event after Now. It returns false if no event
+// happens within a year, as at the poles.
+func (tr *Tracker) Next() (Event, bool) {
+	for len(tr.pending) == 0 && tr.filled.Sub(tr.now) < trackerHorizon {
+		tr.fill()
+	}
+	if len(tr.pending) == 0 {
+		return Event{}, false
+	}
+	return tr.pending[0], true
+}
+
+// Advance moves the tracker to now and returns the events in (Now, now] in
+// chronological order, updating the phase. It returns nil if now is not
+// after Now; to go back in time start a new tracker.
+func (tr *Tracker) Advance(now time.Time) []Event {
+	if !now.After(tr.now) {
+		return nil
+	}
+	for !tr.filled.After(now) {
+		tr.fill()
+	}
+
+	var crossed []Event
+	for len(tr.pending) > 0 && !tr.pending[0].Time.After(now) {
+		crossed = append(crossed, tr.pending[0])
+		tr.pending = tr.pending[1:]
+	}
+
+	// the phase comes from the elevation rather than the crossed events,
+	// which can miss a twilight that only just happens on the date
+	tr.now = now
+	tr.phase = PhaseAt(now, tr.latitude, tr.longitude, tr.opts...)
+	return crossed
+}
+
+// fill adds the events of the next day to pending.
+func (tr *Tracker) fill() {
+	loc := tr.now.Location()
+	to := tr.filled.Add(24 * time.Hour)
+	for _, e := range eventsBetween(tr.latitude, tr.longitude, tr.filled.In(loc), to, nil, tr.opts) {
+		if e.Time.After(tr.now) {
+			tr.pending = append(tr.pending, e)
+		}
+	}
+	tr.filled = to
+}
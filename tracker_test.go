@@ -0,0 +1,66 @@
+package sunevent
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTracker(t *testing.T) {
+	taipei := time.FixedZone("CST", 8*3600)
+	const latitude, longitude = 22.63, 120.30
+	start := time.Date(2026, time.March, 20, 0, 0, 0, 0, taipei)
+
+	tr := NewTracker(latitude, longitude, start)
+	if tr.Phase() != PhaseNight || !tr.Now().Equal(start) {
+		t.Errorf("start: %v at %s", tr.Phase(), tr.Now())
+	}
+
+	next, ok := tr.Next()
+	dawn, _ := AstronomicalDawn.On(start, latitude, longitude)
+	if !ok || next.Type != AstronomicalDawn || !next.Time.Equal(dawn) {
+		t.Errorf("Next = %+v, %v, want astronomical dawn at %s", next, ok, dawn)
+	}
+
+	// a day in uneven steps crosses every event once, in order
+	want := EventsBetween(latitude, longitude, start, start.AddDate(0, 0, 1))
+	var got []Event
+	for now := start; now.Before(start.AddDate(0, 0, 1)); {
+		now = now.Add(37 * time.Minute)
+		got = append(got, tr.Advance(now)...)
+		if p := PhaseAt(now, latitude, longitude); tr.Phase() != p {
+			t.Errorf("phase at %s = %v, want %v", now, tr.Phase(), p)
+		}
+	}
+	if len(got) != len(want) {
+		t.Fatalf("crossed %d events, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].Type != want[i].Type || !got[i].Time.Equal(want[i].Time) {
+			t.Errorf("event %d = %v at %s, want %v at %s", i, got[i].Type, got[i].Time, want[i].Type, want[i].Time)
+		}
+	}
+
+	if crossed := tr.Advance(start); crossed != nil {
+		t.Errorf("going back crossed %+v", crossed)
+	}
+}
+
+func TestTrackerOptions(t *testing.T) {
+	taipei := time.FixedZone("CST", 8*3600)
+	start := time.Date(2026, time.March, 20, 0, 0, 0, 0, taipei)
+
+	// the options reach the events as well as the phase
+	opts := []Option{WithLongitudeConvention(WestPositive), WithAlgorithm(NOAA)}
+	tr := NewTracker(22.63, -120.30, start, opts...)
+	next, ok := tr.Next()
+	dawn, _ := AstronomicalDawn.On(start, 22.63, 120.30, WithAlgorithm(NOAA))
+	if !ok || !next.Time.Equal(dawn) {
+		t.Errorf("Next = %+v, want the NOAA dawn at %s", next, dawn)
+	}
+
+	noon, _ := SolarNoonOn(start, 22.63, 120.30)
+	tr.Advance(noon)
+	if tr.Phase() != PhaseDay {
+		t.Errorf("phase at noon = %v", tr.Phase())
+	}
+}
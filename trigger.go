@@ -0,0 +1,133 @@
+package sunevent
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Trigger is a moment relative to a solar event, such as 30 minutes
+// before sunset: lights and irrigation rarely switch exactly at the event.
+type Trigger struct {
+	Event EventType
+
+	// Offset is added to the time of the event; it is negative for a
+	// moment before the event.
+	Offset time.Duration
+}
+
+// At returns the trigger offset from the event e, as in
+// At(Sunset, -30*time.Minute).
+func At(e EventType, offset time.Duration) Trigger {
+	return Trigger{Event: e, Offset: offset}
+}
+
+// String returns the trigger in the compact form accepted by
+// ParseTrigger, such as "sunset-30m0s".
+func (t Trigger) String() string {
+	switch {
+	case t.Offset > 0:
+		return t.Event.String() + "+" + t.Offset.String()
+	case t.Offset < 0:
+		return t.Event.String() + "-" + (-t.Offset).String()
+	}
+	return t.Event.String()
+}
+
+// ParseTrigger parses a trigger in the compact form "sunset-20m" or
+// "civil dawn+1h", with a duration as for time.ParseDuration, or in words
+// as in "30 minutes before sunset" or "1h after civil dawn". The event
+// names are those of ParseEventType.
+func ParseTrigger(s string) (Trigger, error) {
+	s = strings.TrimSpace(s)
+	for _, word := range []string{" before ", " after "} {
+		i := strings.LastIndex(s, word)
+		if i < 0 {
+			continue
+		}
+		offset, err := parseOffset(s[:i])
+		if err != nil {
+			return Trigger{}, fmt.Errorf("sunevent: trigger %q: %v", s, err)
+		}
+		e, err := ParseEventType(s[i+len(word):])
+		if err != nil {
+			return Trigger{}, err
+		}
+		if word == " before " {
+			offset = -offset
+		}
+		return At(e, offset), nil
+	}
+
+	// event names contain hyphens, so try every sign from the right
+	for i := len(s) - 1; i > 0; i-- {
+		if s[i] != '+' && s[i] != '-' {
+			continue
+		}
+		offset, err := time.ParseDuration(s[i:])
+		if err != nil {
+			continue
+		}
+		e, err := ParseEventType(s[:i])
+		if err != nil {
+			return Trigger{}, err
+		}
+		return At(e, offset), nil
+	}
+
+	e, err := ParseEventType(s)
+	if err != nil {
+		return Trigger{}, err
+	}
+	return At(e, 0), nil
+}
+
+// offsetUnits maps the unit words of ParseTrigger to durations.
+var offsetUnits = map[string]time.Duration{
+	"second":  time.Second,
+	"seconds": time.Second,
+	"sec":     time.Second,
+	"minute":  time.Minute,
+	"minutes": time.Minute,
+	"min":     time.Minute,
+	"mins":    time.Minute,
+	"hour":    time.Hour,
+	"hours":   time.Hour,
+}
+
+// parseOffset parses a non-negative duration written as for
+// time.ParseDuration or as a number and a unit word, "30 minutes".
+func parseOffset(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if d, err := time.ParseDuration(strings.Replace(s, " ", "", -1)); err == nil && d >= 0 {
+		return d, nil
+	}
+	fields := strings.Fields(strings.ToLower(s))
+	if len(fields) == 2 {
+		n, err := strconv.ParseFloat(fields[0], 64)
+		unit, ok := offsetUnits[fields[1]]
+		if err == nil && ok && n >= 0 {
+			return time.Duration(n * float64(unit)), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid offset %q", s)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (t Trigger) MarshalText() ([]byte, error) {
+	if _, err := t.Event.MarshalText(); err != nil {
+		return nil, err
+	}
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using ParseTrigger.
+func (t *Trigger) UnmarshalText(text []byte) error {
+	v, err := ParseTrigger(string(text))
+	if err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
@@ -0,0 +1,67 @@
+package sunevent
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTrigger(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Trigger
+	}{
+		{"sunset", At(Sunset, 0)},
+		{"sunset-20m", At(Sunset, -20*time.Minute)},
+		{"civil dawn+1h", At(CivilDawn, time.Hour)},
+		{"civil-dawn-1h30m", At(CivilDawn, -90*time.Minute)},
+		{"30 minutes before sunset", At(Sunset, -30*time.Minute)},
+		{"1h after civil dawn", At(CivilDawn, time.Hour)},
+		{"1.5 hours after sunrise", At(Sunrise, 90*time.Minute)},
+		{"10 sec before noon", At(SolarNoon, -10*time.Second)},
+	}
+	for _, tt := range tests {
+		got, err := ParseTrigger(tt.in)
+		if err != nil {
+			t.Errorf("ParseTrigger(%q): %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseTrigger(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseTriggerErrors(t *testing.T) {
+	for _, in := range []string{"", "teatime", "sunset+", "soon before sunset", "-5 minutes after sunrise"} {
+		if got, err := ParseTrigger(in); err == nil {
+			t.Errorf("ParseTrigger(%q) = %v, want an error", in, got)
+		}
+	}
+}
+
+func TestTriggerStringRoundTrip(t *testing.T) {
+	for _, tr := range []Trigger{At(Sunset, 0), At(Sunset, -30*time.Minute), At(NauticalDawn, 45*time.Second)} {
+		got, err := ParseTrigger(tr.String())
+		if err != nil || got != tr {
+			t.Errorf("ParseTrigger(%q) = %v, %v, want %v", tr.String(), got, err, tr)
+		}
+	}
+}
+
+func TestSchedulerDo(t *testing.T) {
+	// an hour before a sunset an hour and a moment away is a moment away
+	now := time.Now()
+	sky := scriptedSky{{Type: Sunset, Time: now.Add(time.Hour + 50*time.Millisecond)}}
+	s := NewScheduler(sky)
+	calls := make(chan EventType, 2)
+	s.Do(At(Sunset, -time.Hour), func() { calls <- Sunset })
+	s.Do(At(Sunset, -2*time.Hour), func() { t.Error("called for a trigger in the past") })
+	s.Start()
+	defer s.Stop()
+
+	start := time.Now()
+	receive(t, calls, 1)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("called after %s", elapsed)
+	}
+}
@@ -0,0 +1,96 @@
+package sunevent
+
+import (
+	"time"
+)
+
+// TwilightKind selects how far below the horizon twilight extends.
+type TwilightKind int
+
+const (
+	// CivilTwilight ends when the Sun is 6° below the horizon.
+	CivilTwilight TwilightKind = iota
+	// NauticalTwilight ends when the Sun is 12° below the horizon.
+	NauticalTwilight
+	// AstronomicalTwilight ends when the Sun is 18° below the horizon.
+	AstronomicalTwilight
+)
+
+func (k TwilightKind) dusk() EventType {
+	switch k {
+	case NauticalTwilight:
+		return NauticalDusk
+	case AstronomicalTwilight:
+		return AstronomicalDusk
+	}
+	return CivilDusk
+}
+
+func (k TwilightKind) String() string {
+	switch k {
+	case CivilTwilight:
+		return "civil"
+	case NauticalTwilight:
+		return "nautical"
+	case AstronomicalTwilight:
+		return "astronomical"
+	}
+	return "unknown"
+}
+
+// Twilight is the evening twilight following a sunset.
+type Twilight struct {
+	Kind  TwilightKind
+	Start time.Time
+	End   time.Time
+
+	// AllNight reports that the Sun does not sink far enough for the
+	// twilight to end, as in the white nights of summer at high latitudes.
+	// End is then the next sunrise.
+	AllNight bool
+}
+
+// EveningTwilight returns the twilight from sunset on the civil date of
+// date until the Sun reaches the depth of kind. When it never does, the
+// twilight lasts until the next sunrise and AllNight is set instead of an
+// error. It returns the error of SunSetOn when the Sun does not set.
+func EveningTwilight(date time.Time, latitude, longitude float64, kind TwilightKind, opts ...Option) (Twilight, error) {
+	set, err := SunSetOn(date, latitude, longitude, opts...)
+	if err != nil {
+		return Twilight{}, err
+	}
+	tw := Twilight{Kind: kind, Start: set}
+
+	altitude, _ := kind.dusk().Altitude()
+	end, err := TimeAtAltitude(date, latitude, longitude, altitude, false, opts...)
+	if err == nil && !end.After(set) {
+		// the crossing on date ends the twilight of the night before; the
+		// end of this one falls after midnight, on the next civil date
+		next := time.Date(date.Year(), date.Month(), date.Day()+1, 12, 0, 0, 0, date.Location())
+		end, err = TimeAtAltitude(next, latitude, longitude, altitude, false, opts...)
+	}
+	if err == nil {
+		tw.End = end
+		return tw, nil
+	}
+	if err != ErrSunNeverSets {
+		return Twilight{}, err
+	}
+
+	// the Sun stays above the twilight limit all night
+	next, err := NextSunriseDate(set, latitude, longitude, opts...)
+	if err != nil {
+		return Twilight{}, err
+	}
+	tw.End = next
+	tw.AllNight = true
+	return tw, nil
+}
+
+// IsWhiteNight reports whether the night following the civil date of date
+// is a continuous twilight of the given kind: the Sun sets, but never
+// sinks below the kind's depth.
+func IsWhiteNight(date time.Time, latitude, longitude float64, kind TwilightKind, opts ...Option) bool {
+	tw, err := EveningTwilight(date, latitude, longitude, kind, opts...)
+	return err == nil && tw.AllNight
+}
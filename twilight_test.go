@@ -0,0 +1,71 @@
+package sunevent
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEveningTwilight(t *testing.T) {
+	const latitude, longitude = 64.15, -21.94 // Reykjavik
+
+	// early in May civil twilight ends before midnight
+	date := time.Date(2026, time.May, 1, 12, 0, 0, 0, time.UTC)
+	tw, err := EveningTwilight(date, latitude, longitude, CivilTwilight)
+	if err != nil {
+		t.Fatal(err)
+	}
+	set, _ := SunSetOn(date, latitude, longitude)
+	dusk, _ := CivilDusk.On(date, latitude, longitude)
+	if tw.AllNight || !tw.Start.Equal(set) || !tw.End.Equal(dusk) {
+		t.Errorf("1 May: %+v, want %s–%s", tw, set, dusk)
+	}
+
+	// by the middle of the month it ends after midnight, on the next civil
+	// date, both with the almanac, which reports the crossing following
+	// the evening, and with an algorithm that keeps to the civil date
+	for _, day := range []int{14, 15, 16} {
+		date := time.Date(2026, time.May, day, 12, 0, 0, 0, time.UTC)
+		want, _ := TimeAtAltitude(date, latitude, longitude, -6, false)
+		for _, a := range []Algorithm{Almanac, civilDateAlgorithm{}} {
+			tw, err := EveningTwilight(date, latitude, longitude, CivilTwilight, WithAlgorithm(a))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tw.AllNight || !tw.End.Equal(want) || tw.End.Day() != day+1 {
+				t.Errorf("%d May with %T: %+v, want to end at %s", day, a, tw, want)
+			}
+		}
+		if e := Elevation(want, latitude, longitude); !near(e, -6, 0.05) {
+			t.Errorf("%d May: elevation %v at the end of civil twilight", day, e)
+		}
+	}
+
+	// at the end of May it lasts until sunrise
+	date = time.Date(2026, time.May, 25, 12, 0, 0, 0, time.UTC)
+	tw, err = EveningTwilight(date, latitude, longitude, CivilTwilight)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rise, _ := SunRiseOn(date.AddDate(0, 0, 1), latitude, longitude)
+	if !tw.AllNight || !tw.End.Equal(rise) || !IsWhiteNight(date, latitude, longitude, CivilTwilight) {
+		t.Errorf("25 May: %+v, want all night until %s", tw, rise)
+	}
+
+	// the Sun does not set at all in Longyearbyen in June
+	if _, err := EveningTwilight(time.Date(2026, time.June, 21, 12, 0, 0, 0, time.UTC), 78.22, 15.65, CivilTwilight); err != ErrSunNeverSets {
+		t.Errorf("twilight of the midnight Sun: %v, want ErrSunNeverSets", err)
+	}
+}
+
+// civilDateAlgorithm is the almanac restricted to crossings on the civil
+// date, as the Algorithm interface asks: a setting after midnight is
+// replaced by that of the evening before.
+type civilDateAlgorithm struct{}
+
+func (civilDateAlgorithm) SunTimes(date time.Time, latitude, longitude, zenith float64) (rise, set time.Time, err error) {
+	rise, set, err = Almanac.SunTimes(date, latitude, longitude, zenith)
+	if !set.IsZero() && set.Day() != date.Day() {
+		_, set, err = Almanac.SunTimes(date.AddDate(0, 0, -1), latitude, longitude, zenith)
+	}
+	return rise, set, err
+}
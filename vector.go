@@ -0,0 +1,39 @@
+package sunevent
+
+import (
+	"time"
+)
+
+// SunVector returns the unit vector pointing from the observer towards the
+// Sun at the given instant.
+//
+// In the default ENU frame x points east, y points north and z points up,
+// so z is negative while the Sun is below the horizon. With WithFrame(ECEF)
+// the same direction is expressed in Earth-Centered, Earth-Fixed axes,
+// which is convenient when several observers share one scene.
+func SunVector(t time.Time, latitude, longitude float64, opts ...Option) (x, y, z float64) {
+	o := newOptions(opts)
+	longitude = o.longitude(longitude)
+	azimuth, elevation := o.sunAt(t, latitude, longitude)
+
+	east := degreeCos(elevation) * degreeSin(azimuth)
+	north := degreeCos(elevation) * degreeCos(azimuth)
+	up := degreeSin(elevation)
+
+	if o.Frame == ECEF {
+		return enuToECEF(east, north, up, latitude, longitude)
+	}
+	return east, north, up
+}
+
+// enuToECEF rotates a direction from the local frame at latitude and
+// longitude into ECEF axes.
+func enuToECEF(east, north, up, latitude, longitude float64) (x, y, z float64) {
+	sinLat, cosLat := degreeSin(latitude), degreeCos(latitude)
+	sinLon, cosLon := degreeSin(longitude), degreeCos(longitude)
+
+	x = -sinLon*east - sinLat*cosLon*north + cosLat*cosLon*up
+	y = cosLon*east - sinLat*sinLon*north + cosLat*sinLon*up
+	z = cosLat*north + sinLat*up
+	return x, y, z
+}
@@ -0,0 +1,43 @@
+package sunevent
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSunVector(t *testing.T) {
+	at := time.Date(2026, time.June, 21, 3, 0, 0, 0, time.UTC)
+	const latitude, longitude = 22.63, 120.30
+
+	x, y, z := SunVector(at, latitude, longitude)
+	if l := math.Sqrt(x*x + y*y + z*z); !near(l, 1, 1e-12) {
+		t.Errorf("|SunVector| = %v, want 1", l)
+	}
+	p := SunPosition(at, latitude, longitude)
+	if !near(z, math.Sin(p.Altitude*math.Pi/180), 1e-9) {
+		t.Errorf("up = %v, want the sine of the elevation %v", z, p.Altitude)
+	}
+	if az := math.Mod(math.Atan2(x, y)*180/math.Pi+360, 360); !near(az, p.Azimuth, 1e-9) {
+		t.Errorf("azimuth of the vector = %v, want %v", az, p.Azimuth)
+	}
+}
+
+func TestSunVectorECEF(t *testing.T) {
+	at := time.Date(2026, time.June, 21, 3, 0, 0, 0, time.UTC)
+
+	// at latitude 0 and longitude 0 up is X, east is Y and north is Z
+	e, n, u := SunVector(at, 0, 0)
+	x, y, z := SunVector(at, 0, 0, WithFrame(ECEF))
+	if !near(x, u, 1e-12) || !near(y, e, 1e-12) || !near(z, n, 1e-12) {
+		t.Errorf("ECEF at 0, 0 = %v, %v, %v, want %v, %v, %v", x, y, z, u, e, n)
+	}
+
+	// the Sun is far enough for every observer to see it in the same
+	// direction
+	x1, y1, z1 := SunVector(at, 22.63, 120.30, WithFrame(ECEF))
+	x2, y2, z2 := SunVector(at, -33.87, 151.21, WithFrame(ECEF))
+	if !near(x1, x2, 1e-3) || !near(y1, y2, 1e-3) || !near(z1, z2, 1e-3) {
+		t.Errorf("ECEF directions differ: %v, %v, %v and %v, %v, %v", x1, y1, z1, x2, y2, z2)
+	}
+}
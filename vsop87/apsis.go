@@ -0,0 +1,86 @@
+package vsop87
+
+import (
+	"math"
+	"time"
+
+	"github.com/cfw011566/sunevent"
+)
+
+// Perihelion returns the instant in year, in UTC, when the Earth is
+// closest to the Sun, early in January: the minimum of the VSOP87
+// distance, which includes the pull of the Moon. That moves the
+// perihelion by up to a day and a half from that of the Earth–Moon
+// barycentre. The distance changes so slowly around the apsis that small
+// errors of the abridged series shift the result; for 2020 to 2026 it is
+// within 25 minutes of the published instants.
+func Perihelion(year int) time.Time {
+	return apsis(meanApsis(year, false), false)
+}
+
+// Aphelion returns the instant in year, in UTC, when the Earth is
+// farthest from the Sun, early in July. For 2020 to 2026 it is within 45
+// minutes of the published instants.
+func Aphelion(year int) time.Time {
+	return apsis(meanApsis(year, true), true)
+}
+
+// meanApsis returns the perihelion or aphelion of the Earth in year from
+// the mean orbit, with the periodic corrections of Meeus, Astronomical
+// Algorithms, chapter 38, for the pull of the Moon. It is within a day of
+// the minimum or maximum of the distance.
+// JDE = 2451547.507 + 365.2596358 * k + 0.0000000156 * k^2
+func meanApsis(year int, aphelion bool) time.Time {
+	k := math.Floor(0.99997*(float64(year)-2000.01) + 0.5)
+	if aphelion {
+		k += 0.5
+	}
+	jde := 2451547.507 + k*(365.2596358+0.0000000156*k)
+
+	// the Earth's displacement from the barycentre
+	A := 328.41 + 132.788585*k
+	B := 316.13 + 584.903153*k
+	C := 346.20 + 450.380738*k
+	D := 136.95 + 659.306737*k
+	E := 249.52 + 329.653368*k
+	if aphelion {
+		jde += -1.352*sind(A) + 0.061*sind(B) + 0.062*sind(C) + 0.029*sind(D) + 0.031*sind(E)
+	} else {
+		jde += 1.278*sind(A) - 0.055*sind(B) - 0.091*sind(C) - 0.056*sind(D) - 0.045*sind(E)
+	}
+
+	tt := time.Unix(0, 0).UTC().Add(time.Duration((jde - 2440587.5) * 86400 * float64(time.Second)))
+	return tt.Add(-sunevent.DeltaT(tt))
+}
+
+// apsis returns the extreme of the distance within three days of guess,
+// by golden section search.
+func apsis(guess time.Time, farthest bool) time.Time {
+	const phi = 0.6180339887498949
+	f := func(t time.Time) float64 {
+		_, _, r := Sun(t)
+		if farthest {
+			return -r
+		}
+		return r
+	}
+
+	lo, hi := guess.Add(-72*time.Hour), guess.Add(72*time.Hour)
+	step := func() time.Duration {
+		return time.Duration(float64(hi.Sub(lo)) * phi)
+	}
+	a, b := hi.Add(-step()), lo.Add(step())
+	fa, fb := f(a), f(b)
+	for hi.Sub(lo) > time.Second {
+		if fa < fb {
+			hi, b, fb = b, a, fa
+			a = hi.Add(-step())
+			fa = f(a)
+		} else {
+			lo, a, fa = a, b, fb
+			b = lo.Add(step())
+			fb = f(b)
+		}
+	}
+	return lo.Add(hi.Sub(lo) / 2).Round(time.Minute)
+}
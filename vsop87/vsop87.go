@@ -0,0 +1,148 @@
+// Package vsop87 computes the apparent position of the Sun to about an
+// arcsecond, following Meeus, Astronomical Algorithms, chapter 25: the
+// abridged VSOP87 theory of the Earth's motion, the FK5 frame correction,
+// nutation and aberration.
+//
+// It is far slower than the almanac formulas of package sunevent and is
+// meant for astronomy and eclipse work. The position functions of sunevent
+// use it when given the Ephemeris as an option:
+//
+//	p := sunevent.SunPosition(t, lat, lon, sunevent.WithEphemeris(vsop87.Ephemeris{}))
+package vsop87
+
+import (
+	"math"
+	"time"
+
+	"github.com/cfw011566/sunevent"
+	"github.com/cfw011566/sunevent/internal/meeus"
+)
+
+// Ephemeris supplies VSOP87 positions to the position functions of
+// package sunevent through sunevent.WithEphemeris.
+type Ephemeris struct {
+	// DeltaT, if set, replaces sunevent.DeltaT as the model of TT − UT.
+	DeltaT func(t time.Time) time.Duration
+}
+
+// SunGreenwich returns the Sun's apparent Greenwich hour angle and
+// declination in degrees at the instant t.
+func (e Ephemeris) SunGreenwich(t time.Time) (hourAngle, declination float64) {
+	c := apparent(t, e.deltaT(t))
+	return normalize(c.sidereal-c.ra, 360), c.dec
+}
+
+// deltaT returns TT - UT in seconds at t.
+func (e Ephemeris) deltaT(t time.Time) float64 {
+	if e.DeltaT == nil {
+		return sunevent.DeltaT(t).Seconds()
+	}
+	return e.DeltaT(t).Seconds()
+}
+
+// Sun returns the Sun's apparent geocentric right ascension in hours
+// [0, 24), declination in degrees and distance in astronomical units at
+// the instant t.
+func Sun(t time.Time) (ra, dec, distance float64) {
+	c := apparent(t, Ephemeris{}.deltaT(t))
+	return c.ra / 15.0, c.dec, c.distance
+}
+
+// coordinates are the apparent coordinates of the Sun, in degrees, and the
+// apparent sidereal time at Greenwich.
+type coordinates struct {
+	ra, dec, distance float64
+	sidereal          float64
+}
+
+// apparent returns the coordinates at the instant t, with TT - UT of dt
+// seconds.
+func apparent(t time.Time, dt float64) coordinates {
+	// days since J2000.0 in UT, from the Unix epoch to keep the precision
+	// of the fraction
+	d := float64(t.UnixNano())/86400e9 - 10957.5
+	de := d + dt/86400.0
+
+	T := de / 36525.0
+	tau := T / 10.0
+
+	L := series(earthL, tau)
+	B := series(earthB, tau)
+	R := series(earthR, tau)
+
+	// geocentric coordinates of the Sun
+	lambda := normalize(deg(L)+180.0, 360)
+	beta := -deg(B)
+
+	// conversion to the FK5 system
+	l1 := lambda - 1.397*T - 0.00031*T*T
+	lambda -= 0.09033 / 3600.0
+	beta += 0.03916 / 3600.0 * (cosd(l1) - sind(l1))
+
+	// nutation and aberration
+	dpsi, deps := meeus.Nutation(T)
+	lambda += dpsi - 20.4898/3600.0/R
+	eps := meanObliquity(T) + deps
+
+	ra := deg(math.Atan2(sind(lambda)*cosd(eps)-tand(beta)*sind(eps), cosd(lambda)))
+	dec := deg(math.Asin(sind(beta)*cosd(eps) + cosd(beta)*sind(eps)*sind(lambda)))
+
+	// apparent sidereal time, Meeus (12.4) corrected by the equation of
+	// the equinoxes
+	Tu := d / 36525.0
+	theta := 280.46061837 + 360.98564736629*d + 0.000387933*Tu*Tu - Tu*Tu*Tu/38710000.0
+	theta += dpsi * cosd(eps)
+
+	return coordinates{
+		ra:       normalize(ra, 360),
+		dec:      dec,
+		distance: R,
+		sidereal: normalize(theta, 360),
+	}
+}
+
+// series evaluates a VSOP87 coordinate in radians or au at tau, the Julian
+// millennia since J2000.0.
+func series(s [][]term, tau float64) float64 {
+	sum, power := 0.0, 1.0
+	for _, terms := range s {
+		v := 0.0
+		for _, t := range terms {
+			v += t.a * math.Cos(t.b+t.c*tau)
+		}
+		sum += v * power
+		power *= tau
+	}
+	return sum / 1e8
+}
+
+// meanObliquity returns the mean obliquity of the ecliptic in degrees
+// (Meeus 22.2).
+func meanObliquity(T float64) float64 {
+	return 23.0 + 26.0/60.0 + (21.448-46.8150*T-0.00059*T*T+0.001813*T*T*T)/3600.0
+}
+
+func deg(x float64) float64 {
+	return x * 180.0 / math.Pi
+}
+
+func sind(x float64) float64 {
+	return math.Sin(x * math.Pi / 180.0)
+}
+
+func cosd(x float64) float64 {
+	return math.Cos(x * math.Pi / 180.0)
+}
+
+func tand(x float64) float64 {
+	return math.Tan(x * math.Pi / 180.0)
+}
+
+// normalize wraps v into [0, max).
+func normalize(v, max float64) float64 {
+	v = math.Mod(v, max)
+	if v < 0 {
+		v += max
+	}
+	return v
+}
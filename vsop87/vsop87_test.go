@@ -0,0 +1,84 @@
+package vsop87
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/cfw011566/sunevent"
+)
+
+func TestApparent(t *testing.T) {
+	// Meeus, example 25.b: 1992 October 13 at 0h TD
+	c := apparent(time.Date(1992, time.October, 13, 0, 0, 0, 0, time.UTC), 0)
+	ra := 198.378178 // 13h13m30.749s
+	dec := -7.783872 // -7°47′01.74″
+	if math.Abs(c.ra-ra)*3600 > 1 || math.Abs(c.dec-dec)*3600 > 1 {
+		t.Errorf("RA %v°, dec %v°, want %v°, %v°", c.ra, c.dec, ra, dec)
+	}
+	if math.Abs(c.distance-0.99760775) > 1e-7 {
+		t.Errorf("distance %v AU, want 0.99760775", c.distance)
+	}
+}
+
+func TestSun(t *testing.T) {
+	// within the accuracy of the almanac formulas of package sunevent
+	at := time.Date(2026, time.June, 21, 3, 0, 0, 0, time.UTC)
+	ra, dec, distance := Sun(at)
+	wantRA, wantDec := sunevent.SunEquatorial(at)
+	if math.Abs(ra-wantRA) > 0.01/15 || math.Abs(dec-wantDec) > 0.01 {
+		t.Errorf("Sun = %vh, %v°, want about %vh, %v°", ra, dec, wantRA, wantDec)
+	}
+	if distance < 1.016 || distance > 1.017 {
+		t.Errorf("distance %v AU in June", distance)
+	}
+}
+
+func TestEphemeris(t *testing.T) {
+	at := time.Date(2026, time.June, 21, 3, 0, 0, 0, time.UTC)
+	const latitude, longitude = 22.63, 120.30
+	got := sunevent.SunPosition(at, latitude, longitude, sunevent.WithEphemeris(Ephemeris{}))
+	want := sunevent.SunPosition(at, latitude, longitude)
+	if math.Abs(got.Azimuth-want.Azimuth) > 0.02 || math.Abs(got.Altitude-want.Altitude) > 0.02 {
+		t.Errorf("position with the ephemeris = %+v, want about %+v", got, want)
+	}
+
+	// ΔT shifts the position by the Sun's motion in that time, about 0.04°
+	// an hour
+	late, _ := Ephemeris{DeltaT: func(time.Time) time.Duration { return time.Hour }}.SunGreenwich(at)
+	early, _ := Ephemeris{DeltaT: func(time.Time) time.Duration { return 0 }}.SunGreenwich(at)
+	if d := math.Abs(late - early); d < 0.035 || d > 0.05 {
+		t.Errorf("an hour of ΔT moves the hour angle by %v°", d)
+	}
+}
+
+func TestApsides(t *testing.T) {
+	utc := func(year int, month time.Month, day, hour, min int) time.Time {
+		return time.Date(year, month, day, hour, min, 0, 0, time.UTC)
+	}
+	// the instants published by the US Naval Observatory
+	tests := []struct {
+		perihelion, aphelion time.Time
+	}{
+		{utc(2020, time.January, 5, 7, 48), utc(2020, time.July, 4, 11, 35)},
+		{utc(2021, time.January, 2, 13, 51), utc(2021, time.July, 5, 22, 27)},
+		{utc(2022, time.January, 4, 6, 52), utc(2022, time.July, 4, 7, 11)},
+		{utc(2023, time.January, 4, 16, 17), utc(2023, time.July, 6, 20, 7)},
+		{utc(2024, time.January, 3, 0, 39), utc(2024, time.July, 5, 5, 6)},
+		{utc(2025, time.January, 4, 13, 28), utc(2025, time.July, 3, 19, 55)},
+		{utc(2026, time.January, 3, 17, 16), utc(2026, time.July, 6, 17, 31)},
+	}
+	within := func(got, want time.Time, tolerance time.Duration) bool {
+		d := got.Sub(want)
+		return d <= tolerance && d >= -tolerance
+	}
+	for _, tt := range tests {
+		year := tt.perihelion.Year()
+		if got := Perihelion(year); !within(got, tt.perihelion, 25*time.Minute) {
+			t.Errorf("Perihelion(%d) = %s, want %s", year, got, tt.perihelion)
+		}
+		if got := Aphelion(year); !within(got, tt.aphelion, 45*time.Minute) {
+			t.Errorf("Aphelion(%d) = %s, want %s", year, got, tt.aphelion)
+		}
+	}
+}
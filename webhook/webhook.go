@@ -0,0 +1,266 @@
+// Package webhook posts the solar events of a location to HTTP endpoints
+// as they happen, so that other services can react to sunset without
+// polling:
+//
+//	d := webhook.NewDispatcher(22.63, 120.30, []webhook.Hook{
+//		{URL: "https://example.com/hooks/sun", Events: []sunevent.EventType{sunevent.Sunset}, Secret: secret},
+//	})
+//	d.Run(ctx)
+//
+// Each delivery is a POST of a JSON payload:
+//
+//	{"event":"sunset","time":"2026-06-21T11:03:12Z","latitude":22.63,"longitude":120.3,"location":"home","delivery":"sunset-1782039792"}
+//
+// with the headers
+//
+//	X-Sunevent-Event      the event, as in the payload
+//	X-Sunevent-Delivery   an identifier of the occurrence, the same for every retry
+//	X-Sunevent-Signature  sha256= and the hex HMAC-SHA256 of the body keyed by the secret of the hook
+//
+// The signature is only sent for hooks with a secret; receivers check it
+// with Verify. Deliveries failing with a network error or a status of
+// 429 or 5xx are retried with exponential backoff.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/cfw011566/sunevent"
+)
+
+// Hook is an endpoint receiving events.
+type Hook struct {
+	URL string
+
+	// Events are the events posted to the hook; empty means every event.
+	Events []sunevent.EventType
+
+	// Secret, if not empty, keys the signature of the deliveries.
+	Secret string
+}
+
+func (h *Hook) wants(e sunevent.EventType) bool {
+	if len(h.Events) == 0 {
+		return true
+	}
+	for _, t := range h.Events {
+		if t == e {
+			return true
+		}
+	}
+	return false
+}
+
+// Dispatcher posts the events of one location to hooks.
+type Dispatcher struct {
+	latitude, longitude float64
+	hooks               []Hook
+
+	client   *http.Client
+	location string
+	retries  int
+	backoff  time.Duration
+	onError  func(Hook, sunevent.Event, error)
+}
+
+// Option configures a Dispatcher.
+type Option func(*Dispatcher)
+
+// WithHTTPClient sets the client of the deliveries, by default one with a
+// timeout of 30 seconds.
+func WithHTTPClient(c *http.Client) Option {
+	return func(d *Dispatcher) {
+		d.client = c
+	}
+}
+
+// WithLocation sets the location field of the payloads, by default empty
+// and left out.
+func WithLocation(name string) Option {
+	return func(d *Dispatcher) {
+		d.location = name
+	}
+}
+
+// WithRetries sets how many times a failed delivery is retried, by
+// default 3, and the wait before the first retry, by default a second,
+// which doubles for each retry.
+func WithRetries(n int, backoff time.Duration) Option {
+	return func(d *Dispatcher) {
+		d.retries, d.backoff = n, backoff
+	}
+}
+
+// WithErrorHandler sets the function called when a delivery fails for
+// good, by default one that logs the error.
+func WithErrorHandler(f func(h Hook, e sunevent.Event, err error)) Option {
+	return func(d *Dispatcher) {
+		d.onError = f
+	}
+}
+
+// NewDispatcher returns a dispatcher posting the events at the location
+// to the hooks.
+func NewDispatcher(latitude, longitude float64, hooks []Hook, opts ...Option) *Dispatcher {
+	d := &Dispatcher{
+		latitude:  latitude,
+		longitude: longitude,
+		hooks:     hooks,
+		client:    &http.Client{Timeout: 30 * time.Second},
+		retries:   3,
+		backoff:   time.Second,
+		onError: func(h Hook, e sunevent.Event, err error) {
+			log.Printf("sunevent/webhook: %s to %s: %v", e.Type, h.URL, err)
+		},
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// Run posts the events as they happen until ctx is done, and then waits
+// for the deliveries in progress, which are cancelled, and returns nil.
+// Each event is posted to every hook concurrently, so that a slow hook
+// does not delay the others.
+func (d *Dispatcher) Run(ctx context.Context) error {
+	var types []sunevent.EventType
+	for _, h := range d.hooks {
+		if len(h.Events) == 0 {
+			types = nil
+			break
+		}
+		types = append(types, h.Events...)
+	}
+
+	var wg sync.WaitGroup
+	for o := range sunevent.Subscribe(ctx, d.latitude, d.longitude, types...) {
+		for _, h := range d.hooks {
+			if !h.wants(o.Type) {
+				continue
+			}
+			wg.Add(1)
+			go func(h Hook, e sunevent.Event) {
+				defer wg.Done()
+				if err := d.post(ctx, h, e); err != nil && ctx.Err() == nil {
+					d.onError(h, e, err)
+				}
+			}(h, o.Event)
+		}
+	}
+	wg.Wait()
+	return nil
+}
+
+// Deliver posts the event e to the hooks that want it now, as Run would,
+// and returns the first error. It lets a hook be tested without waiting
+// for the event.
+func (d *Dispatcher) Deliver(ctx context.Context, e sunevent.Event) error {
+	var first error
+	for _, h := range d.hooks {
+		if !h.wants(e.Type) {
+			continue
+		}
+		if err := d.post(ctx, h, e); err != nil && first == nil {
+			first = fmt.Errorf("sunevent/webhook: %s: %v", h.URL, err)
+		}
+	}
+	return first
+}
+
+// payload is the body of a delivery.
+type payload struct {
+	Event     string    `json:"event"`
+	Time      time.Time `json:"time"`
+	Latitude  float64   `json:"latitude"`
+	Longitude float64   `json:"longitude"`
+	Location  string    `json:"location,omitempty"`
+	Delivery  string    `json:"delivery"`
+}
+
+// post delivers e to h, retrying failures that may be temporary.
+func (d *Dispatcher) post(ctx context.Context, h Hook, e sunevent.Event) error {
+	p := payload{
+		Event:     e.Type.String(),
+		Time:      e.Time.UTC().Round(time.Second),
+		Latitude:  d.latitude,
+		Longitude: d.longitude,
+		Location:  d.location,
+	}
+	p.Delivery = fmt.Sprintf("%s-%d", p.Event, p.Time.Unix())
+	body, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	wait := d.backoff
+	for attempt := 0; ; attempt++ {
+		retry, err := d.send(ctx, h, p, body)
+		if err == nil || !retry || attempt >= d.retries {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+}
+
+// send makes one attempt at a delivery and reports whether a failure is
+// worth retrying.
+func (d *Dispatcher) send(ctx context.Context, h Hook, p payload, body []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, h.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "sunevent-webhook")
+	req.Header.Set("X-Sunevent-Event", p.Event)
+	req.Header.Set("X-Sunevent-Delivery", p.Delivery)
+	if h.Secret != "" {
+		req.Header.Set("X-Sunevent-Signature", Sign([]byte(h.Secret), body))
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 1<<16))
+	resp.Body.Close()
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return true, fmt.Errorf("%s", resp.Status)
+	}
+	return false, fmt.Errorf("%s", resp.Status)
+}
+
+// Sign returns the value of the X-Sunevent-Signature header of a delivery
+// of body signed with secret.
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature, the X-Sunevent-Signature header of a
+// delivery, is that of body signed with secret.
+func Verify(secret, body []byte, signature string) bool {
+	return hmac.Equal([]byte(signature), []byte(Sign(secret, body)))
+}
@@ -0,0 +1,97 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cfw011566/sunevent"
+)
+
+var sunset = sunevent.Event{Type: sunevent.Sunset, Time: time.Date(2026, time.June, 21, 19, 3, 12, 400e6, time.FixedZone("CST", 8*3600))}
+
+func TestDeliver(t *testing.T) {
+	var got *http.Request
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		body, _ = ioutil.ReadAll(r.Body)
+	}))
+	defer srv.Close()
+
+	d := NewDispatcher(22.63, 120.30, []Hook{
+		{URL: srv.URL, Events: []sunevent.EventType{sunevent.Sunset}, Secret: "s3cret"},
+		{URL: srv.URL + "/sunrise", Events: []sunevent.EventType{sunevent.Sunrise}},
+	}, WithLocation("home"))
+	if err := d.Deliver(context.Background(), sunset); err != nil {
+		t.Fatal(err)
+	}
+	if got == nil || got.URL.Path != "/" {
+		t.Fatalf("delivered to %v, want only the sunset hook", got)
+	}
+
+	var p payload
+	if err := json.Unmarshal(body, &p); err != nil {
+		t.Fatal(err)
+	}
+	want := payload{
+		Event:     "sunset",
+		Time:      time.Date(2026, time.June, 21, 11, 3, 12, 0, time.UTC),
+		Latitude:  22.63,
+		Longitude: 120.30,
+		Location:  "home",
+		Delivery:  "sunset-1782039792",
+	}
+	if p != want {
+		t.Errorf("payload %s, want %+v", body, want)
+	}
+	if got.Header.Get("X-Sunevent-Event") != "sunset" || got.Header.Get("X-Sunevent-Delivery") != want.Delivery {
+		t.Errorf("headers %v", got.Header)
+	}
+	signature := got.Header.Get("X-Sunevent-Signature")
+	if !Verify([]byte("s3cret"), body, signature) {
+		t.Errorf("signature %q does not verify", signature)
+	}
+	if Verify([]byte("guess"), body, signature) || Verify([]byte("s3cret"), append(body, ' '), signature) {
+		t.Error("Verify accepted a wrong secret or body")
+	}
+}
+
+func TestRetries(t *testing.T) {
+	var mu sync.Mutex
+	var attempts []int
+	status := []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		code := status[len(attempts)%len(status)]
+		attempts = append(attempts, code)
+		w.WriteHeader(code)
+	}))
+	defer srv.Close()
+
+	// fails twice, then succeeds
+	d := NewDispatcher(0, 0, []Hook{{URL: srv.URL}}, WithRetries(2, time.Millisecond))
+	if err := d.Deliver(context.Background(), sunset); err != nil || len(attempts) != 3 {
+		t.Errorf("Deliver: %v after %v", err, attempts)
+	}
+
+	// a client error is not retried
+	attempts = nil
+	status = []int{http.StatusBadRequest}
+	if err := d.Deliver(context.Background(), sunset); err == nil || len(attempts) != 1 {
+		t.Errorf("Deliver: %v after %v, want one failed attempt", err, attempts)
+	}
+
+	// nor a server error beyond the retries
+	attempts = nil
+	status = []int{http.StatusInternalServerError}
+	if err := d.Deliver(context.Background(), sunset); err == nil || len(attempts) != 3 {
+		t.Errorf("Deliver: %v after %v, want three failed attempts", err, attempts)
+	}
+}
@@ -0,0 +1,72 @@
+package sunevent
+
+import (
+	"time"
+
+	"github.com/cfw011566/sunevent/internal/meeus"
+)
+
+// ZodiacSign is a 30° sign of the tropical zodiac, counted along the
+// ecliptic from the March equinox.
+type ZodiacSign int
+
+const (
+	Aries ZodiacSign = iota
+	Taurus
+	Gemini
+	Cancer
+	Leo
+	Virgo
+	Libra
+	Scorpio
+	Sagittarius
+	Capricorn
+	Aquarius
+	Pisces
+)
+
+var zodiacSignNames = [...]string{
+	Aries:       "aries",
+	Taurus:      "taurus",
+	Gemini:      "gemini",
+	Cancer:      "cancer",
+	Leo:         "leo",
+	Virgo:       "virgo",
+	Libra:       "libra",
+	Scorpio:     "scorpio",
+	Sagittarius: "sagittarius",
+	Capricorn:   "capricorn",
+	Aquarius:    "aquarius",
+	Pisces:      "pisces",
+}
+
+func (z ZodiacSign) String() string {
+	if z < 0 || int(z) >= len(zodiacSignNames) {
+		return "unknown"
+	}
+	return zodiacSignNames[z]
+}
+
+// ZodiacSignAt returns the sign of the tropical zodiac that contains the
+// ecliptic longitude in degrees. Sidereal zodiacs, used by some gardening
+// calendars, are shifted by the ayanamsa, about 24° in the present era;
+// subtract it from the longitude first.
+func ZodiacSignAt(longitude float64) ZodiacSign {
+	return ZodiacSign(int(normalizeRange(longitude, 360) / 30.0))
+}
+
+// MoonLongitude returns the apparent geocentric ecliptic longitude of the
+// Moon in degrees [0, 360) at the instant t, referred to the true equinox
+// of date. It advances by about 13° a day.
+func MoonLongitude(t time.Time) float64 {
+	T := Options{}.julianCentury(t)
+	lambda, _, _ := moonEcliptic(T)
+	dpsi, _ := meeus.Nutation(T)
+	return normalizeRange(lambda+dpsi, 360)
+}
+
+// MoonSign returns the sign of the tropical zodiac the Moon is in at the
+// instant t.
+func MoonSign(t time.Time) ZodiacSign {
+	return ZodiacSignAt(MoonLongitude(t))
+}
@@ -0,0 +1,48 @@
+package sunevent
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMoonLongitude(t *testing.T) {
+	// Meeus, Astronomical Algorithms, example 47.a: the apparent longitude
+	// at 1992 April 12, 0h TD, which was 59 s after 0h UT
+	at := time.Date(1992, time.April, 11, 23, 59, 1, 0, time.UTC)
+	if lambda := MoonLongitude(at); !near(lambda, 133.167265, 0.003) {
+		t.Errorf("MoonLongitude(%s) = %v°, want 133.167265°", at, lambda)
+	}
+	if sign := MoonSign(at); sign != Leo {
+		t.Errorf("MoonSign(%s) = %v, want leo", at, sign)
+	}
+
+	// the Moon goes round the zodiac in 27.3 days, about 13° a day
+	day := MoonLongitude(at.Add(24*time.Hour)) - MoonLongitude(at)
+	if day < 11.5 || day > 15.5 {
+		t.Errorf("the Moon moved %v° in a day", day)
+	}
+}
+
+func TestZodiacSignAt(t *testing.T) {
+	tests := []struct {
+		longitude float64
+		want      ZodiacSign
+	}{
+		{0, Aries},
+		{29.99, Aries},
+		{30, Taurus},
+		{133.17, Leo},
+		{359.9, Pisces},
+		{-15, Pisces},
+		{360, Aries},
+		{725, Aries},
+	}
+	for _, tt := range tests {
+		if got := ZodiacSignAt(tt.longitude); got != tt.want {
+			t.Errorf("ZodiacSignAt(%v) = %v, want %v", tt.longitude, got, tt.want)
+		}
+	}
+	if s := ZodiacSign(12).String(); s != "unknown" {
+		t.Errorf("ZodiacSign(12).String() = %q", s)
+	}
+}